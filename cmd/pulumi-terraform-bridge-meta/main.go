@@ -0,0 +1,307 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command pulumi-terraform-bridge-meta decodes and repairs the Terraform metadata that bridged providers record in
+// Pulumi state. It operates on the output of `pulumi stack export`; repaired state can be written back with
+// `pulumi stack import`.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func main() {
+	cmd := &cobra.Command{
+		Use:   os.Args[0],
+		Short: "Decode and repair the Terraform metadata of bridged resources",
+		Long: "Decode and repair the Terraform metadata of bridged resources.\n" +
+			"\n" +
+			"Terraform providers keep private state alongside each resource, such as the resource's schema\n" +
+			"version and its operation timeouts. Bridged providers record this state in the resource's\n" +
+			"outputs under the " + tfbridge.ResourceMetaKey + " key. These commands read the output of\n" +
+			"`pulumi stack export` so that the metadata can be inspected and fixed without hand-editing it.\n",
+	}
+	cmd.AddCommand(newDecodeCmd(), newSetCmd())
+
+	if err := cmd.Execute(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "An error occurred: %v\n", err)
+		os.Exit(-1)
+	}
+}
+
+func newDecodeCmd() *cobra.Command {
+	var urn string
+	cmd := &cobra.Command{
+		Use:   "decode <STATE-FILE>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Print the decoded metadata of each bridged resource in an exported stack",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			deployment, err := readDeployment(args[0])
+			if err != nil {
+				return err
+			}
+
+			decoded := map[string]interface{}{}
+			err = forEachResource(deployment, urn, func(urn string, outputs map[string]interface{}) error {
+				meta, _, err := getMeta(outputs)
+				if err != nil || meta == nil {
+					return err
+				}
+				decoded[urn] = newDecodedMeta(meta)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return writeJSON(os.Stdout, decoded)
+		}),
+	}
+
+	cmd.Flags().StringVar(&urn, "urn", "", "only decode the metadata of the resource with this URN")
+	return cmd
+}
+
+func newSetCmd() *cobra.Command {
+	var urn string
+	var schemaVersion int
+	var timeouts []string
+	var out string
+	cmd := &cobra.Command{
+		Use:   "set <STATE-FILE>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Change the metadata of a bridged resource in an exported stack",
+		Long: "Change the metadata of a bridged resource in an exported stack.\n" +
+			"\n" +
+			"The updated stack is written to standard output, or to the file given by --out, and can be\n" +
+			"imported with `pulumi stack import`.\n",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			if urn == "" {
+				return fmt.Errorf("--urn is required")
+			}
+
+			newTimeouts := map[string]*time.Duration{}
+			for _, t := range timeouts {
+				op, value, ok := strings.Cut(t, "=")
+				if !ok {
+					return fmt.Errorf("invalid timeout %q: expected <OPERATION>=<DURATION>", t)
+				}
+				if value == "" {
+					newTimeouts[op] = nil
+					continue
+				}
+				d, err := time.ParseDuration(value)
+				if err != nil {
+					return fmt.Errorf("invalid timeout %q: %w", t, err)
+				}
+				newTimeouts[op] = &d
+			}
+
+			deployment, err := readDeployment(args[0])
+			if err != nil {
+				return err
+			}
+
+			found := false
+			err = forEachResource(deployment, urn, func(_ string, outputs map[string]interface{}) error {
+				found = true
+
+				meta, secret, err := getMeta(outputs)
+				if err != nil {
+					return err
+				}
+				if meta == nil {
+					meta = &tfbridge.ResourceMeta{}
+				}
+				if cmd.Flags().Changed("schema-version") {
+					meta.SchemaVersion = &schemaVersion
+				}
+				for op, d := range newTimeouts {
+					if d == nil {
+						delete(meta.Timeouts, op)
+						continue
+					}
+					if meta.Timeouts == nil {
+						meta.Timeouts = map[string]time.Duration{}
+					}
+					meta.Timeouts[op] = *d
+				}
+				return setMeta(outputs, meta, secret)
+			})
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("no resource with URN %q", urn)
+			}
+
+			if out == "" {
+				return writeJSON(os.Stdout, deployment)
+			}
+			f, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			if err := writeJSON(f, deployment); err != nil {
+				contract.IgnoreClose(f)
+				return err
+			}
+			return f.Close()
+		}),
+	}
+
+	cmd.Flags().StringVar(&urn, "urn", "", "the URN of the resource to change")
+	cmd.Flags().IntVar(&schemaVersion, "schema-version", 0, "the schema version to record")
+	cmd.Flags().StringArrayVar(&timeouts, "timeout", nil,
+		"a timeout to record, in the form <OPERATION>=<DURATION> (e.g. create=30m); an empty duration removes it")
+	cmd.Flags().StringVarP(&out, "out", "o", "", "the file to write the updated stack to")
+	return cmd
+}
+
+// readDeployment reads the output of `pulumi stack export`. The deployment is decoded generically so that fields
+// this command does not know about are preserved when it is written back out.
+func readDeployment(path string) (map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(f)
+
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	var deployment map[string]interface{}
+	if err := dec.Decode(&deployment); err != nil {
+		return nil, fmt.Errorf("reading %v: %w", path, err)
+	}
+	return deployment, nil
+}
+
+// forEachResource calls the given function with the URN and outputs of each resource in the deployment. If urn is
+// not empty, only the matching resource is visited.
+func forEachResource(deployment map[string]interface{}, urn string,
+	f func(urn string, outputs map[string]interface{}) error) error {
+
+	inner, ok := deployment["deployment"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("the state file is not the output of `pulumi stack export`")
+	}
+	resources, _ := inner["resources"].([]interface{})
+	for _, r := range resources {
+		res, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		resURN, _ := res["urn"].(string)
+		if urn != "" && resURN != urn {
+			continue
+		}
+		outputs, ok := res["outputs"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := f(resURN, outputs); err != nil {
+			return fmt.Errorf("%v: %w", resURN, err)
+		}
+	}
+	return nil
+}
+
+// decodedMeta is the human-readable form of a resource's metadata that the decode command prints.
+type decodedMeta struct {
+	SchemaVersion *int                   `json:"schemaVersion,omitempty"`
+	Timeouts      map[string]string      `json:"timeouts,omitempty"`
+	Private       map[string]interface{} `json:"private,omitempty"`
+}
+
+func newDecodedMeta(meta *tfbridge.ResourceMeta) decodedMeta {
+	decoded := decodedMeta{SchemaVersion: meta.SchemaVersion, Private: meta.Private}
+	if len(meta.Timeouts) != 0 {
+		decoded.Timeouts = make(map[string]string, len(meta.Timeouts))
+		for op, t := range meta.Timeouts {
+			decoded.Timeouts[op] = t.String()
+		}
+	}
+	return decoded
+}
+
+// getMeta returns the metadata recorded in a resource's outputs, if any, and whether it is a secret. Secret
+// metadata can only be read from a stack that was exported with --show-secrets.
+func getMeta(outputs map[string]interface{}) (*tfbridge.ResourceMeta, bool, error) {
+	switch v := outputs[tfbridge.ResourceMetaKey].(type) {
+	case string:
+		meta, err := tfbridge.ParseResourceMeta(v)
+		return meta, false, err
+	case map[string]interface{}:
+		if v[resource.SigKey] != resource.SecretSig {
+			return nil, false, nil
+		}
+		plaintext, ok := v["plaintext"].(string)
+		if !ok {
+			return nil, true, fmt.Errorf("the metadata is encrypted; export the stack with --show-secrets")
+		}
+		var s string
+		if err := json.Unmarshal([]byte(plaintext), &s); err != nil {
+			return nil, true, fmt.Errorf("decoding secret metadata: %w", err)
+		}
+		meta, err := tfbridge.ParseResourceMeta(s)
+		return meta, true, err
+	default:
+		return nil, false, nil
+	}
+}
+
+// setMeta records metadata in a resource's outputs. Secret metadata is recorded in plaintext, which
+// `pulumi stack import` encrypts.
+func setMeta(outputs map[string]interface{}, meta *tfbridge.ResourceMeta, secret bool) error {
+	if meta.IsEmpty() {
+		delete(outputs, tfbridge.ResourceMetaKey)
+		return nil
+	}
+	bytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if !secret {
+		outputs[tfbridge.ResourceMetaKey] = string(bytes)
+		return nil
+	}
+
+	plaintext, err := json.Marshal(string(bytes))
+	if err != nil {
+		return err
+	}
+	outputs[tfbridge.ResourceMetaKey] = map[string]interface{}{
+		resource.SigKey: resource.SecretSig,
+		"plaintext":     string(plaintext),
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	return enc.Encode(v)
+}
@@ -19,6 +19,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/spf13/afero"
@@ -70,6 +71,12 @@ func Convert(opts Options) (map[string][]byte, Diagnostics, error) {
 			return nil, Diagnostics{}, err
 		}
 		opts.Root = afero.NewBasePathFs(afero.NewOsFs(), cwd)
+		if opts.ProjectName == "" {
+			opts.ProjectName = filepath.Base(cwd)
+		}
+	}
+	if opts.ProviderInfoSource == nil {
+		opts.ProviderInfoSource = il.NewProviderInfoSourceFromEnv()
 	}
 	if opts.ProviderInfoSource == nil {
 		opts.ProviderInfoSource = il.PluginProviderInfoSource
@@ -110,7 +117,7 @@ func Convert(opts Options) (map[string][]byte, Diagnostics, error) {
 		}
 	}
 
-	tf12Files, program, programDiags, err := convertTF12(tf12Files, opts)
+	tf12Files, program, stackConfigs, programDiags, err := convertTF12(tf12Files, opts)
 	if err != nil {
 		return nil, Diagnostics{}, err
 	}
@@ -153,6 +160,10 @@ func Convert(opts Options) (map[string][]byte, Diagnostics, error) {
 		return nil, Diagnostics{All: diagnostics, files: tf12Files}, nil
 	}
 
+	for name, contents := range stackConfigs {
+		generatedFiles[name] = contents
+	}
+
 	return generatedFiles, Diagnostics{All: diagnostics, files: tf12Files}, nil
 }
 
@@ -176,21 +187,47 @@ type Options struct {
 	// ResourceNameProperty sets the key of the resource name property that will be removed if FilterResourceNames is
 	// true.
 	ResourceNameProperty string
+	// LocalCommandToken is the token of the resource that local-exec provisioners and null_resources are converted
+	// into. Defaults to "command:local:Command".
+	LocalCommandToken string
+	// RemoteCommandToken is the token of the resource that remote-exec provisioners are converted into. Defaults to
+	// "command:remote:Command".
+	RemoteCommandToken string
+	// CopyFileToken is the token of the resource that file provisioners are converted into. Defaults to
+	// "command:remote:CopyFile".
+	CopyFileToken string
+	// RemoteStateStack returns the name of the Pulumi stack that replaces the state read by a terraform_remote_state
+	// data source with the given backend, workspace, and config. Nested config keys are joined with "."; only literal
+	// values are included. If RemoteStateStack is nil or returns false, the stacks that replace Terraform Cloud
+	// workspaces are named "<organization>/<workspace>", and other stacks are named after the data source.
+	RemoteStateStack func(backend, workspace string, config map[string]string) (string, bool)
 	// Root, when set, overrides the default filesystem used to load the source Terraform module.
 	Root afero.Fs
+	// TFVars maps the names of stacks to the tfvars files, relative to Root, whose values make up each stack's config.
+	// Later files take precedence. If TFVars is nil, terraform.tfvars and *.auto.tfvars make up the config of the
+	// "default" stack, and every other *.tfvars file adds to these the config of the stack named after the file. The
+	// config of each stack is written to Pulumi.<stack>.yaml.
+	TFVars map[string][]string
+	// ProjectName is the name of the Pulumi project, which namespaces the keys of generated stack config. Defaults to
+	// the name of the working directory if Root is not set. If Root is set and ProjectName is empty, tfvars files are
+	// skipped with a warning.
+	ProjectName string
 	// Optional package cache.
 	PackageCache *pcl.PackageCache
 	// Optional plugin host.
 	PluginHost plugin.Host
 	// Optional Loader.
 	Loader schema.Loader
-	// Optional source for provider schema information.
+	// Optional source for provider schema information. Defaults to the directory named by PULUMI_PROVIDER_INFO_DIR if
+	// set, or else to the Pulumi resource provider plugins.
 	ProviderInfoSource il.ProviderInfoSource
 	// Optional logger for diagnostic information.
 	Logger *log.Logger
 	// SkipResourceTypechecking, if true, allows code-gen to continue even if resource inputs fail to typecheck.
 	SkipResourceTypechecking bool
-	// The target language.
+	// The target language. Local modules are converted to components and terraform_remote_state data sources are
+	// converted to stack references, both of which can only be converted to PCL; converting either to any other
+	// language fails.
 	TargetLanguage string
 	// The target SDK version.
 	TargetSDKVersion string
@@ -38,8 +38,13 @@ func parseFile(parser *syntax.Parser, fs afero.Fs, path string) error {
 
 // parseTF12 parses a TF12 config.
 func parseTF12(opts Options) ([]*syntax.File, hcl.Diagnostics) {
+	return parseTF12Dir(opts.Root, "/")
+}
+
+// parseTF12Dir parses the TF12 config in the given directory of the given filesystem.
+func parseTF12Dir(fs afero.Fs, dir string) ([]*syntax.File, hcl.Diagnostics) {
 	// Find the config files in the requested directory.
-	configs, overrides, diags := configs.NewParser(opts.Root).ConfigDirFiles("/")
+	configs, overrides, diags := configs.NewParser(fs).ConfigDirFiles(dir)
 	if diags.HasErrors() {
 		return nil, diags
 	}
@@ -63,7 +68,7 @@ func parseTF12(opts Options) ([]*syntax.File, hcl.Diagnostics) {
 	// Parse the config.
 	parser := syntax.NewParser()
 	for _, config := range configs {
-		if err := parseFile(parser, opts.Root, config); err != nil {
+		if err := parseFile(parser, fs, config); err != nil {
 			return nil, hcl.Diagnostics{{
 				Severity: hcl.DiagError,
 				Summary:  fmt.Sprintf("failed to parse file %s", config),
@@ -74,7 +79,9 @@ func parseTF12(opts Options) ([]*syntax.File, hcl.Diagnostics) {
 	return parser.Files, parser.Diagnostics
 }
 
-func convertTF12(files []*syntax.File, opts Options) ([]*syntax.File, *pcl.Program, hcl.Diagnostics, error) {
+func convertTF12(files []*syntax.File, opts Options) ([]*syntax.File, *pcl.Program, map[string][]byte,
+	hcl.Diagnostics, error) {
+
 	var hcl2Options []model.BindOption
 	var pulumiOptions []pcl.BindOption
 	if opts.AllowMissingProperties {
@@ -98,17 +105,59 @@ func convertTF12(files []*syntax.File, opts Options) ([]*syntax.File, *pcl.Progr
 	}
 
 	// Bind the files into a module.
+	binder := newTF12Binder(files, opts, hcl2Options, pulumiOptions, "/", map[string]*component{})
+	declaredFiles, pulumiFiles, diagnostics := binder.convertFiles(files)
+	stackConfigs, stackDiags := binder.genStackConfigs(declaredFiles)
+	diagnostics = append(diagnostics, stackDiags...)
+
+	// Programs that contain constructs the PCL binder does not yet support cannot be bound. These constructs are
+	// only generated when converting to PCL, which does not need a bound program. Programs that failed to convert are
+	// not bound either, as binding them would only report the same errors against the generated code.
+	var program *pcl.Program
+	var err error
+	switch {
+	case diagnostics.HasErrors():
+	case len(binder.unboundConstructs) == 0:
+		var programDiags hcl.Diagnostics
+		program, programDiags, err = pcl.BindProgram(pulumiFiles, pulumiOptions...)
+		diagnostics = append(diagnostics, programDiags...)
+	default:
+		diagnostics = append(diagnostics, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "the converted program has not been checked",
+			Detail: fmt.Sprintf("the program contains %v, which the PCL binder does not yet support",
+				strings.Join(binder.unboundConstructs.SortedValues(), " and ")),
+		})
+	}
+
+	// Append the programs for any components to the output.
+	for _, dir := range codegen.SortedKeys(binder.components) {
+		pulumiFiles = append(pulumiFiles, binder.components[dir].files...)
+	}
+
+	return pulumiFiles, program, stackConfigs, diagnostics, err
+}
+
+func newTF12Binder(files []*syntax.File, opts Options, hcl2Options []model.BindOption,
+	pulumiOptions []pcl.BindOption, dir string, components map[string]*component) *tf12binder {
+
 	binder := &tf12binder{
+		opts:                opts,
+		dir:                 dir,
 		hcl2Options:         hcl2Options,
 		pulumiOptions:       pulumiOptions,
 		filterResourceNames: opts.FilterResourceNames,
 		providerInfo:        opts.ProviderInfoSource,
 		providers:           map[string]*tfbridge.ProviderInfo{},
+		components:          components,
+		unboundConstructs:   codegen.StringSet{},
 		binding:             codegen.Set{},
 		bound:               codegen.Set{},
 		conditionals:        newConditionalAnalyzer(),
 		exprToSchemas:       map[model.Expression]il.Schemas{},
 		variableToSchemas:   map[model.Definition](func() il.Schemas){},
+		dynamicBlocks:       map[*hclsyntax.Block]*dynamicBlock{},
+		dynamicIterators:    map[*model.Variable]*dynamicBlock{},
 		tokens:              syntax.NewTokenMapForFiles(files),
 		root:                model.NewRootScope(syntax.None),
 		providerScope:       model.NewRootScope(syntax.None),
@@ -118,6 +167,8 @@ func convertTF12(files []*syntax.File, opts Options) ([]*syntax.File, *pcl.Progr
 	binder.root.DefineScope("data", syntax.None)
 	binder.root.DefineScope("var", syntax.None)
 	binder.root.DefineScope("local", syntax.None)
+	binder.root.DefineScope("module", syntax.None)
+	binder.defineWorkspace()
 
 	// Define null.
 	binder.root.Define("null", &model.Constant{
@@ -129,24 +180,37 @@ func convertTF12(files []*syntax.File, opts Options) ([]*syntax.File, *pcl.Progr
 	for name, fn := range tf12builtins {
 		binder.root.DefineFunction(name, fn)
 	}
+	for name, fn := range tf12Functions {
+		if _, ok := tf12builtins[name]; !ok {
+			binder.root.DefineFunction(name, fn.function())
+		}
+	}
+	for _, name := range tf12UnsupportedFunctions {
+		binder.root.DefineFunction(name, unsupportedFunction())
+	}
+
+	return binder
+}
 
+// convertFiles converts the given TF12 files into Pulumi HCL2 files.
+func (b *tf12binder) convertFiles(files []*syntax.File) ([]*file, []*syntax.File, hcl.Diagnostics) {
 	var diagnostics hcl.Diagnostics
 
 	declaredFiles := make([]*file, len(files))
 	for i, file := range files {
-		f, declareDiags := binder.declareFile(file)
+		f, declareDiags := b.declareFile(file)
 		declaredFiles[i], diagnostics = f, append(diagnostics, declareDiags...)
 	}
 
 	for _, file := range declaredFiles {
-		bindDiags := binder.bindFile(file)
+		bindDiags := b.bindFile(file)
 		diagnostics = append(diagnostics, bindDiags...)
 	}
 
 	// Convert the module into a Pulumi HCL2 program.
 	assignNames(declaredFiles)
 	for _, file := range declaredFiles {
-		genDiags := binder.genFile(file)
+		genDiags := b.genFile(file)
 		diagnostics = append(diagnostics, genDiags...)
 	}
 
@@ -159,26 +223,29 @@ func convertTF12(files []*syntax.File, opts Options) ([]*syntax.File, *pcl.Progr
 		file.output.Reset()
 
 		if pulumiParser.Diagnostics.HasErrors() {
-			opts.logf("%v", contents)
-			opts.logf("%v", diagnostics)
-			opts.logf("%v", pulumiParser.Diagnostics)
+			b.opts.logf("%v", contents)
+			b.opts.logf("%v", diagnostics)
+			b.opts.logf("%v", pulumiParser.Diagnostics)
 			contract.Fail()
 		}
 	}
 
-	program, programDiags, err := pcl.BindProgram(pulumiParser.Files, pulumiOptions...)
-	diagnostics = append(diagnostics, programDiags...)
-
-	return pulumiParser.Files, program, diagnostics, err
+	return declaredFiles, pulumiParser.Files, diagnostics
 }
 
 type tf12binder struct {
+	opts                Options
+	dir                 string // the directory of the module being converted.
 	pulumiOptions       []pcl.BindOption
 	hcl2Options         []model.BindOption
 	filterResourceNames bool
 	providerInfo        il.ProviderInfoSource
 
-	providers map[string]*tfbridge.ProviderInfo
+	providers  map[string]*tfbridge.ProviderInfo
+	components map[string]*component // the components converted from local modules, keyed by directory.
+
+	// unboundConstructs names the constructs in the converted program that the PCL binder does not yet support.
+	unboundConstructs codegen.StringSet
 
 	binding codegen.Set
 	bound   codegen.Set
@@ -186,6 +253,10 @@ type tf12binder struct {
 	conditionals      *conditionalAnalyzer
 	exprToSchemas     map[model.Expression]il.Schemas
 	variableToSchemas map[model.Definition](func() il.Schemas)
+	dynamicBlocks     map[*hclsyntax.Block]*dynamicBlock
+	dynamicIterators  map[*model.Variable]*dynamicBlock
+	validating        bool            // true while the validation rules of a variable are rewritten.
+	workspace         *model.Variable // the definition of terraform.workspace.
 	tokens            syntax.TokenMap
 	root              *model.Scope
 	providerScope     *model.Scope
@@ -218,6 +289,8 @@ type variable struct {
 	name          string
 	pulumiName    string
 	terraformType model.Type
+	sensitive     bool // true if the variable's value is sensitive.
+	nullable      bool // true if the variable may be null.
 
 	block *model.Block
 }
@@ -297,14 +370,17 @@ func (o *output) SyntaxNode() hclsyntax.Node {
 	return o.syntax
 }
 
-// nolint: structcheck, unused
 type module struct {
 	syntax *hclsyntax.Block
 
 	name          string
 	pulumiName    string
-	pulumiType    model.Type
+	source        string
+	component     *component
+	schemas       il.Schemas
 	terraformType model.Type
+	variableType  model.Type
+	rangeVariable *model.Variable
 
 	block *model.Block
 }
@@ -314,7 +390,11 @@ func (m *module) SyntaxNode() hclsyntax.Node {
 }
 
 func (m *module) Traverse(traverser hcl.Traverser) (model.Traversable, hcl.Diagnostics) {
-	return m.terraformType.Traverse(traverser)
+	return m.variableType.Traverse(traverser)
+}
+
+func (m *module) Type() model.Type {
+	return m.variableType
 }
 
 type resource struct {
@@ -329,6 +409,7 @@ type resource struct {
 	terraformType model.Type
 	variableType  model.Type
 	rangeVariable *model.Variable
+	selfVariable  *model.Variable
 	isCounted     bool
 	isConditional bool
 
@@ -405,8 +486,14 @@ func (b *tf12binder) declareFile(input *syntax.File) (*file, hcl.Diagnostics) {
 					name:   item.Labels[0],
 				}
 				file.nodes = append(file.nodes, o)
-				//			case "module":
-				//				// TODO(pdg): module instances
+			case "module":
+				m := &module{
+					syntax: item,
+					name:   item.Labels[0],
+				}
+				scopeDef, _ := b.root.BindReference("module")
+				scopeDef.(*model.Scope).Define(m.name, m)
+				file.nodes = append(file.nodes, m)
 			case "resource", "data":
 				isDataSource := item.Type == "data"
 
@@ -416,8 +503,22 @@ func (b *tf12binder) declareFile(input *syntax.File) (*file, hcl.Diagnostics) {
 				}
 
 				addr := addrs.Resource{Mode: mode, Type: item.Labels[0], Name: item.Labels[1]}
-				token, schemas, terraformType, typeDiags := b.resourceType(addr, item.LabelRanges[0])
-				diagnostics = append(diagnostics, typeDiags...)
+
+				var token string
+				var schemas il.Schemas
+				var terraformType model.Type
+				switch {
+				case !isDataSource && addr.Type == "null_resource":
+					// null_resources are converted into their provisioners.
+					terraformType = nullResourceType()
+				case isDataSource && addr.Type == "terraform_remote_state":
+					// terraform_remote_state data sources are converted into stack references.
+					token, terraformType = stackReferenceToken, remoteStateType()
+				default:
+					var typeDiags hcl.Diagnostics
+					token, schemas, terraformType, typeDiags = b.resourceType(addr, item.LabelRanges[0])
+					diagnostics = append(diagnostics, typeDiags...)
+				}
 
 				variableType := terraformType
 				_, hasCount := item.Body.Attributes["count"]
@@ -497,9 +598,10 @@ func (b *tf12binder) bindNode(node tf12Node) hcl.Diagnostics {
 			}
 			definition, _ = scope.BindReference(key.AsString())
 		}
-		if node, ok := definition.(tf12Node); ok && !depSet.Has(node) {
-			depSet.Add(node)
-			deps = append(deps, node)
+		// Validation rules may refer to the variable they validate.
+		if dep, ok := definition.(tf12Node); ok && dep != node && !depSet.Has(dep) {
+			depSet.Add(dep)
+			deps = append(deps, dep)
 		}
 		return nil
 	})
@@ -693,21 +795,11 @@ func (b *tf12binder) bindBodyItem(item *bodyItem) hcl.Diagnostics {
 	return diagnostics
 }
 
-type variableScopes int
-
-func (variableScopes) GetScopesForBlock(block *hclsyntax.Block) (model.Scopes, hcl.Diagnostics) {
-	return model.StaticScope(nil), nil
-}
-
-func (variableScopes) GetScopeForAttribute(attribute *hclsyntax.Attribute) (*model.Scope, hcl.Diagnostics) {
-	if attribute.Name == "type" {
-		return model.TypeScope, nil
-	}
-	return nil, nil
-}
-
 func (b *tf12binder) bindVariable(v *variable) hcl.Diagnostics {
-	block, diagnostics := model.BindBlock(v.syntax, variableScopes(0), b.tokens, b.hcl2Options...)
+	// Validation rules refer to the variable itself, whose type is not known until its block has been bound.
+	v.terraformType = model.DynamicType
+
+	block, diagnostics := model.BindBlock(v.syntax, variableScopes{root: b.root}, b.tokens, b.hcl2Options...)
 	b.annotateExpressionsWithSchemas(block)
 
 	variableType := model.Type(model.DynamicType)
@@ -718,6 +810,7 @@ func (b *tf12binder) bindVariable(v *variable) hcl.Diagnostics {
 	}
 
 	v.terraformType, v.block = variableType, block
+	bindVariableAttributes(v)
 	return diagnostics
 }
 
@@ -744,23 +837,35 @@ func (b *tf12binder) bindOutput(o *output) hcl.Diagnostics {
 	return diagnostics
 }
 
-func (b *tf12binder) bindModule(m *module) hcl.Diagnostics {
-	return nil
-}
-
 type resourceScopes struct {
-	isDataSource   bool
-	root           *model.Scope
-	providers      *model.Scope
-	attributeScope *model.Scope
-	terraformType  model.Type
+	binder           *tf12binder
+	isDataSource     bool
+	root             *model.Scope
+	providers        *model.Scope
+	attributeScope   *model.Scope
+	provisionerScope *model.Scope
+	terraformType    model.Type
 }
 
 func (s *resourceScopes) GetScopesForBlock(block *hclsyntax.Block) (model.Scopes, hcl.Diagnostics) {
-	if s.isDataSource && block.Type == "lifecycle" {
-		return &lifecycleScopes{terraformType: s.terraformType}, nil
+	if block.Type == "lifecycle" {
+		conditionScope := s.provisionerScope
+		if conditionScope == nil {
+			conditionScope = s.attributeScope
+		}
+		return &lifecycleScopes{
+			root:           s.root,
+			conditionScope: conditionScope,
+			terraformType:  s.terraformType,
+		}, nil
+	}
+	if s.provisionerScope != nil && (block.Type == "provisioner" || block.Type == "connection") {
+		return &provisionerScopes{scope: s.provisionerScope}, nil
 	}
-	return model.StaticScope(s.root), nil
+	if block.Type == "dynamic" && len(block.Labels) == 1 {
+		return s.binder.newDynamicScopes(s.attributeScope, block)
+	}
+	return &blockScopes{binder: s.binder, scope: s.attributeScope}, nil
 }
 
 func (s *resourceScopes) GetScopeForAttribute(attribute *hclsyntax.Attribute) (*model.Scope, hcl.Diagnostics) {
@@ -773,19 +878,33 @@ func (s *resourceScopes) GetScopeForAttribute(attribute *hclsyntax.Attribute) (*
 	return s.attributeScope, nil
 }
 
+// lifecycleScopes binds the contents of a lifecycle block. The elements of ignore_changes refer to the properties of
+// the resource, the elements of replace_triggered_by refer to other resources, and precondition and postcondition
+// blocks may refer to both other resources and the resource itself.
 type lifecycleScopes struct {
-	terraformType model.Type
+	root           *model.Scope
+	conditionScope *model.Scope
+	terraformType  model.Type
 }
 
 func (s *lifecycleScopes) GetScopesForBlock(block *hclsyntax.Block) (model.Scopes, hcl.Diagnostics) {
+	switch block.Type {
+	case "precondition", "postcondition":
+		return model.StaticScope(s.conditionScope), nil
+	}
 	return s, nil
 }
 
 func (s *lifecycleScopes) GetScopeForAttribute(attribute *hclsyntax.Attribute) (*model.Scope, hcl.Diagnostics) {
+	switch attribute.Name {
+	case "replace_triggered_by", "create_before_destroy", "prevent_destroy":
+		return s.root, nil
+	}
+
 	if attribute.Name == "ignore_changes" {
 		if _, isTraversal := attribute.Expr.(*hclsyntax.ScopeTraversalExpr); isTraversal {
 			scope := model.NewRootScope(syntax.None)
-			scope.Define("all", model.Keyword("all"))
+			scope.Define("all", &model.Constant{Name: "all", ConstantValue: cty.StringVal("all")})
 			return scope, nil
 		}
 
@@ -802,19 +921,17 @@ func (s *lifecycleScopes) GetScopeForAttribute(attribute *hclsyntax.Attribute) (
 	return nil, nil
 }
 
-func (b *tf12binder) bindResource(r *resource) hcl.Diagnostics {
-	var diagnostics hcl.Diagnostics
-
-	var rangeDef hclsyntax.Node
-	if count, hasCount := r.syntax.Body.Attributes["count"]; hasCount {
-		rangeDef = count
-		r.rangeVariable = &model.Variable{
+// bindRangeVariable creates the range variable for a resource or module block with a count or for_each attribute. If
+// the block has neither attribute, bindRangeVariable returns a nil variable.
+func (b *tf12binder) bindRangeVariable(block *hclsyntax.Block) (hclsyntax.Node, *model.Variable, hcl.Diagnostics) {
+	if count, hasCount := block.Body.Attributes["count"]; hasCount {
+		rangeVariable := &model.Variable{
 			Name: "count",
 			VariableType: model.NewObjectType(map[string]model.Type{
 				"index": model.NumberType,
 			}),
 		}
-		b.variableToSchemas[r.rangeVariable] = func() il.Schemas {
+		b.variableToSchemas[rangeVariable] = func() il.Schemas {
 			return il.Schemas{
 				Pulumi: &tfbridge.SchemaInfo{
 					Fields: map[string]*tfbridge.SchemaInfo{
@@ -823,20 +940,28 @@ func (b *tf12binder) bindResource(r *resource) hcl.Diagnostics {
 				},
 			}
 		}
-	} else if forEach, hasForEach := r.syntax.Body.Attributes["for_each"]; hasForEach {
+		return count, rangeVariable, nil
+	} else if forEach, hasForEach := block.Body.Attributes["for_each"]; hasForEach {
 		forEachExpr, _ := model.BindExpression(forEach.Expr, b.root, b.tokens, b.hcl2Options...)
-		keyType, valueType, diags := model.GetCollectionTypes(forEachExpr.Type(), forEach.Expr.Range())
-		diagnostics = append(diagnostics, diags...)
+		keyType, valueType, diagnostics := model.GetCollectionTypes(forEachExpr.Type(), forEach.Expr.Range())
 
-		rangeDef = forEach
-		r.rangeVariable = &model.Variable{
+		rangeVariable := &model.Variable{
 			Name: "each",
 			VariableType: model.NewObjectType(map[string]model.Type{
 				"key":   keyType,
 				"value": valueType,
 			}),
 		}
+		return forEach, rangeVariable, diagnostics
 	}
+	return nil, nil, nil
+}
+
+func (b *tf12binder) bindResource(r *resource) hcl.Diagnostics {
+	var diagnostics hcl.Diagnostics
+
+	rangeDef, rangeVariable, diags := b.bindRangeVariable(r.syntax)
+	r.rangeVariable, diagnostics = rangeVariable, append(diagnostics, diags...)
 
 	attributeScope := b.root
 	if r.rangeVariable != nil {
@@ -844,15 +969,23 @@ func (b *tf12binder) bindResource(r *resource) hcl.Diagnostics {
 		attributeScope.Define(r.rangeVariable.Name, r.rangeVariable)
 	}
 	scopes := &resourceScopes{
+		binder:         b,
 		isDataSource:   r.isDataSource,
 		root:           b.root,
 		attributeScope: attributeScope,
 		providers:      b.providerScope,
 		terraformType:  r.terraformType,
 	}
+	if !r.isDataSource {
+		// Provisioner and connection blocks may refer to the resource as `self`.
+		r.selfVariable = &model.Variable{Name: "self", VariableType: r.terraformType}
+		scopes.provisionerScope = attributeScope.Push(r.syntax)
+		scopes.provisionerScope.Define(r.selfVariable.Name, r.selfVariable)
+	}
 
 	block, diags := model.BindBlock(r.syntax, scopes, b.tokens, b.hcl2Options...)
 	diagnostics = append(diagnostics, diags...)
+	b.bindDynamicBlocks(block.Body)
 
 	if r.rangeVariable != nil {
 		var rangeExpr model.Expression
@@ -885,21 +1018,32 @@ func (b *tf12binder) genBodyItem(w io.Writer, item *bodyItem) hcl.Diagnostics {
 	return nil
 }
 
-func (b *tf12binder) genVariable(w io.Writer, v *variable) hcl.Diagnostics {
-	var diagnostics hcl.Diagnostics
-
-	var bodyItems []model.BodyItem
-	if defaultValue, ok := v.block.Body.Attribute("default"); ok {
-		dv, diags := b.rewriteExpression(defaultValue.Value, nil)
-		defaultValue.Value, diagnostics = dv, append(diagnostics, diags...)
-
-		bodyItems = []model.BodyItem{defaultValue}
+// writeTodos writes a TODO comment for each of the given items. Used to record parts of the source that could not be
+// converted next to the code generated in their place.
+func writeTodos(w io.Writer, todos []string) {
+	for _, todo := range todos {
+		lines := strings.Split(todo, "\n")
+		_, err := fmt.Fprintf(w, "\n// TODO: %v", lines[0])
+		contract.IgnoreError(err)
+		for _, line := range lines[1:] {
+			_, err = fmt.Fprintf(w, "\n//   %v", line)
+			contract.IgnoreError(err)
+		}
+	}
+	if len(todos) != 0 {
+		_, err := fmt.Fprintf(w, "\n")
+		contract.IgnoreError(err)
 	}
+}
+
+func (b *tf12binder) genVariable(w io.Writer, v *variable) hcl.Diagnostics {
+	validations := v.block.Body.Items
+	bodyItems, diagnostics := b.variableConfigItems(v)
 	v.block.Body.Items = bodyItems
 
 	v.block.Type = "config"
 	v.block.Labels[0] = v.pulumiName
-	if v.terraformType != model.DynamicType {
+	if v.terraformType != model.DynamicType && !defaultsToNull(v) {
 		err := setConfigBlockType(v.block, v.terraformType)
 		if err != nil {
 			msg := fmt.Sprintf(`Ignoring inferred type for %s.
@@ -920,7 +1064,11 @@ to encode correctly. Error:
 
 	_, err := fmt.Fprintf(w, "%v", v.block)
 	contract.IgnoreError(err)
-	return diagnostics
+
+	v.block.Body.Items = validations
+	diags := b.genValidations(w, v)
+	v.block.Body.Items = bodyItems
+	return append(diagnostics, diags...)
 }
 
 func (b *tf12binder) genProvider(w io.Writer, p *provider) hcl.Diagnostics {
@@ -988,17 +1136,13 @@ func (b *tf12binder) genOutput(w io.Writer, o *output) hcl.Diagnostics {
 	return diagnostics
 }
 
-func (b *tf12binder) genModule(w io.Writer, m *module) hcl.Diagnostics {
-	// TODO(pdg): implement me
-	return nil
-}
-
 type blockInfo struct {
 	name           string
 	schemas        il.Schemas
 	elidedFields   codegen.StringSet
 	groupedTypes   map[string][]*model.Block
 	rewrittenTypes codegen.StringSet
+	isDynamic      bool
 }
 
 type resourceRewriter struct {
@@ -1006,6 +1150,7 @@ type resourceRewriter struct {
 	resource *resource
 	stack    []*blockInfo
 	options  *model.Block
+	todos    []string
 }
 
 func (rr *resourceRewriter) schemas() il.Schemas {
@@ -1112,7 +1257,16 @@ func (rr *resourceRewriter) enterBodyItem(item model.BodyItem) (model.BodyItem,
 	case *model.Attribute:
 		rr.push(item.Name, false)
 	case *model.Block:
-		info := rr.push(item.Type, true)
+		var info *blockInfo
+		switch {
+		case item.Type == "content" && rr.isDynamic():
+			info = rr.pushContent()
+		case item.Type == "dynamic" && len(item.Labels) == 1:
+			info = rr.push(item.Labels[0], true)
+			info.isDynamic = true
+		default:
+			info = rr.push(item.Type, true)
+		}
 
 		for _, item := range item.Body.Items {
 			switch item := item.(type) {
@@ -1127,7 +1281,8 @@ func (rr *resourceRewriter) enterBodyItem(item model.BodyItem) (model.BodyItem,
 					}
 				}
 			case *model.Block:
-				info.groupedTypes[item.Type] = append(info.groupedTypes[item.Type], item)
+				name := blockTypeName(item)
+				info.groupedTypes[name] = append(info.groupedTypes[name], item)
 			}
 		}
 	}
@@ -1208,6 +1363,20 @@ func (rr *resourceRewriter) rewriteBlockAsObjectCons(block *model.Block) *model.
 func (rr *resourceRewriter) rewriteBodyItem(item model.BodyItem) (model.BodyItem, hcl.Diagnostics) {
 	defer rr.pop()
 
+	if rr.inLifecycle() {
+		// The contents of lifecycle blocks are rewritten by rewriteLifecycle.
+		return item, nil
+	}
+	if attr, ok := item.(*model.Attribute); ok && rr.inDynamic() {
+		// The arguments of dynamic blocks are not properties. Only the collection is rewritten.
+		if attr.Name != "for_each" {
+			return attr, nil
+		}
+		value, diags := rr.binder.rewriteExpression(attr.Value, rr.resource)
+		attr.Value = value
+		return attr, diags
+	}
+
 	var diagnostics hcl.Diagnostics
 
 	switch item := item.(type) {
@@ -1270,30 +1439,14 @@ func (rr *resourceRewriter) rewriteBodyItem(item model.BodyItem) (model.BodyItem
 
 		item.Name, item.Value = rr.terraformToPulumiName(item.Name), value
 	case *model.Block:
+		if rr.isDynamic() {
+			// Dynamic blocks are rewritten by the block that contains them.
+			return item, nil
+		}
 		if len(rr.stack) == 2 {
 			switch item.Type {
 			case "lifecycle":
-				var result model.BodyItem
-				preventDestroy, ok := item.Body.Attribute("prevent_destroy")
-				if ok {
-					preventDestroy.Name = "protect"
-					result = rr.appendOption(preventDestroy)
-				}
-				ignoreChanges, ok := item.Body.Attribute("ignore_changes")
-				if ok {
-					ignoreChanges.Name = "ignoreChanges"
-					if options := rr.appendOption(ignoreChanges); options != nil {
-						result = options
-					}
-				}
-				return result, nil
-			case "provisioner", "connection":
-				rng := item.Syntax.TypeRange
-				return item, hcl.Diagnostics{{
-					Severity: hcl.DiagError,
-					Summary:  "tf2pulumi does not support provisioners",
-					Subject:  &rng,
-				}}
+				return rr.rewriteLifecycle(item)
 			}
 		}
 
@@ -1304,25 +1457,50 @@ func (rr *resourceRewriter) rewriteBodyItem(item model.BodyItem) (model.BodyItem
 				items = append(items, item)
 				continue
 			}
-			if rr.isRewritten(block.Type) {
+			blockType := blockTypeName(block)
+			if rr.isRewritten(blockType) {
 				continue
 			}
 
-			rr.markRewritten(block.Type)
+			rr.markRewritten(blockType)
 
-			group := rr.group(block.Type)
-			objects := make([]model.Expression, len(group))
-			for i, block := range group {
-				objects[i] = rr.rewriteBlockAsObjectCons(block)
-			}
+			group := rr.group(blockType)
 
-			propSch := rr.schemas().PropertySchemas(block.Type)
+			propSch := rr.schemas().PropertySchemas(blockType)
 			_, isList := propSch.ModelType().(*model.ListType)
 			projectListElement := isList && tfbridge.IsMaxItemsOne(propSch.TF, propSch.Pulumi)
 
-			name := terraformToPulumiName(block.Type, propSch)
+			name := terraformToPulumiName(blockType, propSch)
 			tokens := syntax.NewAttributeTokens(name)
 
+			if hasDynamicBlock(group) {
+				value, diags := rr.rewriteDynamicBlocks(group, projectListElement)
+				diagnostics = append(diagnostics, diags...)
+				if value == nil {
+					continue
+				}
+				last := group[len(group)-1]
+				if block.Tokens != nil {
+					tokens.Name.LeadingTrivia = block.Tokens.Type.LeadingTrivia
+				}
+				value.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+				if last.Tokens != nil {
+					value.SetTrailingTrivia(last.Tokens.CloseBrace.TrailingTrivia)
+				}
+
+				items = append(items, &model.Attribute{
+					Tokens: tokens,
+					Name:   name,
+					Value:  value,
+				})
+				continue
+			}
+
+			objects := make([]model.Expression, len(group))
+			for i, block := range group {
+				objects[i] = rr.rewriteBlockAsObjectCons(block)
+			}
+
 			var value model.Expression
 			if !projectListElement || len(objects) > 1 {
 				if block.Tokens != nil {
@@ -1379,7 +1557,15 @@ func (b *tf12binder) rewriteExpression(n model.Expression, resource *resource) (
 			// TODO(pdg): implement
 			return n, nil
 		case *model.ScopeTraversalExpression:
-			return b.rewriteScopeTraversal(n, resource)
+			if output, ok := b.rewriteRemoteStateOutput(n); ok {
+				return output, nil
+			}
+			if stack, ok := b.rewriteWorkspace(n); ok {
+				return stack, nil
+			}
+			v, _ := referencedVariable(n)
+			x, diagnostics := b.rewriteScopeTraversal(n, resource)
+			return b.rewriteSensitiveReference(x, v), diagnostics
 		default:
 			return n, nil
 		}
@@ -1387,18 +1573,6 @@ func (b *tf12binder) rewriteExpression(n model.Expression, resource *resource) (
 	return model.VisitExpression(n, model.IdentityVisitor, visitor)
 }
 
-func (b *tf12binder) rewriteFunctionCall(
-	n *model.FunctionCallExpression) (*model.FunctionCallExpression, hcl.Diagnostics) {
-
-	switch n.Name {
-	case "file":
-		n.Name = "readFile"
-	case "jsonencode":
-		n.Name = "toJSON"
-	}
-	return n, nil
-}
-
 func internalTrivia(traversal []syntax.TraverserTokens) (syntax.TriviaList, syntax.TriviaList) {
 	var leadingTrivia, trailingTrivia syntax.TriviaList
 	for i, traverser := range traversal {
@@ -1457,10 +1631,20 @@ func (b *tf12binder) rewriteScopeTraversal(n *model.ScopeTraversalExpression,
 		case *resource:
 			name, offset, schemas = p.pulumiName, i, p.schemas
 		case *module:
-			name, offset = p.pulumiName, i
+			name, offset, schemas = p.pulumiName, i, p.schemas
 		case *variable:
 			name, offset = p.pulumiName, i
 		case *model.Variable:
+			if res != nil && p == res.selfVariable {
+				name, offset, schemas = res.pulumiName, i, res.schemas
+				break
+			}
+			if d, ok := b.dynamicIterators[p]; ok {
+				if iteratorName, iteratorSchemas, ok := b.rewriteIteratorTraversal(n, i, d); ok {
+					name, offset, schemas = iteratorName, i+1, iteratorSchemas
+					break
+				}
+			}
 			if res != nil && res.isDataSource && p == res.rangeVariable {
 				if res.isCounted {
 					return makeSimpleTraversal("__index", p, n), nil
@@ -1574,6 +1758,11 @@ func (b *tf12binder) genResource(w io.Writer, r *resource) hcl.Diagnostics {
 	if r.rangeVariable != nil {
 		r.rangeVariable.Name = "range"
 	}
+	if isRemoteState(r) {
+		return b.genRemoteState(w, r)
+	}
+
+	provisioners, connection := extractProvisioners(r.block)
 
 	rewriter := &resourceRewriter{
 		binder:   b,
@@ -1582,6 +1771,11 @@ func (b *tf12binder) genResource(w io.Writer, r *resource) hcl.Diagnostics {
 	_, diags := model.VisitBodyItem(r.block, rewriter.enterBodyItem, rewriter.rewriteBodyItem)
 	diagnostics = append(diagnostics, diags...)
 
+	if !r.isDataSource && r.typeName == "null_resource" {
+		writeTodos(w, rewriter.todos)
+		return append(diagnostics, b.genProvisioners(w, r, provisioners, connection)...)
+	}
+
 	item := model.BodyItem(r.block)
 	if !r.isDataSource {
 		r.block.Labels = []string{r.pulumiName, r.token}
@@ -1708,7 +1902,12 @@ func (b *tf12binder) genResource(w io.Writer, r *resource) hcl.Diagnostics {
 			Value:  value,
 		}
 	}
+	writeTodos(w, rewriter.todos)
 	fmt.Fprintf(w, "%v", item)
+
+	if len(provisioners) != 0 {
+		diagnostics = append(diagnostics, b.genProvisioners(w, r, provisioners, connection)...)
+	}
 	return diagnostics
 }
 
@@ -0,0 +1,351 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/pulumi/pulumi/pkg/v3/codegen"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/il"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// dynamicBlock records the iterator of a dynamic block. Dynamic blocks are converted into list comprehensions whose
+// key and value variables replace the key and value attributes of the iterator.
+type dynamicBlock struct {
+	iterator  *model.Variable
+	forEach   *model.Attribute
+	keyName   string
+	valueName string
+	keyUsed   bool
+}
+
+// schemas returns the schemas for the elements of the dynamic block's collection.
+func (d *dynamicBlock) schemas(b *tf12binder) il.Schemas {
+	if d.forEach == nil {
+		return il.Schemas{}
+	}
+	return b.exprToSchemas[d.forEach.Value].ElemSchemas()
+}
+
+// blockScopes binds the contents of a block nested inside a resource. Dynamic blocks may appear at any level of
+// nesting.
+type blockScopes struct {
+	binder *tf12binder
+	scope  *model.Scope
+}
+
+func (s *blockScopes) GetScopesForBlock(block *hclsyntax.Block) (model.Scopes, hcl.Diagnostics) {
+	if block.Type == "dynamic" && len(block.Labels) == 1 {
+		return s.binder.newDynamicScopes(s.scope, block)
+	}
+	return s, nil
+}
+
+func (s *blockScopes) GetScopeForAttribute(attribute *hclsyntax.Attribute) (*model.Scope, hcl.Diagnostics) {
+	return s.scope, nil
+}
+
+// dynamicScopes binds the contents of a dynamic block. The iterator, which is named by the block's label unless the
+// block has an iterator attribute, is only in scope inside the block's content.
+type dynamicScopes struct {
+	binder       *tf12binder
+	scope        *model.Scope
+	iterator     *model.Variable
+	contentScope *model.Scope
+}
+
+func (b *tf12binder) newDynamicScopes(scope *model.Scope, block *hclsyntax.Block) (model.Scopes, hcl.Diagnostics) {
+	var diagnostics hcl.Diagnostics
+
+	name := block.Labels[0]
+	if iterator, ok := block.Body.Attributes["iterator"]; ok {
+		if traversal, ok := iterator.Expr.(*hclsyntax.ScopeTraversalExpr); ok && len(traversal.Traversal) == 1 {
+			name = traversal.Traversal.RootName()
+		}
+	}
+
+	keyType, valueType := model.Type(model.DynamicType), model.Type(model.DynamicType)
+	if forEach, ok := block.Body.Attributes["for_each"]; ok {
+		forEachExpr, _ := model.BindExpression(forEach.Expr, scope, b.tokens, b.hcl2Options...)
+		keyType, valueType, diagnostics = model.GetCollectionTypes(forEachExpr.Type(), forEach.Expr.Range())
+	}
+
+	iterator := &model.Variable{
+		Name: name,
+		VariableType: model.NewObjectType(map[string]model.Type{
+			"key":   keyType,
+			"value": valueType,
+		}),
+	}
+	valueName := camel(tfbridge.TerraformToPulumiName(name, nil, nil, false))
+	d := &dynamicBlock{
+		iterator:  iterator,
+		keyName:   valueName + "Key",
+		valueName: valueName,
+	}
+	b.dynamicBlocks[block], b.dynamicIterators[iterator] = d, d
+
+	contentScope := scope.Push(block)
+	contentScope.Define(name, iterator)
+
+	return &dynamicScopes{
+		binder:       b,
+		scope:        scope,
+		iterator:     iterator,
+		contentScope: contentScope,
+	}, diagnostics
+}
+
+func (s *dynamicScopes) GetScopesForBlock(block *hclsyntax.Block) (model.Scopes, hcl.Diagnostics) {
+	if block.Type == "content" {
+		return &blockScopes{binder: s.binder, scope: s.contentScope}, nil
+	}
+	return model.StaticScope(s.scope), nil
+}
+
+func (s *dynamicScopes) GetScopeForAttribute(attribute *hclsyntax.Attribute) (*model.Scope, hcl.Diagnostics) {
+	if attribute.Name == "iterator" {
+		scope := model.NewRootScope(syntax.None)
+		scope.Define(s.iterator.Name, &model.Constant{
+			Name:          s.iterator.Name,
+			ConstantValue: cty.StringVal(s.iterator.Name),
+		})
+		return scope, nil
+	}
+	return s.scope, nil
+}
+
+// bindDynamicBlocks records the for_each attribute of each dynamic block in the given body. The schemas of the
+// attribute's value determine the names of the properties of the iterator.
+func (b *tf12binder) bindDynamicBlocks(body *model.Body) {
+	for _, item := range body.Items {
+		block, ok := item.(*model.Block)
+		if !ok {
+			continue
+		}
+		if d, ok := b.dynamicBlocks[block.Syntax]; ok {
+			d.forEach, _ = block.Body.Attribute("for_each")
+		}
+		b.bindDynamicBlocks(block.Body)
+	}
+}
+
+// rewriteIteratorTraversal rewrites a traversal of the key or value of a dynamic block's iterator into a traversal of
+// the corresponding variable of the list comprehension.
+func (b *tf12binder) rewriteIteratorTraversal(n *model.ScopeTraversalExpression, i int,
+	d *dynamicBlock) (string, il.Schemas, bool) {
+
+	if len(n.Traversal) <= i+1 {
+		return "", il.Schemas{}, false
+	}
+	attr, ok := n.Traversal[i+1].(hcl.TraverseAttr)
+	if !ok {
+		return "", il.Schemas{}, false
+	}
+	switch attr.Name {
+	case "key":
+		d.keyUsed = true
+		return d.keyName, il.Schemas{}, true
+	case "value":
+		return d.valueName, d.schemas(b), true
+	default:
+		return "", il.Schemas{}, false
+	}
+}
+
+// isDynamic returns true if the rewriter is visiting a dynamic block.
+func (rr *resourceRewriter) isDynamic() bool {
+	return rr.stack[len(rr.stack)-1].isDynamic
+}
+
+// inDynamic returns true if the rewriter is visiting an item of a dynamic block.
+func (rr *resourceRewriter) inDynamic() bool {
+	return len(rr.stack) > 1 && rr.stack[len(rr.stack)-2].isDynamic
+}
+
+// pushContent pushes the content block of a dynamic block. The content of a dynamic block has the same schemas as the
+// dynamic block itself.
+func (rr *resourceRewriter) pushContent() *blockInfo {
+	info := &blockInfo{
+		name:           rr.stack[len(rr.stack)-1].name,
+		schemas:        rr.schemas(),
+		elidedFields:   codegen.StringSet{},
+		groupedTypes:   map[string][]*model.Block{},
+		rewrittenTypes: codegen.StringSet{},
+	}
+	rr.stack = append(rr.stack, info)
+	return info
+}
+
+// outdent removes one level of indentation from any line breaks in the given trivia.
+func outdent(trivia syntax.TriviaList) syntax.TriviaList {
+	result := make(syntax.TriviaList, len(trivia))
+	for i, t := range trivia {
+		if ws, ok := t.(syntax.Whitespace); ok {
+			bytes := ws.Bytes()
+			if n := len(bytes); n >= 2 && bytes[n-1] == ' ' && bytes[n-2] == ' ' {
+				t = syntax.NewWhitespace(bytes[:n-2]...)
+			}
+		}
+		result[i] = t
+	}
+	return result
+}
+
+// hasDynamicBlock returns true if any of the given blocks is a dynamic block.
+func hasDynamicBlock(blocks []*model.Block) bool {
+	for _, block := range blocks {
+		if block.Type == "dynamic" {
+			return true
+		}
+	}
+	return false
+}
+
+// blockTypeName returns the name of the property that a nested block sets.
+func blockTypeName(block *model.Block) string {
+	if block.Type == "dynamic" && len(block.Labels) == 1 {
+		return block.Labels[0]
+	}
+	return block.Type
+}
+
+// rewriteDynamicBlock converts a dynamic block into a list comprehension over its for_each attribute:
+//
+//	[for key, value in collection : { ...content... }]
+func (rr *resourceRewriter) rewriteDynamicBlock(block *model.Block) (*model.ForExpression, hcl.Diagnostics) {
+	d, ok := rr.binder.dynamicBlocks[block.Syntax]
+	content := block.Body.Blocks("content")
+	if !ok || d.forEach == nil || len(content) != 1 {
+		rng := block.Syntax.Range()
+		return nil, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("cannot convert dynamic block %v", blockTypeName(block)),
+			Detail:   "dynamic blocks must have a for_each attribute and a single content block",
+			Subject:  &rng,
+		}}
+	}
+
+	value := rr.rewriteBlockAsObjectCons(content[0])
+	value.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+	value.SetTrailingTrivia(nil)
+
+	// The content is nested one level deeper than the comprehension.
+	for _, item := range value.Items {
+		item.Key.SetLeadingTrivia(outdent(item.Key.GetLeadingTrivia()))
+	}
+	value.Tokens.CloseBrace.LeadingTrivia = outdent(value.Tokens.CloseBrace.LeadingTrivia)
+
+	collection := d.forEach.Value
+	collection.SetLeadingTrivia(nil)
+	collection.SetTrailingTrivia(nil)
+
+	var keyVariable *model.Variable
+	keyName := ""
+	if d.keyUsed {
+		keyName = d.keyName
+		keyVariable = &model.Variable{Name: keyName, VariableType: model.DynamicType}
+	}
+
+	tokens := syntax.NewForTokens(keyName, d.valueName, false, false, false)
+	tokens.In.TrailingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ')}
+	tokens.Colon.LeadingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ')}
+
+	return &model.ForExpression{
+		Tokens:        tokens,
+		KeyVariable:   keyVariable,
+		ValueVariable: &model.Variable{Name: d.valueName, VariableType: model.DynamicType},
+		Collection:    collection,
+		Value:         value,
+	}, nil
+}
+
+// rewriteDynamicBlocks converts a group of nested blocks that includes dynamic blocks into the value of the property
+// they set. A single dynamic block becomes a list comprehension. If the property is projected from a list with at most
+// one element, the comprehension is indexed:
+//
+//	length(collection) == 0 ? null : [for value in collection : { ...content... }][0]
+//
+// Mixed static and dynamic blocks are concatenated.
+func (rr *resourceRewriter) rewriteDynamicBlocks(group []*model.Block,
+	projectListElement bool) (model.Expression, hcl.Diagnostics) {
+
+	var diagnostics hcl.Diagnostics
+	var parts []model.Expression
+	var static []model.Expression
+	for _, block := range group {
+		if block.Type != "dynamic" {
+			static = append(static, rr.rewriteBlockAsObjectCons(block))
+			continue
+		}
+		if len(static) != 0 {
+			parts, static = append(parts, newTuple(static...)), nil
+		}
+
+		value, diags := rr.rewriteDynamicBlock(block)
+		diagnostics = append(diagnostics, diags...)
+		if value == nil {
+			continue
+		}
+		parts = append(parts, value)
+	}
+	if len(static) != 0 {
+		parts = append(parts, newTuple(static...))
+	}
+	if diagnostics.HasErrors() {
+		return nil, diagnostics
+	}
+
+	if len(parts) > 1 {
+		return tf12Functions["concat"].invokeStd(newCall("concat", parts...)), diagnostics
+	}
+
+	value := parts[0]
+	if forExpr, ok := value.(*model.ForExpression); ok && projectListElement {
+		isEmpty := &model.BinaryOpExpression{
+			Tokens:       syntax.NewBinaryOpTokens(hclsyntax.OpEqual),
+			LeftOperand:  newCall("length", forExpr.Collection),
+			Operation:    hclsyntax.OpEqual,
+			RightOperand: &model.LiteralValueExpression{Value: cty.NumberIntVal(0)},
+		}
+		isEmpty.RightOperand.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+		isEmpty.Tokens.Operator.LeadingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ')}
+
+		null := newTraversal("null")
+		null.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+
+		index := &model.IndexExpression{
+			Tokens:     syntax.NewIndexTokens(),
+			Collection: forExpr,
+			Key:        &model.LiteralValueExpression{Value: cty.NumberIntVal(0)},
+		}
+		index.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+
+		value = &model.ConditionalExpression{
+			Tokens:      syntax.NewConditionalTokens(),
+			Condition:   isEmpty,
+			TrueResult:  null,
+			FalseResult: index,
+		}
+	}
+	return value, diagnostics
+}
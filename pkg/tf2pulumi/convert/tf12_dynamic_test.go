@@ -0,0 +1,141 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/test"
+)
+
+func TestConvertDynamicBlocks(t *testing.T) {
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(`
+variable "rules" {
+  type = list(object({ from = number, to = number }))
+}
+
+variable "volume_size" {
+  type = number
+}
+
+resource "aws_security_group" "web" {
+  ingress {
+    from_port = 443
+    to_port   = 443
+    protocol  = "tcp"
+  }
+
+  dynamic "ingress" {
+    for_each = var.rules
+    content {
+      from_port = ingress.value.from
+      to_port   = ingress.value.to
+      protocol  = "tcp"
+    }
+  }
+
+  dynamic "egress" {
+    for_each = var.rules
+    iterator = rule
+    content {
+      from_port   = rule.value.from
+      to_port     = rule.value.to
+      protocol    = "tcp"
+      description = "rule ${rule.key}"
+    }
+  }
+}
+
+resource "aws_lb_listener" "web" {
+  load_balancer_arn = "arn"
+
+  dynamic "default_action" {
+    for_each = var.rules
+    content {
+      type = "forward"
+      forward {
+        dynamic "target_group" {
+          for_each = [default_action.value.from, default_action.value.to]
+          iterator = tg
+          content {
+            arn    = "arn-${tg.value}"
+            weight = default_action.key
+          }
+        }
+      }
+    }
+  }
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-123"
+
+  dynamic "root_block_device" {
+    for_each = var.volume_size == 0 ? [] : [var.volume_size]
+    content {
+      volume_size = root_block_device.value
+    }
+  }
+}
+`), 0600))
+
+	generated, diags, err := Convert(Options{
+		Root: root,
+		Loader: specLoader{
+			"std": stdSpec,
+			"aws": {
+				Name:    "aws",
+				Version: "1.0.0",
+				Resources: map[string]schema.ResourceSpec{
+					"aws:ec2/instance:Instance":           resourceSpec("ami", "rootBlockDevice"),
+					"aws:ec2/securityGroup:SecurityGroup": resourceSpec("ingress", "egress"),
+					"aws:lb/listener:Listener":            resourceSpec("loadBalancerArn", "defaultActions"),
+				},
+			},
+		},
+		ProviderInfoSource:       test.NewProviderInfoSource("../testdata/providers"),
+		SkipResourceTypechecking: true,
+		TargetLanguage:           LanguagePulumi,
+	})
+	require.NoError(t, err)
+	require.False(t, diags.All.HasErrors(), "%v", diags.All)
+
+	program := string(generated["main.tf.pp"])
+
+	// Static and dynamic blocks of the same type are concatenated.
+	assert.Contains(t, program, `ingress = invoke("std:index:concat", { input = [[{`)
+	assert.Contains(t, program, `}], [for ingress in rules : {
+    fromPort = ingress.from,`)
+
+	// Iterators may be renamed, and the key is only bound if it is used.
+	assert.Contains(t, program, `egress = [for ruleKey, rule in rules : {
+    fromPort   = rule.from,`)
+	assert.Contains(t, program, `description = "rule ${ruleKey}"`)
+
+	// Dynamic blocks may be nested, and may refer to the iterators of the blocks that contain them.
+	assert.Contains(t, program, `defaultActions = [for defaultActionKey, defaultAction in rules : {`)
+	assert.Contains(t, program, `targetGroups = [for tg in [defaultAction.from, defaultAction.to] : {`)
+	assert.Contains(t, program, `weight = defaultActionKey`)
+
+	// Blocks with at most one element are projected from the comprehension.
+	assert.Contains(t, program, "rootBlockDevice = length(volumeSize == 0 ? [] : [volumeSize]) == 0 ? null : "+
+		"[for rootBlockDevice in volumeSize == 0 ? [] : [volumeSize] : {\n    volumeSize = rootBlockDevice\n  }][0]")
+}
@@ -0,0 +1,362 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// stdModule is the module that holds the std-style helper functions invoked by converted programs.
+const stdModule = "std:index"
+
+// tf12Function describes how a call to a TF built-in function is converted to PCL. A function maps to either a PCL
+// intrinsic, which is called with the same arguments as the TF function, or to an invoke of a std-style helper, which
+// receives its arguments as an object and returns its result in the `result` property. Functions that only convert
+// their argument between equivalent types map to the argument itself.
+type tf12Function struct {
+	// intrinsic is the name of the PCL intrinsic the function maps to, if any.
+	intrinsic string
+	// std is the name of the std helper the function maps to, if any.
+	std string
+	// identity is true if a call to the function is replaced with its only argument.
+	identity bool
+
+	// params holds the names of the function's parameters. When the function is invoked as a std helper, these are
+	// the names of the properties of the argument object.
+	params []string
+	// variadic is true if the last parameter collects any remaining arguments into a list.
+	variadic bool
+}
+
+func intrinsicFunction(name string, params ...string) *tf12Function {
+	return &tf12Function{intrinsic: name, params: params}
+}
+
+func stdFunction(name string, params ...string) *tf12Function {
+	return &tf12Function{std: name, params: params}
+}
+
+func variadicStdFunction(name string, params ...string) *tf12Function {
+	return &tf12Function{std: name, params: params, variadic: true}
+}
+
+func identityFunction() *tf12Function {
+	return &tf12Function{identity: true, params: []string{"value"}}
+}
+
+// tf12Functions maps the name of each TF built-in function that can be converted to a description of its conversion.
+// Functions that are missing from this table cannot be converted.
+var tf12Functions = map[string]*tf12Function{
+	// Numeric functions
+	"abs":      stdFunction("abs", "input"),
+	"ceil":     stdFunction("ceil", "input"),
+	"floor":    stdFunction("floor", "input"),
+	"log":      stdFunction("log", "input", "base"),
+	"max":      variadicStdFunction("max", "input"),
+	"min":      variadicStdFunction("min", "input"),
+	"parseint": stdFunction("parseint", "input", "base"),
+	"pow":      stdFunction("pow", "base", "exponent"),
+	"signum":   stdFunction("signum", "input"),
+
+	// String functions
+	"chomp":      stdFunction("chomp", "input"),
+	"endswith":   stdFunction("endswith", "input", "suffix"),
+	"format":     variadicStdFunction("format", "input", "args"),
+	"formatlist": variadicStdFunction("formatlist", "input", "args"),
+	"indent":     stdFunction("indent", "spaces", "input"),
+	"join":       intrinsicFunction("join", "separator", "list"),
+	"lower":      stdFunction("lower", "input"),
+	"regex":      stdFunction("regex", "pattern", "string"),
+	"replace":    stdFunction("replace", "text", "search", "replace"),
+	"split":      intrinsicFunction("split", "separator", "string"),
+	"startswith": stdFunction("startswith", "input", "prefix"),
+	"strrev":     stdFunction("strrev", "input"),
+	"substr":     stdFunction("substr", "input", "offset", "length"),
+	"title":      stdFunction("title", "input"),
+	"trim":       stdFunction("trim", "input", "cutset"),
+	"trimprefix": stdFunction("trimprefix", "input", "prefix"),
+	"trimspace":  stdFunction("trimspace", "input"),
+	"trimsuffix": stdFunction("trimsuffix", "input", "suffix"),
+	"upper":      stdFunction("upper", "input"),
+
+	// Collection functions
+	"alltrue":      stdFunction("alltrue", "input"),
+	"anytrue":      stdFunction("anytrue", "input"),
+	"chunklist":    stdFunction("chunklist", "input", "size"),
+	"coalesce":     variadicStdFunction("coalesce", "input"),
+	"coalescelist": variadicStdFunction("coalescelist", "input"),
+	"compact":      stdFunction("compact", "input"),
+	"concat":       variadicStdFunction("concat", "input"),
+	"contains":     stdFunction("contains", "input", "element"),
+	"distinct":     stdFunction("distinct", "input"),
+	"element":      intrinsicFunction("element", "list", "index"),
+	"flatten":      stdFunction("flatten", "input"),
+	"index":        stdFunction("index", "input", "element"),
+	"keys":         stdFunction("keys", "input"),
+	"length":       intrinsicFunction("length", "value"),
+	"lookup":       intrinsicFunction("lookup", "map", "key", "default"),
+	"matchkeys":    stdFunction("matchkeys", "values", "keys", "searchset"),
+	"merge":        variadicStdFunction("merge", "input"),
+	"one":          stdFunction("one", "input"),
+	"range":        stdFunction("range", "start", "limit", "step"),
+	"reverse":      stdFunction("reverse", "input"),
+	"slice":        stdFunction("slice", "list", "from", "to"),
+	"sort":         stdFunction("sort", "input"),
+	"sum":          stdFunction("sum", "input"),
+	"transpose":    stdFunction("transpose", "input"),
+	"values":       stdFunction("values", "input"),
+	"zipmap":       stdFunction("zipmap", "keys", "values"),
+
+	// Encoding functions
+	"base64decode": stdFunction("base64decode", "input"),
+	"base64encode": intrinsicFunction("toBase64", "value"),
+	"base64gzip":   stdFunction("base64gzip", "input"),
+	"csvdecode":    stdFunction("csvdecode", "input"),
+	"jsondecode":   stdFunction("jsondecode", "input"),
+	"jsonencode":   intrinsicFunction("toJSON", "value"),
+	"urlencode":    stdFunction("urlencode", "input"),
+	"yamldecode":   stdFunction("yamldecode", "input"),
+	"yamlencode":   stdFunction("yamlencode", "input"),
+
+	// Filesystem functions
+	"abspath":          stdFunction("abspath", "input"),
+	"basename":         stdFunction("basename", "input"),
+	"dirname":          stdFunction("dirname", "input"),
+	"file":             intrinsicFunction("readFile", "path"),
+	"filebase64":       intrinsicFunction("filebase64", "path"),
+	"filebase64sha256": intrinsicFunction("filebase64sha256", "path"),
+	"filebase64sha512": stdFunction("filebase64sha512", "input"),
+	"fileexists":       stdFunction("fileexists", "input"),
+	"filemd5":          stdFunction("filemd5", "input"),
+	"filesha1":         stdFunction("filesha1", "input"),
+	"filesha256":       stdFunction("filesha256", "input"),
+	"filesha512":       stdFunction("filesha512", "input"),
+	"pathexpand":       stdFunction("pathexpand", "input"),
+	"templatefile":     stdFunction("templatefile", "input", "vars"),
+
+	// Date and time functions
+	"timeadd":   stdFunction("timeadd", "timestamp", "duration"),
+	"timecmp":   stdFunction("timecmp", "timestampa", "timestampb"),
+	"timestamp": stdFunction("timestamp"),
+
+	// Hash and crypto functions
+	"base64sha256": stdFunction("base64sha256", "input"),
+	"base64sha512": stdFunction("base64sha512", "input"),
+	"bcrypt":       stdFunction("bcrypt", "input", "cost"),
+	"md5":          stdFunction("md5", "input"),
+	"rsadecrypt":   stdFunction("rsadecrypt", "ciphertext", "key"),
+	"sha1":         intrinsicFunction("sha1", "input"),
+	"sha256":       stdFunction("sha256", "input"),
+	"sha512":       stdFunction("sha512", "input"),
+	"uuid":         stdFunction("uuid"),
+
+	// IP network functions
+	"cidrhost":    stdFunction("cidrhost", "input", "host"),
+	"cidrnetmask": stdFunction("cidrnetmask", "input"),
+	"cidrsubnet":  stdFunction("cidrsubnet", "input", "newbits", "netnum"),
+	"cidrsubnets": variadicStdFunction("cidrsubnets", "input", "newbits"),
+
+	// Type conversion functions
+	"sensitive": intrinsicFunction("secret", "value"),
+	"tobool":    identityFunction(),
+	"tolist":    identityFunction(),
+	"tomap":     identityFunction(),
+	"tonumber":  identityFunction(),
+	"toset":     identityFunction(),
+	"tostring":  identityFunction(),
+}
+
+// tf12UnsupportedFunctions lists the TF built-in functions that cannot be converted. These functions are declared
+// when binding TF12 code so that calls to them are reported by rewriteFunctionCall rather than by the binder.
+//
+// can and try evaluate their arguments lazily in order to catch errors, which an invoke cannot do, and nonsensitive has
+// no equivalent intrinsic in the supported versions of PCL.
+var tf12UnsupportedFunctions = []string{
+	"can",
+	"fileset",
+	"formatdate",
+	"nonsensitive",
+	"regexall",
+	"setintersection",
+	"setproduct",
+	"setsubtract",
+	"setunion",
+	"textdecodebase64",
+	"textencodebase64",
+	"try",
+	"uuidv5",
+}
+
+// signature returns a permissive signature for the function for use when binding TF12 code. All parameters accept
+// any type and may be omitted; the TF configuration is assumed to be valid.
+func (f *tf12Function) signature() model.StaticFunctionSignature {
+	var signature model.StaticFunctionSignature
+
+	params := f.params
+	if f.variadic {
+		params = params[:len(params)-1]
+		signature.VarargsParameter = &model.Parameter{
+			Name: f.params[len(f.params)-1],
+			Type: model.DynamicType,
+		}
+	}
+	for _, name := range params {
+		signature.Parameters = append(signature.Parameters, model.Parameter{
+			Name: name,
+			Type: model.NewOptionalType(model.DynamicType),
+		})
+	}
+	signature.ReturnType = model.DynamicType
+
+	return signature
+}
+
+// unsupportedFunction returns a model.Function that accepts any arguments for use when binding calls to functions
+// that cannot be converted.
+func unsupportedFunction() *model.Function {
+	return model.NewFunction(model.StaticFunctionSignature{
+		VarargsParameter: &model.Parameter{
+			Name: "args",
+			Type: model.DynamicType,
+		},
+		ReturnType: model.DynamicType,
+	})
+}
+
+// function returns a model.Function for the function for use when binding TF12 code. Identity functions return the
+// type of their argument.
+func (f *tf12Function) function() *model.Function {
+	if !f.identity {
+		return model.NewFunction(f.signature())
+	}
+	return model.NewFunction(model.GenericFunctionSignature(
+		func(args []model.Expression) (model.StaticFunctionSignature, hcl.Diagnostics) {
+			signature := f.signature()
+			if len(args) > 0 {
+				signature.ReturnType = args[0].Type()
+			}
+			return signature, nil
+		}))
+}
+
+// stringLiteral returns a literal string expression with the given value.
+func stringLiteral(value string) *model.LiteralValueExpression {
+	return &model.LiteralValueExpression{Value: cty.StringVal(value)}
+}
+
+// invokeStd returns an expression that invokes the std helper for the given call and projects its result. The
+// arguments to the helper are printed as a single-line object.
+func (f *tf12Function) invokeStd(n *model.FunctionCallExpression) model.Expression {
+	type item struct {
+		name  string
+		value model.Expression
+	}
+	var items []item
+	for i, arg := range n.Args {
+		if i >= len(f.params) || f.variadic && i == len(f.params)-1 {
+			break
+		}
+		items = append(items, item{f.params[i], arg})
+	}
+	if f.variadic && len(n.Args) >= len(f.params) {
+		rest := n.Args[len(f.params)-1:]
+		for _, arg := range rest {
+			arg.SetLeadingTrivia(nil)
+			arg.SetTrailingTrivia(nil)
+		}
+		tokens := syntax.NewTupleConsTokens(len(rest))
+		for i := range tokens.Commas {
+			tokens.Commas[i].TrailingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ')}
+		}
+		items = append(items, item{f.params[len(f.params)-1], &model.TupleConsExpression{
+			Tokens:      tokens,
+			Expressions: rest,
+		}})
+	}
+
+	space := syntax.TriviaList{syntax.NewWhitespace(' ')}
+	args := &model.ObjectConsExpression{Tokens: syntax.NewObjectConsTokens(len(items))}
+	args.Tokens.OpenBrace.LeadingTrivia, args.Tokens.OpenBrace.TrailingTrivia = space, nil
+	args.Tokens.CloseBrace.LeadingTrivia = space
+	for i, item := range items {
+		key := stringLiteral(item.name)
+		key.Tokens = syntax.NewLiteralValueTokens(key.Value)
+		key.SetLeadingTrivia(space)
+		item.value.SetLeadingTrivia(space)
+		item.value.SetTrailingTrivia(nil)
+		if comma := args.Tokens.Items[i].Comma; comma != nil {
+			comma.TrailingTrivia = nil
+		}
+		args.Items = append(args.Items, model.ObjectConsItem{Key: key, Value: item.value})
+	}
+
+	invoke := &model.FunctionCallExpression{
+		Name: "invoke",
+		Args: []model.Expression{
+			&model.TemplateExpression{
+				Parts: []model.Expression{stringLiteral(fmt.Sprintf("%s:%s", stdModule, f.std))},
+			},
+			args,
+		},
+	}
+	invoke.Tokens = syntax.NewFunctionCallTokens(invoke.Name, len(invoke.Args))
+
+	return &model.RelativeTraversalExpression{
+		Source:    invoke,
+		Traversal: hcl.Traversal{hcl.TraverseAttr{Name: "result"}},
+		Parts:     []model.Traversable{model.DynamicType},
+	}
+}
+
+// rewriteFunctionCall converts a call to a TF built-in function into the equivalent PCL expression. Calls to functions
+// that cannot be converted are left as-is, and produce an error diagnostic.
+func (b *tf12binder) rewriteFunctionCall(n *model.FunctionCallExpression) (model.Expression, hcl.Diagnostics) {
+	f, ok := tf12Functions[n.Name]
+	if !ok {
+		rng := n.SyntaxNode().Range()
+		return n, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("cannot convert call to function '%v'", n.Name),
+			Detail: fmt.Sprintf("the Terraform function '%v' has no equivalent in Pulumi; the call must be converted "+
+				"by hand", n.Name),
+			Subject: &rng,
+		}}
+	}
+
+	var result model.Expression
+	switch {
+	case f.identity && len(n.Args) == 1:
+		result = n.Args[0]
+	case f.intrinsic != "":
+		n.Name = f.intrinsic
+		return n, nil
+	case n.Name == "range" && len(n.Args) <= 2:
+		// The range intrinsic accepts at most two arguments.
+		return n, nil
+	case f.std != "":
+		result = f.invokeStd(n)
+	default:
+		return n, nil
+	}
+
+	result.SetLeadingTrivia(n.GetLeadingTrivia())
+	result.SetTrailingTrivia(n.GetTrailingTrivia())
+	return result, nil
+}
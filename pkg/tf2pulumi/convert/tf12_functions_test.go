@@ -0,0 +1,195 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// specLoader loads packages from the schemas it holds.
+type specLoader map[string]schema.PackageSpec
+
+func (l specLoader) LoadPackage(pkg string, version *semver.Version) (*schema.Package, error) {
+	spec, ok := l[pkg]
+	if !ok {
+		return nil, fmt.Errorf("unknown package %v", pkg)
+	}
+	return schema.ImportSpec(spec, nil)
+}
+
+func anyProperty() schema.PropertySpec {
+	return schema.PropertySpec{TypeSpec: schema.TypeSpec{Ref: "pulumi.json#/Any"}}
+}
+
+// stdSpec declares the std helpers used by the conversion tests.
+var stdSpec = func() schema.PackageSpec {
+	function := func(inputs ...string) schema.FunctionSpec {
+		properties := map[string]schema.PropertySpec{}
+		for _, input := range inputs {
+			properties[input] = anyProperty()
+		}
+		return schema.FunctionSpec{
+			Inputs: &schema.ObjectTypeSpec{Type: "object", Properties: properties, Required: inputs},
+			Outputs: &schema.ObjectTypeSpec{
+				Type:       "object",
+				Properties: map[string]schema.PropertySpec{"result": anyProperty()},
+				Required:   []string{"result"},
+			},
+		}
+	}
+	return schema.PackageSpec{
+		Name:    "std",
+		Version: "1.0.0",
+		Functions: map[string]schema.FunctionSpec{
+			"std:index:concat":       function("input"),
+			"std:index:upper":        function("input"),
+			"std:index:merge":        function("input"),
+			"std:index:templatefile": function("input", "vars"),
+			"std:index:yamlencode":   function("input"),
+		},
+	}
+}()
+
+func TestConvertFunctions(t *testing.T) {
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(`
+variable "name" {
+  type = string
+}
+
+variable "tags" {
+  type = map(string)
+}
+
+output "intrinsic" {
+  value = jsonencode(base64encode(file("data.txt")))
+}
+
+output "std" {
+  value = upper(var.name)
+}
+
+output "variadic" {
+  value = merge(var.tags, { "Name" = var.name })
+}
+
+output "identity" {
+  value = tostring(var.name)
+}
+
+output "numbers" {
+  value = range(3)
+}
+
+output "template" {
+  value = templatefile("template.tftpl", { name = var.name })
+}
+
+output "yaml" {
+  value = yamlencode(var.tags)
+}
+`), 0600))
+
+	generated, diags, err := Convert(Options{
+		Root:           root,
+		Loader:         specLoader{"std": stdSpec},
+		TargetLanguage: LanguagePulumi,
+	})
+	require.NoError(t, err)
+	require.False(t, diags.All.HasErrors(), "%v", diags.All)
+
+	program := string(generated["main.tf.pp"])
+	assert.Contains(t, program, `value = toJSON(toBase64(readFile("data.txt")))`)
+	assert.Contains(t, program, `value = invoke("std:index:upper", { input = name }).result`)
+	assert.Contains(t, program, `value = invoke("std:index:merge", { input = [tags, {`)
+	assert.Contains(t, program, "value = name\n")
+	assert.Contains(t, program, "value = range(3)")
+	assert.Contains(t, program, `value = invoke("std:index:templatefile", { input = "template.tftpl", vars = {`)
+	assert.Contains(t, program, `value = invoke("std:index:yamlencode", { input = tags }).result`)
+}
+
+// requireUnsupportedFunction asserts that the given diagnostics report the call to the named function, which must be
+// the only call in the source, as one that cannot be converted.
+func requireUnsupportedFunction(t *testing.T, diags hcl.Diagnostics, source, name string) {
+	var diag *hcl.Diagnostic
+	for _, d := range diags {
+		if d.Summary == fmt.Sprintf("cannot convert call to function '%v'", name) {
+			diag = d
+		}
+	}
+	require.NotNil(t, diag, "%v", diags)
+	assert.Equal(t, hcl.DiagError, diag.Severity)
+	require.NotNil(t, diag.Subject)
+	assert.Equal(t, "main.tf", diag.Subject.Filename)
+	start := strings.Index(source, name+"(")
+	end := strings.LastIndex(source, ")") + 1
+	assert.Equal(t, start, diag.Subject.Start.Byte)
+	assert.Equal(t, end, diag.Subject.End.Byte)
+}
+
+func TestUnsupportedFunctions(t *testing.T) {
+	cases := map[string]string{
+		"can":          `can(tonumber("x"))`,
+		"fileset":      `fileset("files", "*.txt")`,
+		"nonsensitive": `nonsensitive("x")`,
+		"try":          `try(tonumber("x"), 0)`,
+		"uuidv5":       `uuidv5("dns", "example.com")`,
+	}
+	for name, call := range cases {
+		name, call := name, call
+		t.Run(name, func(t *testing.T) {
+			// Functions that cannot be converted are reported against the call.
+			source := fmt.Sprintf("output \"value\" {\n  value = %v\n}\n", call)
+			root := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(source), 0600))
+
+			_, diags, err := Convert(Options{
+				Root:           root,
+				TargetLanguage: LanguagePulumi,
+			})
+			require.NoError(t, err)
+			require.True(t, diags.All.HasErrors())
+			requireUnsupportedFunction(t, diags.All, source, name)
+		})
+	}
+}
+
+func TestRewriteNonsensitive(t *testing.T) {
+	// nonsensitive has no equivalent in PCL, so converting it to a language reports the call rather than failing to
+	// bind the generated program.
+	source := "output \"value\" {\n  value = nonsensitive(\"x\")\n}\n"
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(source), 0600))
+
+	_, diags, err := Convert(Options{
+		Root:           root,
+		TargetLanguage: LanguageTypescript,
+	})
+	require.NoError(t, err)
+	require.True(t, diags.All.HasErrors())
+	for _, d := range diags.All {
+		assert.NotContains(t, d.Summary, "unknown function")
+	}
+	requireUnsupportedFunction(t, diags.All, source, "nonsensitive")
+}
@@ -0,0 +1,228 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// inLifecycle returns true if the rewriter is visiting the contents of a lifecycle block.
+func (rr *resourceRewriter) inLifecycle() bool {
+	return len(rr.stack) > 2 && rr.stack[1].name == "lifecycle"
+}
+
+// unsupported records part of a lifecycle block that cannot be converted. A warning is issued, and a TODO comment is
+// written before the resource.
+func (rr *resourceRewriter) unsupported(summary, todo string, subject hcl.Range) *hcl.Diagnostic {
+	rr.todos = append(rr.todos, todo)
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  summary,
+		Subject:  &subject,
+	}
+}
+
+// rewriteLifecycle converts the contents of a lifecycle block into resource options:
+//
+//   - prevent_destroy becomes protect
+//   - ignore_changes becomes ignoreChanges, with each property path renamed. ignore_changes = all lists every input
+//     property of the resource.
+//   - create_before_destroy = false becomes deleteBeforeReplace = true. Pulumi creates replacements before deleting
+//     the resources they replace by default, so create_before_destroy = true needs no option.
+//
+// replace_triggered_by has no equivalent, and precondition and postcondition blocks are not checked by Pulumi. These
+// are recorded as TODO comments.
+func (rr *resourceRewriter) rewriteLifecycle(block *model.Block) (model.BodyItem, hcl.Diagnostics) {
+	var diagnostics hcl.Diagnostics
+
+	var result model.BodyItem
+	appendOption := func(attr *model.Attribute) {
+		if options := rr.appendOption(attr); options != nil {
+			result = options
+		}
+	}
+
+	for _, item := range block.Body.Items {
+		switch item := item.(type) {
+		case *model.Attribute:
+			switch item.Name {
+			case "prevent_destroy":
+				value, diags := rr.binder.rewriteExpression(item.Value, rr.resource)
+				item.Name, item.Value, diagnostics = "protect", value, append(diagnostics, diags...)
+				appendOption(item)
+			case "ignore_changes":
+				item.Name, item.Value = "ignoreChanges", rr.rewriteIgnoreChanges(item.Value)
+				appendOption(item)
+			case "create_before_destroy":
+				value, diags := rr.binder.rewriteExpression(item.Value, rr.resource)
+				diagnostics = append(diagnostics, diags...)
+
+				if lit, ok := value.(*model.LiteralValueExpression); ok && lit.Value.Type() == cty.Bool {
+					if lit.Value.True() {
+						continue
+					}
+					value = &model.LiteralValueExpression{
+						Tokens: syntax.NewLiteralValueTokens(cty.True),
+						Value:  cty.True,
+					}
+				} else {
+					value = &model.UnaryOpExpression{
+						Tokens:    syntax.NewUnaryOpTokens(hclsyntax.OpLogicalNot),
+						Operation: hclsyntax.OpLogicalNot,
+						Operand:   value,
+					}
+				}
+				value.SetLeadingTrivia(item.Value.GetLeadingTrivia())
+				value.SetTrailingTrivia(item.Value.GetTrailingTrivia())
+
+				if rr.binder.opts.TargetLanguage != LanguagePulumi {
+					diagnostics = append(diagnostics, rr.unsupported(
+						"create_before_destroy cannot be converted",
+						fmt.Sprintf("set the deleteBeforeReplace resource option to %v", strings.TrimSpace(
+							fmt.Sprintf("%v", value))),
+						item.Syntax.Range()))
+					continue
+				}
+				item.Name, item.Value = "deleteBeforeReplace", value
+				appendOption(item)
+				rr.binder.unboundConstructs.Add("the deleteBeforeReplace resource option")
+			case "replace_triggered_by":
+				value, diags := rr.binder.rewriteExpression(item.Value, rr.resource)
+				diagnostics = append(diagnostics, diags...)
+				diagnostics = append(diagnostics, rr.unsupported(
+					"replace_triggered_by cannot be converted",
+					fmt.Sprintf("replace this resource when any of %v change. Pulumi's replaceOnChanges option only\n"+
+						"applies to the resource's own properties: pass the values to a property that forces replacement\n"+
+						"or replace the resource by hand", strings.TrimSpace(fmt.Sprintf("%v", value))),
+					item.Syntax.Range()))
+			}
+		case *model.Block:
+			switch item.Type {
+			case "precondition", "postcondition":
+				todo := item.Type
+				if condition, ok := item.Body.Attribute("condition"); ok {
+					value, diags := rr.binder.rewriteExpression(condition.Value, rr.resource)
+					diagnostics = append(diagnostics, diags...)
+					todo = fmt.Sprintf("%v: %v", todo, strings.TrimSpace(fmt.Sprintf("%v", value)))
+				}
+				if message, ok := item.Body.Attribute("error_message"); ok {
+					value, diags := rr.binder.rewriteExpression(message.Value, rr.resource)
+					diagnostics = append(diagnostics, diags...)
+					todo = fmt.Sprintf("%v\nerror message: %v", todo, strings.TrimSpace(fmt.Sprintf("%v", value)))
+				}
+				diagnostics = append(diagnostics, rr.unsupported(
+					fmt.Sprintf("%v blocks are not checked by Pulumi", item.Type), todo, item.Syntax.TypeRange))
+			}
+		}
+	}
+
+	return result, diagnostics
+}
+
+// rewriteIgnoreChanges rewrites the value of an ignore_changes attribute into the value of the ignoreChanges option.
+func (rr *resourceRewriter) rewriteIgnoreChanges(value model.Expression) model.Expression {
+	if keyword, ok := keywordValue(value); ok && keyword == "all" {
+		// Ignore changes to every property that may be set by the program.
+		var properties []string
+		if obj, ok := rr.resource.terraformType.(*model.ObjectType); ok {
+			for name := range obj.Properties {
+				sch := rr.resource.schemas.PropertySchemas(name).TF
+				if name == "id" || sch != nil && sch.Computed() && !sch.Optional() {
+					continue
+				}
+				properties = append(properties, name)
+			}
+		}
+		sort.Strings(properties)
+
+		paths := make([]model.Expression, len(properties))
+		for i, name := range properties {
+			paths[i] = newTraversal(terraformToPulumiName(name, rr.resource.schemas.PropertySchemas(name)))
+		}
+		tuple := newTuple(paths...)
+		tuple.SetLeadingTrivia(value.GetLeadingTrivia())
+		tuple.SetTrailingTrivia(value.GetTrailingTrivia())
+		return tuple
+	}
+
+	tuple, ok := value.(*model.TupleConsExpression)
+	if !ok {
+		return value
+	}
+	for i, path := range tuple.Expressions {
+		tuple.Expressions[i] = rr.rewriteIgnoreChangesPath(path)
+	}
+	return tuple
+}
+
+// rewriteIgnoreChangesPath renames each property in an element of ignore_changes. Elements may be traversals or, as
+// in older versions of Terraform, strings.
+func (rr *resourceRewriter) rewriteIgnoreChangesPath(path model.Expression) model.Expression {
+	var traversal hcl.Traversal
+	var parts []model.Traversable
+	switch path := path.(type) {
+	case *model.ScopeTraversalExpression:
+		traversal, parts = path.Traversal, path.Parts
+	default:
+		s, ok := literalStringValue(path)
+		if !ok {
+			return path
+		}
+		t, diags := hclsyntax.ParseTraversalAbs([]byte(s), "", hcl.Pos{})
+		if diags.HasErrors() {
+			return path
+		}
+		traversal, parts = t, make([]model.Traversable, len(t))
+		for i := range parts {
+			parts[i] = model.DynamicType
+		}
+	}
+
+	schemas := rr.resource.schemas
+	newTraversal := make(hcl.Traversal, len(traversal))
+	for i, traverser := range traversal {
+		switch traverser := traverser.(type) {
+		case hcl.TraverseRoot:
+			schemas = schemas.PropertySchemas(traverser.Name)
+			traverser.Name = terraformToPulumiName(traverser.Name, schemas)
+			newTraversal[i] = traverser
+		case hcl.TraverseAttr:
+			schemas = schemas.PropertySchemas(traverser.Name)
+			traverser.Name = terraformToPulumiName(traverser.Name, schemas)
+			newTraversal[i] = traverser
+		default:
+			schemas = schemas.ElemSchemas()
+			newTraversal[i] = traverser
+		}
+	}
+
+	result := &model.ScopeTraversalExpression{
+		Tokens:    syntax.NewScopeTraversalTokens(newTraversal),
+		RootName:  newTraversal.RootName(),
+		Traversal: newTraversal,
+		Parts:     parts,
+	}
+	result.SetLeadingTrivia(path.GetLeadingTrivia())
+	result.SetTrailingTrivia(path.GetTrailingTrivia())
+	return result
+}
@@ -0,0 +1,118 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/test"
+)
+
+var lifecycleLoader = specLoader{
+	"aws": {
+		Name:    "aws",
+		Version: "1.0.0",
+		Resources: map[string]schema.ResourceSpec{
+			"aws:ec2/instance:Instance": resourceSpec("ami", "instanceType", "privateIp", "publicIp"),
+			"aws:alb/targetGroupAttachment:TargetGroupAttachment": resourceSpec("availabilityZone", "port",
+				"targetGroupArn", "targetId"),
+		},
+	},
+}
+
+func TestConvertLifecycle(t *testing.T) {
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(`
+resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t2.micro"
+
+  lifecycle {
+    prevent_destroy       = true
+    ignore_changes        = [ami, "instance_type"]
+    create_before_destroy = false
+    replace_triggered_by  = [aws_alb_target_group_attachment.web.id]
+
+    precondition {
+      condition     = aws_alb_target_group_attachment.web.port == 80
+      error_message = "The target group must listen on port 80."
+    }
+
+    postcondition {
+      condition     = self.public_ip != ""
+      error_message = "The instance must have a public IP."
+    }
+  }
+}
+
+resource "aws_alb_target_group_attachment" "web" {
+  target_group_arn = "arn"
+  target_id        = "id"
+
+  lifecycle {
+    ignore_changes        = all
+    create_before_destroy = true
+  }
+}
+`), 0600))
+
+	convert := func(language string) (map[string][]byte, hcl.Diagnostics) {
+		generated, diags, err := Convert(Options{
+			Root:                     root,
+			Loader:                   lifecycleLoader,
+			ProviderInfoSource:       test.NewProviderInfoSource("../testdata/providers"),
+			SkipResourceTypechecking: true,
+			TargetLanguage:           language,
+		})
+		require.NoError(t, err)
+		require.False(t, diags.All.HasErrors(), "%v", diags.All)
+		return generated, diags.All
+	}
+
+	generated, diags := convert(LanguagePulumi)
+	program := string(generated["main.tf.pp"])
+	assert.Contains(t, program, `// TODO: replace this resource when any of [webTargetGroupAttachment.id] change.`)
+	assert.Contains(t, program, `// TODO: precondition: webTargetGroupAttachment.port == 80
+//   error message: "The target group must listen on port 80."`)
+	assert.Contains(t, program, `// TODO: postcondition: webInstance.publicIp != ""`)
+	assert.Regexp(t, `options \{\s+protect\s+= true\s+ignoreChanges\s+= \[ami, instanceType\]\s+`+
+		`deleteBeforeReplace = true\s+\}`, program)
+	assert.Regexp(t, `ignoreChanges\s+= \[availabilityZone, port, targetGroupArn, targetId\]`, program)
+
+	var summaries []string
+	for _, d := range diags {
+		summaries = append(summaries, d.Summary)
+	}
+	assert.ElementsMatch(t, []string{
+		"replace_triggered_by cannot be converted",
+		"precondition blocks are not checked by Pulumi",
+		"postcondition blocks are not checked by Pulumi",
+		"the converted program has not been checked",
+	}, summaries)
+
+	// Other targets do not support deleteBeforeReplace, so it is left as a TODO.
+	_, diags = convert(LanguageTypescript)
+	summaries = nil
+	for _, d := range diags {
+		summaries = append(summaries, d.Summary)
+	}
+	assert.Contains(t, summaries, "create_before_destroy cannot be converted")
+}
@@ -0,0 +1,258 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/il"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// component is a Pulumi component converted from a local TF module. Each module directory is converted once,
+// regardless of the number of module blocks that reference it.
+type component struct {
+	dir   string         // the directory of the module in the source filesystem.
+	files []*syntax.File // the converted program for the component.
+
+	inputs  map[string]string // maps the name of each TF variable to the name of the component input.
+	outputs map[string]string // maps the name of each TF output to the name of the component output.
+
+	converting bool // true while the component is being converted. Used to detect recursive modules.
+}
+
+// isLocalModuleSource returns true if the given module source refers to a directory on the local filesystem. As in
+// Terraform, local sources must begin with either "./" or "../".
+func isLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+// literalStringValue returns the value of the given expression if it is a string literal.
+func literalStringValue(expr model.Expression) (string, bool) {
+	if t, ok := expr.(*model.TemplateExpression); ok && len(t.Parts) == 1 {
+		expr = t.Parts[0]
+	}
+	if lit, ok := expr.(*model.LiteralValueExpression); ok && lit.Value.Type() == cty.String {
+		return lit.Value.AsString(), true
+	}
+	return "", false
+}
+
+// convertComponent converts the TF module in the given directory into a component.
+func (b *tf12binder) convertComponent(dir string, subject hcl.Range) (*component, hcl.Diagnostics) {
+	if c, ok := b.components[dir]; ok {
+		if c.converting {
+			return nil, hcl.Diagnostics{{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("module %v references itself", dir),
+				Subject:  &subject,
+			}}
+		}
+		return c, nil
+	}
+
+	files, diagnostics := parseTF12Dir(b.opts.Root, dir)
+	if diagnostics.HasErrors() {
+		return nil, diagnostics
+	}
+
+	c := &component{
+		dir:        dir,
+		inputs:     map[string]string{},
+		outputs:    map[string]string{},
+		converting: true,
+	}
+	b.components[dir] = c
+
+	binder := newTF12Binder(files, b.opts, b.hcl2Options, b.pulumiOptions, dir, b.components)
+	declaredFiles, pulumiFiles, diags := binder.convertFiles(files)
+	diagnostics = append(diagnostics, diags...)
+
+	for _, f := range declaredFiles {
+		for _, n := range f.nodes {
+			switch n := n.(type) {
+			case *variable:
+				c.inputs[n.name] = n.pulumiName
+			case *output:
+				c.outputs[n.name] = n.pulumiName
+			}
+		}
+	}
+	c.files, c.converting = pulumiFiles, false
+
+	return c, diagnostics
+}
+
+func (b *tf12binder) bindModule(m *module) hcl.Diagnostics {
+	var diagnostics hcl.Diagnostics
+
+	rangeDef, rangeVariable, diags := b.bindRangeVariable(m.syntax)
+	m.rangeVariable, diagnostics = rangeVariable, append(diagnostics, diags...)
+
+	attributeScope := b.root
+	if m.rangeVariable != nil {
+		attributeScope = b.root.Push(rangeDef)
+		attributeScope.Define(m.rangeVariable.Name, m.rangeVariable)
+	}
+	scopes := &resourceScopes{
+		binder:         b,
+		root:           b.root,
+		attributeScope: attributeScope,
+		providers:      b.providerScope,
+	}
+
+	block, diags := model.BindBlock(m.syntax, scopes, b.tokens, b.hcl2Options...)
+	diagnostics = append(diagnostics, diags...)
+	b.annotateExpressionsWithSchemas(block)
+
+	m.block, m.terraformType, m.variableType = block, model.DynamicType, model.DynamicType
+
+	if source, ok := block.Body.Attribute("source"); ok {
+		m.source, _ = literalStringValue(source.Value)
+	}
+
+	rng := m.syntax.DefRange()
+	if !isLocalModuleSource(m.source) {
+		return append(diagnostics, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  fmt.Sprintf("cannot convert module %v", m.name),
+			Detail: fmt.Sprintf("only modules with local sources can be converted; module %v will be omitted",
+				m.name),
+			Subject: &rng,
+		})
+	}
+	if b.opts.TargetLanguage != LanguagePulumi {
+		return append(diagnostics, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("cannot convert module %v to %v", m.name, b.opts.TargetLanguage),
+			Detail: "modules are converted to components, which are not yet supported by the code generator for " +
+				"this language; convert to PCL instead",
+			Subject: &rng,
+		})
+	}
+
+	c, diags := b.convertComponent(path.Join(b.dir, m.source), rng)
+	diagnostics = append(diagnostics, diags...)
+	if c == nil {
+		return diagnostics
+	}
+	m.component = c
+
+	// The component's outputs are described by an object type whose properties are the TF names of the outputs. The
+	// schema info for the module maps these names to the names of the component's outputs.
+	outputTypes := map[string]model.Type{}
+	fields := map[string]*tfbridge.SchemaInfo{}
+	for name, pulumiName := range c.outputs {
+		outputTypes[name] = model.DynamicType
+		fields[name] = &tfbridge.SchemaInfo{Name: pulumiName}
+	}
+	m.terraformType = model.NewObjectType(outputTypes)
+	m.variableType = m.terraformType
+	m.schemas = il.Schemas{Pulumi: &tfbridge.SchemaInfo{Fields: fields}}
+	if m.rangeVariable != nil {
+		m.variableType = model.NewListType(m.terraformType)
+		m.schemas = il.Schemas{Pulumi: &tfbridge.SchemaInfo{Elem: m.schemas.Pulumi}}
+	}
+
+	return diagnostics
+}
+
+func (b *tf12binder) genModule(w io.Writer, m *module) hcl.Diagnostics {
+	if m.component == nil {
+		// The module could not be converted. A diagnostic has already been issued.
+		return nil
+	}
+
+	var diagnostics hcl.Diagnostics
+
+	if m.rangeVariable != nil {
+		m.rangeVariable.Name = "range"
+	}
+
+	var options []*model.Attribute
+	items := make([]model.BodyItem, 0, len(m.block.Body.Items))
+	for _, item := range m.block.Body.Items {
+		attr, ok := item.(*model.Attribute)
+		if !ok {
+			items = append(items, item)
+			continue
+		}
+
+		switch attr.Name {
+		case "source", "version":
+			continue
+		case "providers":
+			rng := attr.Syntax.Range()
+			diagnostics = append(diagnostics, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("provider mappings for module %v are not supported", m.name),
+				Subject:  &rng,
+			})
+			continue
+		}
+
+		value, diags := b.rewriteExpression(attr.Value, nil)
+		attr.Value, diagnostics = value, append(diagnostics, diags...)
+
+		switch attr.Name {
+		case "count", "for_each":
+			attr.Name = "range"
+		case "depends_on":
+			attr.Name = "dependsOn"
+		default:
+			name, ok := m.component.inputs[attr.Name]
+			if !ok {
+				rng := attr.Syntax.NameRange
+				diagnostics = append(diagnostics, &hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  fmt.Sprintf("module %v has no variable named %v", m.name, attr.Name),
+					Subject:  &rng,
+				})
+				name = camel(tfbridge.TerraformToPulumiName(attr.Name, nil, nil, false))
+			}
+			attr.Name = name
+			items = append(items, attr)
+			continue
+		}
+
+		options = append(options, attr)
+	}
+
+	// Move the resource options into an options block at the end of the component's body.
+	rewriter := &resourceRewriter{binder: b}
+	for _, attr := range options {
+		if options := rewriter.appendOption(attr); options != nil {
+			items = append(items, options)
+		}
+	}
+	m.block.Body.Items = items
+
+	m.block.Type = "component"
+	m.block.Labels = []string{m.pulumiName, m.source}
+	b.unboundConstructs.Add("components")
+
+	_, err := fmt.Fprintf(w, "%v", m.block)
+	contract.IgnoreError(err)
+	return diagnostics
+}
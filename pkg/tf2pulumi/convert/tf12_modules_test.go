@@ -0,0 +1,121 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertModules(t *testing.T) {
+	root := afero.NewMemMapFs()
+	files := map[string]string{
+		"/main.tf": `
+variable "base_cidr" {
+  type = string
+}
+
+module "network" {
+  source    = "./modules/network"
+  cidr_block = var.base_cidr
+}
+
+module "subnet" {
+  source     = "./modules/network"
+  count      = 2
+  cidr_block = cidrsubnet(var.base_cidr, 4, count.index)
+  depends_on = [module.network]
+}
+
+output "network_id" {
+  value = module.network.network_id
+}
+
+output "subnet_ids" {
+  value = module.subnet[0].network_id
+}
+`,
+		"/modules/network/main.tf": `
+variable "cidr_block" {
+  type = string
+}
+
+output "network_id" {
+  value = "network-${var.cidr_block}"
+}
+`,
+	}
+	for name, contents := range files {
+		require.NoError(t, afero.WriteFile(root, name, []byte(contents), 0600))
+	}
+
+	generated, diags, err := Convert(Options{
+		Root:           root,
+		TargetLanguage: LanguagePulumi,
+	})
+	require.NoError(t, err)
+	require.False(t, diags.All.HasErrors(), "%v", diags.All)
+
+	// The PCL binder does not yet support components, so the program is not bound.
+	require.Len(t, diags.All, 1)
+	assert.Equal(t, "the converted program has not been checked", diags.All[0].Summary)
+	assert.Equal(t, "the program contains components, which the PCL binder does not yet support",
+		diags.All[0].Detail)
+
+	program := string(generated["main.tf.pp"])
+	assert.Contains(t, program, `component network "./modules/network" {`)
+	assert.Contains(t, program, "cidrBlock = baseCidr")
+	assert.Contains(t, program, `component subnet "./modules/network" {`)
+	assert.Contains(t, program,
+		`cidrBlock = invoke("std:index:cidrsubnet", { input = baseCidr, newbits = 4, netnum = range.value }).result`)
+	assert.Regexp(t, `options \{\s+range\s+= 2\s+dependsOn = \[network\]`, program)
+	assert.Contains(t, program, "value = network.networkId")
+	assert.Contains(t, program, "value = subnet[0].networkId")
+
+	component := string(generated["modules/network/main.tf.pp"])
+	assert.Contains(t, component, "config cidrBlock string {")
+	assert.Contains(t, component, "output networkId {")
+
+	// Modules can only be converted to PCL.
+	_, diags, err = Convert(Options{
+		Root:           root,
+		TargetLanguage: LanguageTypescript,
+	})
+	require.NoError(t, err)
+	assert.True(t, diags.All.HasErrors())
+}
+
+func TestConvertRemoteModules(t *testing.T) {
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(`
+module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "3.14.0"
+}
+`), 0600))
+
+	generated, diags, err := Convert(Options{
+		Root:           root,
+		TargetLanguage: LanguagePulumi,
+	})
+	require.NoError(t, err)
+	require.False(t, diags.All.HasErrors(), "%v", diags.All)
+	require.Len(t, diags.All, 1)
+	assert.Equal(t, "cannot convert module vpc", diags.All[0].Summary)
+	assert.NotContains(t, string(generated["main.tf.pp"]), "vpc")
+}
@@ -0,0 +1,535 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	defaultLocalCommandToken  = "command:local:Command"
+	defaultRemoteCommandToken = "command:remote:Command"
+	defaultCopyFileToken      = "command:remote:CopyFile"
+)
+
+// connectionProperties maps the names of the TF connection arguments that can be converted to the names of the
+// corresponding properties of a command connection. Bastion arguments are converted into the connection's proxy.
+var connectionProperties = map[string]string{
+	"host":        "host",
+	"port":        "port",
+	"user":        "user",
+	"password":    "password",
+	"private_key": "privateKey",
+}
+
+var bastionProperties = map[string]string{
+	"bastion_host":        "host",
+	"bastion_port":        "port",
+	"bastion_user":        "user",
+	"bastion_password":    "password",
+	"bastion_private_key": "privateKey",
+}
+
+// provisionerScopes binds the contents of provisioner and connection blocks. These blocks may refer to the resource
+// that contains them using the name `self`, and use bare keywords for the values of `when` and `on_failure`. The
+// keywords are bound as string constants.
+type provisionerScopes struct {
+	scope *model.Scope
+}
+
+func (s *provisionerScopes) GetScopesForBlock(block *hclsyntax.Block) (model.Scopes, hcl.Diagnostics) {
+	return s, nil
+}
+
+func (s *provisionerScopes) GetScopeForAttribute(attribute *hclsyntax.Attribute) (*model.Scope, hcl.Diagnostics) {
+	switch attribute.Name {
+	case "when", "on_failure":
+		scope := model.NewRootScope(syntax.None)
+		for _, keyword := range []string{"create", "destroy", "continue", "fail"} {
+			scope.Define(keyword, &model.Constant{Name: keyword, ConstantValue: cty.StringVal(keyword)})
+		}
+		return scope, nil
+	}
+	return s.scope, nil
+}
+
+// keywordValue returns the name of the keyword referenced by the given expression, if any.
+func keywordValue(expr model.Expression) (string, bool) {
+	traversal, ok := expr.(*model.ScopeTraversalExpression)
+	if !ok || len(traversal.Parts) != 1 {
+		return "", false
+	}
+	keyword, ok := traversal.Parts[0].(*model.Constant)
+	if !ok || keyword.ConstantValue.Type() != cty.String {
+		return "", false
+	}
+	return keyword.ConstantValue.AsString(), true
+}
+
+// extractProvisioners removes the provisioner and connection blocks from the body of the given resource and returns
+// them.
+func extractProvisioners(block *model.Block) ([]*model.Block, *model.Block) {
+	var provisioners []*model.Block
+	var connection *model.Block
+
+	items := make([]model.BodyItem, 0, len(block.Body.Items))
+	for _, item := range block.Body.Items {
+		if block, ok := item.(*model.Block); ok {
+			switch block.Type {
+			case "provisioner":
+				provisioners = append(provisioners, block)
+				continue
+			case "connection":
+				connection = block
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	block.Body.Items = items
+
+	return provisioners, connection
+}
+
+// newTraversal returns a traversal of the named top-level node.
+func newTraversal(name string) *model.ScopeTraversalExpression {
+	traversal := hcl.Traversal{hcl.TraverseRoot{Name: name}}
+	return &model.ScopeTraversalExpression{
+		Tokens:    syntax.NewScopeTraversalTokens(traversal),
+		RootName:  name,
+		Traversal: traversal,
+		Parts:     []model.Traversable{model.DynamicType},
+	}
+}
+
+// newTemplate returns a template expression that evaluates to the given string.
+func newTemplate(value string) *model.TemplateExpression {
+	return &model.TemplateExpression{Parts: []model.Expression{stringLiteral(value)}}
+}
+
+// newAttribute returns an attribute with the given name and value. Any trivia attached to the value is discarded.
+func newAttribute(name string, value model.Expression) *model.Attribute {
+	value.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+	value.SetTrailingTrivia(syntax.TriviaList{syntax.NewWhitespace('\n')})
+
+	tokens := syntax.NewAttributeTokens(name)
+	tokens.Name.LeadingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ', ' ')}
+	return &model.Attribute{
+		Tokens: tokens,
+		Name:   name,
+		Value:  value,
+	}
+}
+
+// newObject returns an object whose properties are given by the attributes. The object is printed on a single line.
+func newObject(attrs []*model.Attribute) *model.ObjectConsExpression {
+	object := &model.ObjectConsExpression{Tokens: syntax.NewObjectConsTokens(len(attrs))}
+	object.Tokens.OpenBrace.TrailingTrivia = nil
+	object.Tokens.CloseBrace.LeadingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ')}
+	for i, attr := range attrs {
+		key := stringLiteral(attr.Name)
+		key.Tokens = syntax.NewLiteralValueTokens(key.Value)
+		key.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+
+		attr.Value.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+		attr.Value.SetTrailingTrivia(nil)
+		object.Items = append(object.Items, model.ObjectConsItem{
+			Key:   key,
+			Value: attr.Value,
+		})
+		if comma := object.Tokens.Items[i].Comma; comma != nil {
+			comma.TrailingTrivia = nil
+		}
+	}
+	return object
+}
+
+// newTuple returns a tuple of the given expressions.
+func newTuple(exprs ...model.Expression) *model.TupleConsExpression {
+	tokens := syntax.NewTupleConsTokens(len(exprs))
+	for i, expr := range exprs {
+		expr.SetLeadingTrivia(nil)
+		expr.SetTrailingTrivia(nil)
+		if i < len(tokens.Commas) {
+			tokens.Commas[i].TrailingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ')}
+		}
+	}
+	return &model.TupleConsExpression{
+		Tokens:      tokens,
+		Expressions: exprs,
+	}
+}
+
+// newCall returns a call to the named function.
+func newCall(name string, args ...model.Expression) *model.FunctionCallExpression {
+	for i, arg := range args {
+		arg.SetLeadingTrivia(nil)
+		if i > 0 {
+			arg.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+		}
+		arg.SetTrailingTrivia(nil)
+	}
+	return &model.FunctionCallExpression{
+		Tokens: syntax.NewFunctionCallTokens(name, len(args)),
+		Name:   name,
+		Args:   args,
+	}
+}
+
+// provisionerConverter converts the provisioners of a single resource into command resources.
+type provisionerConverter struct {
+	binder   *tf12binder
+	resource *resource
+
+	connections map[*model.Block]model.Expression
+	diagnostics hcl.Diagnostics
+	todos       []string
+}
+
+// unsupported records an argument or block that cannot be converted. A warning is issued, and a TODO comment is
+// written before the resource being generated.
+func (pc *provisionerConverter) unsupported(summary string, subject hcl.Range) {
+	pc.diagnostics = append(pc.diagnostics, &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  summary,
+		Subject:  &subject,
+	})
+	pc.todos = append(pc.todos, summary)
+}
+
+// attributes rewrites the attributes in the given body and returns them by name.
+func (pc *provisionerConverter) attributes(body *model.Body) map[string]*model.Attribute {
+	attrs := map[string]*model.Attribute{}
+	for _, item := range body.Items {
+		if attr, ok := item.(*model.Attribute); ok {
+			if _, isKeyword := keywordValue(attr.Value); !isKeyword {
+				value, diags := pc.binder.rewriteExpression(attr.Value, pc.resource)
+				attr.Value, pc.diagnostics = value, append(pc.diagnostics, diags...)
+			}
+			attrs[attr.Name] = attr
+		}
+	}
+	return attrs
+}
+
+// convertConnection converts a TF connection block into the connection object accepted by remote commands.
+func (pc *provisionerConverter) convertConnection(block *model.Block) model.Expression {
+	if connection, ok := pc.connections[block]; ok {
+		return connection
+	}
+
+	attrs := pc.attributes(block.Body)
+
+	if typ, ok := attrs["type"]; ok {
+		if v, ok := literalStringValue(typ.Value); !ok || v != "ssh" {
+			pc.unsupported("only SSH connections can be converted", typ.Syntax.Range())
+		}
+	}
+
+	var properties, proxy []*model.Attribute
+	for _, name := range sortedAttributeNames(attrs) {
+		attr := attrs[name]
+		switch {
+		case name == "type":
+			continue
+		case connectionProperties[name] != "":
+			properties = append(properties, newAttribute(connectionProperties[name], attr.Value))
+		case bastionProperties[name] != "":
+			proxy = append(proxy, newAttribute(bastionProperties[name], attr.Value))
+		default:
+			pc.unsupported(fmt.Sprintf("connection argument %v cannot be converted", name), attr.Syntax.Range())
+		}
+	}
+	if len(proxy) != 0 {
+		properties = append(properties, newAttribute("proxy", newObject(proxy)))
+	}
+
+	connection := newObject(properties)
+	pc.connections[block] = connection
+	return connection
+}
+
+// convertProvisioner converts a single provisioner into the body of a command resource. If the provisioner cannot be
+// converted, convertProvisioner returns an empty token.
+func (pc *provisionerConverter) convertProvisioner(p *model.Block, connection *model.Block) (string, []model.BodyItem) {
+	kind := p.Labels[0]
+
+	var items []model.BodyItem
+	for _, item := range p.Body.Items {
+		if block, ok := item.(*model.Block); ok {
+			if block.Type == "connection" {
+				connection = block
+				continue
+			}
+			pc.unsupported(fmt.Sprintf("%v blocks in %v provisioners cannot be converted", block.Type, kind),
+				block.Syntax.Range())
+		}
+	}
+	attrs := pc.attributes(p.Body)
+
+	destroy := false
+	if when, ok := attrs["when"]; ok {
+		keyword, _ := keywordValue(when.Value)
+		destroy = keyword == "destroy"
+		delete(attrs, "when")
+	}
+	if onFailure, ok := attrs["on_failure"]; ok {
+		if keyword, _ := keywordValue(onFailure.Value); keyword == "continue" {
+			pc.unsupported("on_failure = continue cannot be converted; failures will fail the deployment",
+				onFailure.Syntax.Range())
+		}
+		delete(attrs, "on_failure")
+	}
+
+	// The command property that runs the command.
+	commandProperty := "create"
+	if destroy {
+		commandProperty = "delete"
+	}
+
+	var token string
+	var properties map[string]string
+	switch kind {
+	case "local-exec":
+		token = pc.binder.opts.LocalCommandToken
+		if token == "" {
+			token = defaultLocalCommandToken
+		}
+		properties = map[string]string{
+			"command":     commandProperty,
+			"working_dir": "dir",
+			"environment": "environment",
+			"interpreter": "interpreter",
+		}
+	case "remote-exec":
+		token = pc.binder.opts.RemoteCommandToken
+		if token == "" {
+			token = defaultRemoteCommandToken
+		}
+
+		// The inline, script, and scripts arguments are mutually exclusive. Each is converted into a single command.
+		var command model.Expression
+		if inline, ok := attrs["inline"]; ok {
+			command = newCall("join", newTemplate("\n"), inline.Value)
+			delete(attrs, "inline")
+		} else if script, ok := attrs["script"]; ok {
+			command = newCall("readFile", script.Value)
+			delete(attrs, "script")
+		} else if scripts, ok := attrs["scripts"]; ok {
+			scriptVariable := &model.Variable{Name: "script", VariableType: model.StringType}
+			scriptTraversal := newTraversal("script")
+			scriptTraversal.Parts = []model.Traversable{scriptVariable}
+
+			scripts.Value.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+			scripts.Value.SetTrailingTrivia(nil)
+			command = newCall("join", newTemplate("\n"), &model.ForExpression{
+				Tokens:        syntax.NewForTokens("", "script", false, false, false),
+				ValueVariable: scriptVariable,
+				Collection:    scripts.Value,
+				Value:         newCall("readFile", scriptTraversal),
+			})
+			delete(attrs, "scripts")
+		}
+		if command != nil {
+			items = append(items, newAttribute(commandProperty, command))
+		}
+		properties = map[string]string{}
+	case "file":
+		token = pc.binder.opts.CopyFileToken
+		if token == "" {
+			token = defaultCopyFileToken
+		}
+		if destroy {
+			pc.unsupported("file provisioners that run at destroy time cannot be converted", p.Syntax.Range())
+		}
+		properties = map[string]string{
+			"source":      "localPath",
+			"destination": "remotePath",
+		}
+	default:
+		pc.unsupported(fmt.Sprintf("%v provisioners cannot be converted", kind), p.Syntax.Range())
+		return "", nil
+	}
+
+	for _, name := range sortedAttributeNames(attrs) {
+		attr := attrs[name]
+		property, ok := properties[name]
+		if !ok {
+			pc.unsupported(fmt.Sprintf("%v provisioner argument %v cannot be converted", kind, name),
+				attr.Syntax.Range())
+			continue
+		}
+		items = append(items, newAttribute(property, attr.Value))
+	}
+
+	if kind != "local-exec" {
+		if connection == nil {
+			pc.unsupported(fmt.Sprintf("%v provisioner has no connection", kind), p.Syntax.Range())
+		} else {
+			items = append(items, newAttribute("connection", pc.convertConnection(connection)))
+		}
+	}
+
+	return token, items
+}
+
+// genProvisioners generates a command resource for each of the given provisioners. Commands run after the resource
+// that contains them has been created, and are replaced along with the resource. Provisioners run in order.
+//
+// A null_resource is converted into its provisioners alone. The last provisioner takes the name of the null_resource
+// so that references to the null_resource refer to the command that finishes last, and the null_resource's triggers
+// and options are carried over to each command.
+func (b *tf12binder) genProvisioners(w io.Writer, r *resource, provisioners []*model.Block,
+	connection *model.Block) hcl.Diagnostics {
+
+	isNullResource := r.typeName == "null_resource"
+
+	var rangeValue, dependsOn model.Expression
+	if options := r.block.Body.Blocks("options"); len(options) != 0 {
+		if rng, ok := options[0].Body.Attribute("range"); ok {
+			rangeValue = rng.Value
+		}
+		if deps, ok := options[0].Body.Attribute("dependsOn"); ok {
+			dependsOn = deps.Value
+		}
+	}
+
+	pc := &provisionerConverter{
+		binder:      b,
+		resource:    r,
+		connections: map[*model.Block]model.Expression{},
+	}
+	if rangeValue != nil && !isNullResource {
+		rng := r.syntax.DefRange()
+		pc.unsupported("references to self from provisioners of resources with count or for_each refer to all "+
+			"instances of the resource", rng)
+	}
+
+	var triggers model.Expression
+	if isNullResource {
+		if len(provisioners) == 0 {
+			rng := r.syntax.DefRange()
+			pc.unsupported(fmt.Sprintf("null_resource %v has no provisioners and cannot be converted", r.name), rng)
+		}
+		if t, ok := r.block.Body.Attribute("triggers"); ok {
+			triggers = newTuple(t.Value)
+		}
+	} else {
+		id := newTraversal(r.pulumiName)
+		id.Traversal = append(id.Traversal, hcl.TraverseAttr{Name: "id"})
+		id.Tokens = syntax.NewScopeTraversalTokens(id.Traversal)
+		id.Parts = append(id.Parts, model.StringType)
+		triggers = newTuple(id)
+	}
+
+	previous := ""
+	for i, p := range provisioners {
+		token, items := pc.convertProvisioner(p, connection)
+		pc.writeTodos(w)
+		if token == "" {
+			continue
+		}
+
+		name := fmt.Sprintf("%sProvisioner%d", r.pulumiName, i)
+		if isNullResource && i == len(provisioners)-1 {
+			name = r.pulumiName
+		}
+
+		if triggers != nil {
+			items = append(items, newAttribute("triggers", triggers))
+		}
+
+		var options []model.BodyItem
+		if rangeValue != nil {
+			options = append(options, newAttribute("range", rangeValue))
+		}
+		var deps []model.Expression
+		switch {
+		case previous != "":
+			deps = append(deps, newTraversal(previous))
+		case !isNullResource:
+			deps = append(deps, newTraversal(r.pulumiName))
+		case dependsOn != nil:
+			options = append(options, newAttribute("dependsOn", dependsOn))
+		}
+		if len(deps) != 0 {
+			options = append(options, newAttribute("dependsOn", newTuple(deps...)))
+		}
+		if len(options) != 0 {
+			optionsBlock := &model.Block{
+				Tokens: syntax.NewBlockTokens("options"),
+				Type:   "options",
+				Body:   &model.Body{Items: options},
+			}
+			optionsBlock.Tokens.Type.LeadingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ', ' ')}
+			optionsBlock.Tokens.CloseBrace.LeadingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ', ' ')}
+			for _, item := range options {
+				attr := item.(*model.Attribute)
+				attr.Tokens.Name.LeadingTrivia = syntax.TriviaList{syntax.NewWhitespace(' ', ' ', ' ', ' ')}
+			}
+			items = append(items, optionsBlock)
+		}
+
+		block := &model.Block{
+			Tokens: syntax.NewBlockTokens("resource", name, token),
+			Type:   "resource",
+			Labels: []string{name, token},
+			Body:   &model.Body{Items: items},
+		}
+		block.Tokens.Type.LeadingTrivia = syntax.TriviaList{syntax.NewWhitespace('\n')}
+		block.Tokens.CloseBrace.LeadingTrivia = nil
+
+		_, err := fmt.Fprintf(w, "%v", block)
+		contract.IgnoreError(err)
+
+		previous = name
+	}
+	pc.writeTodos(w)
+
+	return pc.diagnostics
+}
+
+// writeTodos writes a TODO comment for each argument that could not be converted since the last call.
+func (pc *provisionerConverter) writeTodos(w io.Writer) {
+	writeTodos(w, pc.todos)
+	pc.todos = nil
+}
+
+func sortedAttributeNames(attrs map[string]*model.Attribute) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nullResourceType returns the type of a null_resource, which is converted into command resources rather than
+// looked up in a provider.
+func nullResourceType() model.Type {
+	return model.NewObjectType(map[string]model.Type{
+		"id":       model.StringType,
+		"triggers": model.NewMapType(model.StringType),
+	})
+}
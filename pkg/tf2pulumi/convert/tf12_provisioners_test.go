@@ -0,0 +1,184 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/test"
+)
+
+// resourceSpec returns the schema for a resource whose properties are all inputs and outputs of any type.
+func resourceSpec(properties ...string) schema.ResourceSpec {
+	spec := schema.ResourceSpec{
+		ObjectTypeSpec:  schema.ObjectTypeSpec{Type: "object", Properties: map[string]schema.PropertySpec{}},
+		InputProperties: map[string]schema.PropertySpec{},
+	}
+	for _, p := range properties {
+		spec.Properties[p], spec.InputProperties[p] = anyProperty(), anyProperty()
+	}
+	return spec
+}
+
+var provisionersLoader = specLoader{
+	"aws": {
+		Name:    "aws",
+		Version: "1.0.0",
+		Resources: map[string]schema.ResourceSpec{
+			"aws:ec2/instance:Instance": resourceSpec("ami", "instanceType", "privateIp", "publicIp"),
+		},
+	},
+	"command": {
+		Name:    "command",
+		Version: "1.0.0",
+		Resources: map[string]schema.ResourceSpec{
+			"command:local:Command": resourceSpec("create", "delete", "dir", "environment", "interpreter",
+				"triggers"),
+			"command:remote:Command":  resourceSpec("connection", "create", "delete", "triggers"),
+			"command:remote:CopyFile": resourceSpec("connection", "localPath", "remotePath", "triggers"),
+		},
+	},
+}
+
+func TestConvertProvisioners(t *testing.T) {
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(`
+resource "aws_instance" "web" {
+  ami           = "ami-12345"
+  instance_type = "t2.micro"
+
+  connection {
+    type        = "ssh"
+    host        = self.public_ip
+    user        = "ubuntu"
+    private_key = file("id_rsa")
+    timeout     = "2m"
+  }
+
+  provisioner "local-exec" {
+    command     = "echo ${self.private_ip} >> ips.txt"
+    working_dir = "/tmp"
+  }
+
+  provisioner "remote-exec" {
+    inline = ["sudo apt-get update", "sudo apt-get install -y nginx"]
+  }
+
+  provisioner "file" {
+    source      = "nginx.conf"
+    destination = "/etc/nginx/nginx.conf"
+  }
+
+  provisioner "local-exec" {
+    when       = destroy
+    command    = "echo destroyed"
+    on_failure = continue
+  }
+}
+
+resource "null_resource" "cluster" {
+  triggers = {
+    instance = aws_instance.web.id
+  }
+
+  provisioner "local-exec" {
+    command = "echo ${aws_instance.web.public_ip}"
+  }
+}
+
+resource "null_resource" "empty" {
+}
+`), 0600))
+
+	generated, diags, err := Convert(Options{
+		Root:                     root,
+		Loader:                   provisionersLoader,
+		ProviderInfoSource:       test.NewProviderInfoSource("../testdata/providers"),
+		SkipResourceTypechecking: true,
+		TargetLanguage:           LanguagePulumi,
+	})
+	require.NoError(t, err)
+	require.False(t, diags.All.HasErrors(), "%v", diags.All)
+
+	program := string(generated["main.tf.pp"])
+	assert.Contains(t, program, `resource webProvisioner0 "command:local:Command" {
+  create = "echo ${web.privateIp} >> ips.txt"
+  dir = "/tmp"
+  triggers = [web.id]
+  options {
+    dependsOn = [web]
+  }
+}`)
+	assert.Contains(t, program, `resource webProvisioner1 "command:remote:Command" {
+  create = join("\n", ["sudo apt-get update", "sudo apt-get install -y nginx"])
+  connection = { host = web.publicIp, privateKey = readFile("id_rsa"), user = "ubuntu" }`)
+	assert.Contains(t, program, `resource webProvisioner2 "command:remote:CopyFile" {
+  remotePath = "/etc/nginx/nginx.conf"
+  localPath = "nginx.conf"`)
+	assert.Contains(t, program, `resource webProvisioner3 "command:local:Command" {
+  delete = "echo destroyed"`)
+	assert.Contains(t, program, `resource cluster "command:local:Command" {
+  create = "echo ${web.publicIp}"
+  triggers = [{
+    instance = web.id
+  }]
+}`)
+
+	// Arguments that cannot be converted are reported as warnings and TODOs.
+	var warnings []string
+	for _, d := range diags.All {
+		assert.Equal(t, hcl.DiagWarning, d.Severity)
+		warnings = append(warnings, d.Summary)
+	}
+	assert.ElementsMatch(t, []string{
+		"connection argument timeout cannot be converted",
+		"on_failure = continue cannot be converted; failures will fail the deployment",
+		"null_resource empty has no provisioners and cannot be converted",
+	}, warnings)
+	for _, w := range warnings {
+		assert.Contains(t, program, "// TODO: "+w)
+	}
+
+	// The command tokens are configurable.
+	_, diags, err = Convert(Options{
+		Root: root,
+		Loader: specLoader{
+			"aws": provisionersLoader["aws"],
+			"command": {
+				Name:    "command",
+				Version: "1.0.0",
+				Resources: map[string]schema.ResourceSpec{
+					"command:index:Local":    resourceSpec("create", "delete", "dir", "triggers"),
+					"command:index:Remote":   resourceSpec("connection", "create", "triggers"),
+					"command:index:CopyFile": resourceSpec("connection", "localPath", "remotePath", "triggers"),
+				},
+			},
+		},
+		LocalCommandToken:        "command:index:Local",
+		RemoteCommandToken:       "command:index:Remote",
+		CopyFileToken:            "command:index:CopyFile",
+		ProviderInfoSource:       test.NewProviderInfoSource("../testdata/providers"),
+		SkipResourceTypechecking: true,
+		TargetLanguage:           LanguagePulumi,
+	})
+	require.NoError(t, err)
+	assert.False(t, diags.All.HasErrors(), "%v", diags.All)
+}
@@ -0,0 +1,225 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// stackReferenceToken is the token of the resource that terraform_remote_state data sources are converted into.
+const stackReferenceToken = "pulumi:pulumi:StackReference"
+
+// isRemoteState returns true if the given resource is a terraform_remote_state data source. These data sources are
+// built in to Terraform rather than provided by a plugin.
+func isRemoteState(r *resource) bool {
+	return r.isDataSource && r.typeName == "terraform_remote_state"
+}
+
+// remoteStateType returns the type of a terraform_remote_state data source.
+func remoteStateType() model.Type {
+	return model.NewObjectType(map[string]model.Type{
+		"backend":   model.StringType,
+		"config":    model.DynamicType,
+		"defaults":  model.DynamicType,
+		"outputs":   model.DynamicType,
+		"workspace": model.StringType,
+	})
+}
+
+// remoteStateConfig flattens the literal values in the config of a terraform_remote_state data source into a map.
+// The keys of nested values are joined with ".".
+func remoteStateConfig(prefix string, expr model.Expression, config map[string]string) {
+	obj, ok := expr.(*model.ObjectConsExpression)
+	if !ok {
+		return
+	}
+	for _, item := range obj.Items {
+		key, ok := literalStringValue(item.Key)
+		if !ok {
+			continue
+		}
+		if value, ok := literalStringValue(item.Value); ok {
+			config[prefix+key] = value
+			continue
+		}
+		remoteStateConfig(prefix+key+".", item.Value, config)
+	}
+}
+
+// defaultRemoteStateStack returns the name of the stack that replaces the state stored in a Terraform Cloud workspace.
+// The state of other backends has no default stack.
+func defaultRemoteStateStack(backend, workspace string, config map[string]string) (string, bool) {
+	switch backend {
+	case "remote", "cloud":
+		org, ok := config["organization"]
+		if !ok {
+			return "", false
+		}
+		if name, ok := config["workspaces.name"]; ok {
+			return org + "/" + name, true
+		}
+		if prefix, ok := config["workspaces.prefix"]; ok && workspace != "" {
+			return org + "/" + prefix + workspace, true
+		}
+	}
+	return "", false
+}
+
+// genRemoteState converts a terraform_remote_state data source into a stack reference. The name of the referenced
+// stack is determined by Options.RemoteStateStack.
+func (b *tf12binder) genRemoteState(w io.Writer, r *resource) hcl.Diagnostics {
+	var diagnostics hcl.Diagnostics
+	var todos []string
+
+	rng := r.syntax.DefRange()
+	if b.opts.TargetLanguage != LanguagePulumi {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("cannot convert terraform_remote_state %v to %v", r.name, b.opts.TargetLanguage),
+			Detail: "terraform_remote_state data sources are converted to stack references, which are not yet " +
+				"supported by the code generator for this language; convert to PCL instead",
+			Subject: &rng,
+		}}
+	}
+	if r.rangeVariable != nil {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("cannot convert terraform_remote_state %v", r.name),
+			Detail:   "terraform_remote_state data sources with count or for_each cannot be converted",
+			Subject:  &rng,
+		}}
+	}
+
+	var backend, workspace string
+	config := map[string]string{}
+	if attr, ok := r.block.Body.Attribute("backend"); ok {
+		backend, _ = literalStringValue(attr.Value)
+	}
+	if attr, ok := r.block.Body.Attribute("workspace"); ok {
+		workspace, _ = literalStringValue(attr.Value)
+	}
+	if attr, ok := r.block.Body.Attribute("config"); ok {
+		remoteStateConfig("", attr.Value, config)
+	}
+	if attr, ok := r.block.Body.Attribute("defaults"); ok {
+		subject := attr.Syntax.Range()
+		diagnostics = append(diagnostics, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "defaults cannot be converted",
+			Detail:   "stack references have no default outputs",
+			Subject:  &subject,
+		})
+		todos = append(todos, fmt.Sprintf("outputs missing from the stack referenced by %v default to %v", r.pulumiName,
+			strings.TrimSpace(fmt.Sprintf("%v", attr.Value))))
+	}
+
+	var stack string
+	ok := false
+	if b.opts.RemoteStateStack != nil {
+		stack, ok = b.opts.RemoteStateStack(backend, workspace, config)
+	}
+	if !ok {
+		stack, ok = defaultRemoteStateStack(backend, workspace, config)
+	}
+	if !ok {
+		stack = r.name
+		diagnostics = append(diagnostics, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  fmt.Sprintf("no stack for terraform_remote_state %v", r.name),
+			Detail: fmt.Sprintf("the state of the %v backend is not mapped to a stack; referencing stack %v "+
+				"instead", backend, stack),
+			Subject: &rng,
+		})
+		todos = append(todos, fmt.Sprintf("set the name of the stack that replaces the %v state read by %v", backend,
+			r.pulumiName))
+	}
+
+	block := &model.Block{
+		Tokens: syntax.NewBlockTokens("resource", r.pulumiName, stackReferenceToken),
+		Type:   "resource",
+		Labels: []string{r.pulumiName, stackReferenceToken},
+		Body:   &model.Body{Items: []model.BodyItem{newAttribute("name", newTemplate(stack))}},
+	}
+	block.Tokens.CloseBrace.LeadingTrivia = nil
+	if len(todos) == 0 {
+		block.Tokens.Type.LeadingTrivia = syntax.TriviaList{syntax.NewWhitespace('\n')}
+	}
+
+	writeTodos(w, todos)
+	_, err := fmt.Fprintf(w, "%v", block)
+	contract.IgnoreError(err)
+	b.unboundConstructs.Add("stack references")
+
+	return diagnostics
+}
+
+// rewriteRemoteStateOutput rewrites a reference to an output of a terraform_remote_state data source into a call to
+// getOutput on the stack reference that replaces it:
+//
+//	data.terraform_remote_state.network.outputs.vpc_id => getOutput(network, "vpc_id")
+func (b *tf12binder) rewriteRemoteStateOutput(n *model.ScopeTraversalExpression) (model.Expression, bool) {
+	for i, p := range n.Parts {
+		r, ok := p.(*resource)
+		if !ok || !isRemoteState(r) {
+			continue
+		}
+
+		rest := n.Traversal[i+1:]
+		if len(rest) < 2 {
+			return nil, false
+		}
+		if attr, ok := rest[0].(hcl.TraverseAttr); !ok || attr.Name != "outputs" {
+			return nil, false
+		}
+
+		var name string
+		switch traverser := rest[1].(type) {
+		case hcl.TraverseAttr:
+			name = traverser.Name
+		case hcl.TraverseIndex:
+			if !traverser.Key.Type().Equals(cty.String) {
+				return nil, false
+			}
+			name = traverser.Key.AsString()
+		default:
+			return nil, false
+		}
+
+		var result model.Expression = newCall("getOutput", newTraversal(r.pulumiName), newTemplate(name))
+		if rest = rest[2:]; len(rest) != 0 {
+			parts := make([]model.Traversable, len(rest))
+			for i := range parts {
+				parts[i] = model.DynamicType
+			}
+			result = &model.RelativeTraversalExpression{
+				Source:    result,
+				Traversal: rest,
+				Parts:     parts,
+			}
+		}
+		result.SetLeadingTrivia(n.GetLeadingTrivia())
+		result.SetTrailingTrivia(n.GetTrailingTrivia())
+		return result, true
+	}
+	return nil, false
+}
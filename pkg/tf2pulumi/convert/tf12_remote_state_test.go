@@ -0,0 +1,111 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/test"
+)
+
+func TestConvertRemoteState(t *testing.T) {
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(`
+data "terraform_remote_state" "network" {
+  backend = "remote"
+
+  config = {
+    organization = "acme"
+    workspaces = {
+      name = "network-prod"
+    }
+  }
+}
+
+data "terraform_remote_state" "database" {
+  backend = "s3"
+
+  config = {
+    bucket = "acme-state"
+    key    = "database/terraform.tfstate"
+  }
+}
+
+data "terraform_remote_state" "legacy" {
+  backend = "local"
+
+  config = {
+    path = "../legacy/terraform.tfstate"
+  }
+}
+
+resource "aws_instance" "web" {
+  ami           = data.terraform_remote_state.database.outputs["ami_id"]
+  instance_type = data.terraform_remote_state.legacy.outputs.instance_type
+  private_ip    = data.terraform_remote_state.network.outputs.private_ips[0]
+}
+
+output "vpc_id" {
+  value = data.terraform_remote_state.network.outputs.vpc_id
+}
+`), 0600))
+
+	options := Options{
+		Root:   root,
+		Loader: specLoader{"aws": provisionersLoader["aws"]},
+		RemoteStateStack: func(backend, workspace string, config map[string]string) (string, bool) {
+			if backend == "s3" && config["key"] == "database/terraform.tfstate" {
+				return "acme/database/prod", true
+			}
+			return "", false
+		},
+		ProviderInfoSource:       test.NewProviderInfoSource("../testdata/providers"),
+		SkipResourceTypechecking: true,
+		TargetLanguage:           LanguagePulumi,
+	}
+	generated, diags, err := Convert(options)
+	require.NoError(t, err)
+	require.False(t, diags.All.HasErrors(), "%v", diags.All)
+
+	program := string(generated["main.tf.pp"])
+	assert.Contains(t, program, `resource network "pulumi:pulumi:StackReference" {
+  name = "acme/network-prod"
+}`)
+	assert.Contains(t, program, `resource database "pulumi:pulumi:StackReference" {
+  name = "acme/database/prod"
+}`)
+	assert.Contains(t, program, `// TODO: set the name of the stack that replaces the local state read by legacy
+resource legacy "pulumi:pulumi:StackReference" {
+  name = "legacy"
+}`)
+	assert.Contains(t, program, `ami           = getOutput(database, "ami_id")`)
+	assert.Contains(t, program, `instanceType = getOutput(legacy, "instance_type")`)
+	assert.Contains(t, program, `privateIp    = getOutput(network, "private_ips")[0]`)
+	assert.Contains(t, program, `value = getOutput(network, "vpc_id")`)
+
+	require.Len(t, diags.All, 2)
+	assert.Equal(t, "no stack for terraform_remote_state legacy", diags.All[0].Summary)
+	assert.Equal(t, "the converted program has not been checked", diags.All[1].Summary)
+
+	// Stack references can only be converted to PCL.
+	options.TargetLanguage = LanguageTypescript
+	_, diags, err = Convert(options)
+	require.NoError(t, err)
+	assert.True(t, diags.All.HasErrors())
+}
@@ -0,0 +1,300 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/pulumi/pulumi/pkg/v3/codegen"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// defaultWorkspace is the name of Terraform's default workspace. The stack that replaces it has the same name.
+const defaultWorkspace = "default"
+
+// defineWorkspace defines `terraform.workspace`, which is converted into a call to the stack intrinsic.
+func (b *tf12binder) defineWorkspace() {
+	terraform, _ := b.root.DefineScope("terraform", syntax.None)
+	b.workspace = &model.Variable{Name: "workspace", VariableType: model.StringType}
+	terraform.Define(b.workspace.Name, b.workspace)
+}
+
+// rewriteWorkspace rewrites a reference to `terraform.workspace` into a call to the stack intrinsic.
+func (b *tf12binder) rewriteWorkspace(n *model.ScopeTraversalExpression) (model.Expression, bool) {
+	if len(n.Parts) != 2 || n.Parts[1] != b.workspace {
+		return nil, false
+	}
+
+	call := newCall("stack")
+	call.SetLeadingTrivia(n.GetLeadingTrivia())
+	call.SetTrailingTrivia(n.GetTrailingTrivia())
+	return call, true
+}
+
+// isTFVarsFile returns true if the given file holds variable values.
+func isTFVarsFile(name string) bool {
+	return strings.HasSuffix(name, ".tfvars") || strings.HasSuffix(name, ".tfvars.json")
+}
+
+// isAutoTFVarsFile returns true if Terraform loads the given variables file automatically.
+func isAutoTFVarsFile(name string) bool {
+	return name == "terraform.tfvars" || name == "terraform.tfvars.json" ||
+		strings.HasSuffix(name, ".auto.tfvars") || strings.HasSuffix(name, ".auto.tfvars.json")
+}
+
+// findTFVars finds the variables files in the root of the given filesystem. As in Terraform, terraform.tfvars and
+// *.auto.tfvars make up the values for the default workspace. Every other variables file adds to these the values for
+// the workspace named after the file.
+func findTFVars(fs afero.Fs) (map[string][]string, error) {
+	infos, err := afero.ReadDir(fs, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	var auto, named []string
+	for _, info := range infos {
+		name := info.Name()
+		switch {
+		case info.IsDir() || !isTFVarsFile(name):
+			continue
+		case isAutoTFVarsFile(name):
+			auto = append(auto, name)
+		default:
+			named = append(named, name)
+		}
+	}
+
+	// terraform.tfvars is loaded before the *.auto.tfvars files, which are loaded in lexical order.
+	sort.Slice(auto, func(i, j int) bool {
+		iDefault, jDefault := strings.HasPrefix(auto[i], "terraform."), strings.HasPrefix(auto[j], "terraform.")
+		if iDefault != jDefault {
+			return iDefault
+		}
+		return auto[i] < auto[j]
+	})
+
+	stacks := map[string][]string{}
+	if len(auto) != 0 {
+		stacks[defaultWorkspace] = auto
+	}
+	for _, name := range named {
+		stack := strings.TrimSuffix(strings.TrimSuffix(name, ".json"), ".tfvars")
+		stacks[stack] = append(append([]string{}, auto...), name)
+	}
+	return stacks, nil
+}
+
+// readTFVars reads the values in the given variables file.
+func readTFVars(fs afero.Fs, filename string) (map[string]*hcl.Attribute, hcl.Diagnostics) {
+	contents, err := afero.ReadFile(fs, path.Join("/", filename))
+	if err != nil {
+		return nil, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("failed to read file %s", filename),
+			Detail:   err.Error(),
+		}}
+	}
+
+	parser := hclparse.NewParser()
+	var f *hcl.File
+	var diagnostics hcl.Diagnostics
+	if strings.HasSuffix(filename, ".json") {
+		f, diagnostics = parser.ParseJSON(contents, filename)
+	} else {
+		f, diagnostics = parser.ParseHCL(contents, filename)
+	}
+	if diagnostics.HasErrors() {
+		return nil, diagnostics
+	}
+
+	attrs, diags := f.Body.JustAttributes()
+	return attrs, append(diagnostics, diags...)
+}
+
+// configValue converts a variable value into a config value. Strings, numbers and bools are stored as strings; other
+// values are stored as structured config.
+func configValue(v cty.Value) (config.Value, error) {
+	switch {
+	case v.Type() == cty.String:
+		return config.NewValue(v.AsString()), nil
+	case v.Type() == cty.Number:
+		f := v.AsBigFloat()
+		if f.IsInt() {
+			i, _ := f.Int(nil)
+			return config.NewValue(i.String()), nil
+		}
+		return config.NewValue(f.Text('g', -1)), nil
+	case v.Type() == cty.Bool:
+		if v.True() {
+			return config.NewValue("true"), nil
+		}
+		return config.NewValue("false"), nil
+	default:
+		bytes, err := ctyjson.SimpleJSONValue{Value: v}.MarshalJSON()
+		if err != nil {
+			return config.Value{}, err
+		}
+		return config.NewObjectValue(string(bytes)), nil
+	}
+}
+
+// genStackConfigs converts the values in the given variables files into the config of the stacks that replace the
+// corresponding workspaces. The config for each stack is returned as the contents of its Pulumi.<stack>.yaml file.
+// Config keys are the names of the config variables that replace the module's variables.
+func (b *tf12binder) genStackConfigs(files []*file) (map[string][]byte, hcl.Diagnostics) {
+	tfvars := b.opts.TFVars
+	if tfvars == nil {
+		found, err := findTFVars(b.opts.Root)
+		if err != nil {
+			return nil, hcl.Diagnostics{{
+				Severity: hcl.DiagError,
+				Summary:  "failed to find tfvars files",
+				Detail:   err.Error(),
+			}}
+		}
+		tfvars = found
+	}
+	if len(tfvars) == 0 {
+		return nil, nil
+	}
+	if b.opts.ProjectName == "" {
+		return nil, hcl.Diagnostics{{
+			Severity: hcl.DiagWarning,
+			Summary:  "cannot convert tfvars files without a project name",
+			Detail:   "stack config keys are namespaced by the name of the project; set Options.ProjectName",
+		}}
+	}
+
+	variables := map[string]*variable{}
+	for _, f := range files {
+		for _, n := range f.nodes {
+			if v, ok := n.(*variable); ok {
+				variables[v.name] = v
+			}
+		}
+	}
+
+	// Convert the values in each file. Files may be shared by several stacks.
+	var diagnostics hcl.Diagnostics
+	fileValues := map[string]config.Map{}
+	for _, stack := range codegen.SortedKeys(tfvars) {
+		for _, filename := range tfvars[stack] {
+			if _, ok := fileValues[filename]; ok {
+				continue
+			}
+			values, diags := b.convertTFVars(filename, variables)
+			fileValues[filename], diagnostics = values, append(diagnostics, diags...)
+		}
+	}
+
+	configs := map[string][]byte{}
+	for _, stack := range codegen.SortedKeys(tfvars) {
+		values := config.Map{}
+		for _, filename := range tfvars[stack] {
+			for k, v := range fileValues[filename] {
+				values[k] = v
+			}
+		}
+
+		bytes, err := encoding.YAML.Marshal(&workspace.ProjectStack{Config: values})
+		if err != nil {
+			diagnostics = append(diagnostics, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("failed to encode the config for stack %v", stack),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		configs[fmt.Sprintf("Pulumi.%s.yaml", stack)] = bytes
+	}
+	return configs, diagnostics
+}
+
+// convertTFVars converts the values in the given variables file into config values.
+func (b *tf12binder) convertTFVars(filename string, variables map[string]*variable) (config.Map, hcl.Diagnostics) {
+	attrs, diagnostics := readTFVars(b.opts.Root, filename)
+
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := config.Map{}
+	for _, name := range names {
+		attr := attrs[name]
+		v, ok := variables[name]
+		if !ok {
+			diagnostics = append(diagnostics, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("value for undeclared variable %v", name),
+				Detail:   fmt.Sprintf("%v sets a value for the variable %v, which is not declared", filename, name),
+				Subject:  &attr.NameRange,
+			})
+			continue
+		}
+
+		value, diags := attr.Expr.Value(nil)
+		diagnostics = append(diagnostics, diags...)
+		if diags.HasErrors() || value.IsNull() {
+			continue
+		}
+
+		key, err := config.ParseKey(b.opts.ProjectName + ":" + v.pulumiName)
+		if err != nil {
+			diagnostics = append(diagnostics, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("invalid config key for variable %v", name),
+				Detail:   err.Error(),
+				Subject:  &attr.NameRange,
+			})
+			continue
+		}
+
+		configValue, err := configValue(value)
+		if err != nil {
+			diagnostics = append(diagnostics, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("cannot convert the value of variable %v", name),
+				Detail:   err.Error(),
+				Subject:  &attr.NameRange,
+			})
+			continue
+		}
+		if v.sensitive {
+			diagnostics = append(diagnostics, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("the value of sensitive variable %v is stored in plaintext", name),
+				Detail:   fmt.Sprintf("run `pulumi config set --secret %v` to encrypt it", v.pulumiName),
+				Subject:  &attr.NameRange,
+			})
+		}
+
+		values[key] = configValue
+	}
+	return values, diagnostics
+}
@@ -0,0 +1,104 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/test"
+)
+
+func TestConvertTFVars(t *testing.T) {
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(`
+variable "instance_type" {
+  type = string
+}
+
+variable "instance_count" {
+  type = number
+}
+
+variable "tags" {
+  type = map(string)
+}
+
+resource "aws_instance" "web" {
+  instance_type = var.instance_type
+  tags          = merge(var.tags, { Workspace = terraform.workspace })
+}
+`), 0600))
+	require.NoError(t, afero.WriteFile(root, "/terraform.tfvars", []byte(`
+instance_type  = "t2.micro"
+instance_count = 1
+`), 0600))
+	require.NoError(t, afero.WriteFile(root, "/tags.auto.tfvars.json", []byte(`{"tags": {"team": "web"}}`), 0600))
+	require.NoError(t, afero.WriteFile(root, "/prod.tfvars", []byte(`
+instance_type  = "m5.large"
+instance_count = 3
+unknown        = true
+`), 0600))
+
+	options := Options{
+		Root:                     root,
+		Loader:                   specLoader{"aws": provisionersLoader["aws"], "std": stdSpec},
+		ProjectName:              "web",
+		ProviderInfoSource:       test.NewProviderInfoSource("../testdata/providers"),
+		SkipResourceTypechecking: true,
+		TargetLanguage:           LanguagePulumi,
+	}
+	generated, diags, err := Convert(options)
+	require.NoError(t, err)
+	require.Len(t, diags.All, 1)
+	assert.Equal(t, "value for undeclared variable unknown", diags.All[0].Summary)
+
+	// terraform.workspace is the name of the stack.
+	assert.Contains(t, string(generated["main.tf.pp"]), `Workspace = stack()`)
+
+	// The auto-loaded files make up the default stack; other files add to them.
+	assert.Equal(t, `config:
+  web:instanceCount: "1"
+  web:instanceType: t2.micro
+  web:tags:
+    team: web
+`, string(generated["Pulumi.default.yaml"]))
+	assert.Equal(t, `config:
+  web:instanceCount: "3"
+  web:instanceType: m5.large
+  web:tags:
+    team: web
+`, string(generated["Pulumi.prod.yaml"]))
+
+	// Stacks may also be listed explicitly.
+	options.TFVars = map[string][]string{"staging": {"prod.tfvars", "terraform.tfvars"}}
+	generated, _, err = Convert(options)
+	require.NoError(t, err)
+	assert.NotContains(t, generated, "Pulumi.default.yaml")
+	assert.Equal(t, `config:
+  web:instanceCount: "1"
+  web:instanceType: t2.micro
+`, string(generated["Pulumi.staging.yaml"]))
+
+	// Project names that cannot namespace a config key are reported rather than causing a panic.
+	options.ProjectName = "web:app"
+	_, diags, err = Convert(options)
+	require.NoError(t, err)
+	require.True(t, diags.All.HasErrors())
+	assert.Equal(t, "invalid config key for variable instance_count", diags.All[0].Summary)
+}
@@ -0,0 +1,189 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// variableScopes binds the contents of a variable block. The conditions of validation blocks may refer to the variable
+// being validated and to TF built-in functions.
+type variableScopes struct {
+	root *model.Scope
+}
+
+func (s variableScopes) GetScopesForBlock(block *hclsyntax.Block) (model.Scopes, hcl.Diagnostics) {
+	if block.Type == "validation" {
+		return model.StaticScope(s.root), nil
+	}
+	return model.StaticScope(nil), nil
+}
+
+func (s variableScopes) GetScopeForAttribute(attribute *hclsyntax.Attribute) (*model.Scope, hcl.Diagnostics) {
+	if attribute.Name == "type" {
+		return model.TypeScope, nil
+	}
+	return nil, nil
+}
+
+// literalBoolValue returns the value of the given expression if it is a bool literal.
+func literalBoolValue(expr model.Expression) (bool, bool) {
+	if lit, ok := expr.(*model.LiteralValueExpression); ok && lit.Value.Type() == cty.Bool && lit.Value.IsKnown() &&
+		!lit.Value.IsNull() {
+		return lit.Value.True(), true
+	}
+	return false, false
+}
+
+// isNullLiteral returns true if the given expression is the null literal.
+func isNullLiteral(expr model.Expression) bool {
+	lit, ok := expr.(*model.LiteralValueExpression)
+	return ok && lit.Value.IsNull()
+}
+
+// bindVariableAttributes records the sensitive and nullable attributes of a variable. As in Terraform, variables are
+// nullable unless they set `nullable = false`.
+func bindVariableAttributes(v *variable) {
+	v.nullable = true
+	if attr, ok := v.block.Body.Attribute("nullable"); ok {
+		if nullable, ok := literalBoolValue(attr.Value); ok {
+			v.nullable = nullable
+		}
+	}
+	if attr, ok := v.block.Body.Attribute("sensitive"); ok {
+		v.sensitive, _ = literalBoolValue(attr.Value)
+	}
+}
+
+// defaultsToNull returns true if the given variable is nullable and defaults to null. Config types cannot express
+// the absence of a value, so the config for such a variable is untyped and defaults to null. Untyped config is read
+// as JSON.
+func defaultsToNull(v *variable) bool {
+	defaultValue, hasDefault := v.block.Body.Attribute("default")
+	return hasDefault && isNullLiteral(defaultValue.Value) && v.nullable
+}
+
+// variableConfigItems returns the body of the config block for a variable, which holds its default value, if any. A
+// variable that defaults to null keeps that default only if it is nullable. Otherwise, as in Terraform, the variable
+// is required.
+//
+// Config blocks have no way to mark config as secret that every target language understands, so the values of
+// sensitive variables are instead made secret wherever they are referenced.
+func (b *tf12binder) variableConfigItems(v *variable) ([]model.BodyItem, hcl.Diagnostics) {
+	defaultValue, hasDefault := v.block.Body.Attribute("default")
+	switch {
+	case !hasDefault || isNullLiteral(defaultValue.Value) && !v.nullable:
+		return nil, nil
+	case isNullLiteral(defaultValue.Value):
+		return []model.BodyItem{defaultValue}, nil
+	default:
+		dv, diagnostics := b.rewriteExpression(defaultValue.Value, nil)
+		defaultValue.Value = dv
+		return []model.BodyItem{defaultValue}, diagnostics
+	}
+}
+
+// genValidations converts the validation blocks of a variable into TODOs that hold each block's condition and error
+// message. Pulumi programs have no way to fail with a message, so the rules are not checked when the program runs,
+// and a warning is reported for each variable that has them.
+func (b *tf12binder) genValidations(w io.Writer, v *variable) hcl.Diagnostics {
+	var diagnostics hcl.Diagnostics
+
+	var validations []*model.Block
+	for _, item := range v.block.Body.Items {
+		if block, ok := item.(*model.Block); ok && block.Type == "validation" {
+			validations = append(validations, block)
+		}
+	}
+	if len(validations) == 0 {
+		return nil
+	}
+
+	b.validating = true
+	defer func() { b.validating = false }()
+
+	var todos []string
+	for _, validation := range validations {
+		condition, hasCondition := validation.Body.Attribute("condition")
+		message, hasMessage := validation.Body.Attribute("error_message")
+		if !hasCondition || !hasMessage {
+			rng := validation.Syntax.Range()
+			diagnostics = append(diagnostics, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("invalid validation for variable %v", v.name),
+				Detail:   "validation blocks must set both condition and error_message",
+				Subject:  &rng,
+			})
+			continue
+		}
+
+		c, diags := b.rewriteExpression(condition.Value, nil)
+		diagnostics = append(diagnostics, diags...)
+		m, diags := b.rewriteExpression(message.Value, nil)
+		diagnostics = append(diagnostics, diags...)
+
+		todos = append(todos, fmt.Sprintf("check that %v\nerror message: %v",
+			strings.TrimSpace(fmt.Sprintf("%v", c)), strings.TrimSpace(fmt.Sprintf("%v", m))))
+	}
+	if len(todos) == 0 {
+		return diagnostics
+	}
+
+	writeTodos(w, todos)
+	_, err := fmt.Fprintf(w, "\n")
+	contract.IgnoreError(err)
+
+	rng := v.block.Syntax.Range()
+	return append(diagnostics, &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  fmt.Sprintf("the validation rules of variable %v are not checked", v.name),
+		Detail:   "Pulumi programs cannot check the validation rules of their config; each rule is recorded in a TODO.",
+		Subject:  &rng,
+	})
+}
+
+// referencedVariable returns the variable referenced by the given traversal, if any.
+func referencedVariable(n *model.ScopeTraversalExpression) (*variable, bool) {
+	for _, p := range n.Parts {
+		if _, ok := p.(*model.Scope); ok {
+			continue
+		}
+		v, ok := p.(*variable)
+		return v, ok
+	}
+	return nil, false
+}
+
+// rewriteSensitiveReference wraps a reference to a sensitive variable in a call to the secret intrinsic so that the
+// value remains secret wherever it is used. References within validation rules are left as-is.
+func (b *tf12binder) rewriteSensitiveReference(n model.Expression, v *variable) model.Expression {
+	if b.validating || v == nil || !v.sensitive {
+		return n
+	}
+
+	leading, trailing := n.GetLeadingTrivia(), n.GetTrailingTrivia()
+	call := newCall("secret", n)
+	call.SetLeadingTrivia(leading)
+	call.SetTrailingTrivia(trailing)
+	return call
+}
@@ -0,0 +1,114 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/test"
+)
+
+func TestConvertVariables(t *testing.T) {
+	root := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(root, "/main.tf", []byte(`
+variable "image_id" {
+  type      = string
+  sensitive = true
+  nullable  = false
+
+  validation {
+    condition     = length(var.image_id) > 4
+    error_message = "The image_id value must be a valid AMI id."
+  }
+}
+
+variable "instance_type" {
+  type    = string
+  default = null
+}
+
+variable "private_ip" {
+  type     = string
+  default  = "10.0.0.1"
+  nullable = true
+
+  validation {
+    condition     = length(var.private_ip) > 0
+    error_message = "The private_ip value must not be empty."
+  }
+
+  validation {
+    condition     = length(var.private_ip) < 16
+    error_message = "The private_ip value must be an IPv4 address."
+  }
+}
+
+resource "aws_instance" "web" {
+  ami           = var.image_id
+  instance_type = var.instance_type
+  private_ip    = var.private_ip
+}
+`), 0600))
+
+	options := Options{
+		Root:                     root,
+		Loader:                   specLoader{"aws": provisionersLoader["aws"], "std": stdSpec},
+		ProviderInfoSource:       test.NewProviderInfoSource("../testdata/providers"),
+		SkipResourceTypechecking: true,
+		TargetLanguage:           LanguagePulumi,
+	}
+	generated, diags, err := Convert(options)
+	require.NoError(t, err)
+
+	program := string(generated["main.tf.pp"])
+
+	// Sensitive variables are wrapped in secret() wherever they are used.
+	assert.Contains(t, program, "config imageId string {\n}")
+	assert.Contains(t, program, `ami           = secret(imageId)`)
+
+	// Validation rules are recorded in TODOs, which refer to the plain value, and are reported as unchecked.
+	assert.Contains(t, program, `// TODO: check that length(imageId) > 4
+//   error message: "The image_id value must be a valid AMI id."`)
+	assert.Contains(t, program, `// TODO: check that length(privateIp) > 0`)
+	assert.Contains(t, program, `// TODO: check that length(privateIp) < 16`)
+	require.Len(t, diags.All, 2)
+	for _, diag := range diags.All {
+		assert.Equal(t, hcl.DiagWarning, diag.Severity)
+		assert.Contains(t, diag.Summary, "are not checked")
+	}
+
+	// Nullable variables that default to null are untyped config that defaults to null.
+	assert.Contains(t, program, `config instanceType {
+  default = null
+}`)
+	assert.Contains(t, program, `config privateIp string {
+  default  = "10.0.0.1"
+}`)
+
+	// The same holds for the other target languages.
+	options.TargetLanguage = LanguageTypescript
+	generated, _, err = Convert(options)
+	require.NoError(t, err)
+
+	index := string(generated["index.ts"])
+	assert.Contains(t, index, `const imageId = config.require("imageId");`)
+	assert.Contains(t, index, `ami: pulumi.secret(imageId),`)
+	assert.Contains(t, index, `const instanceType = config.getObject("instanceType") || undefined;`)
+}
@@ -0,0 +1,184 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package il
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// ProviderInfoDirEnvVar is the environment variable that names a directory of serialized provider info. If it is set,
+// provider info is read from the directory instead of from plugins, and the npm registry is never queried.
+const ProviderInfoDirEnvVar = "PULUMI_PROVIDER_INFO_DIR"
+
+// fileProviderInfo is a file of serialized provider info.
+type fileProviderInfo struct {
+	path                               string
+	registry, namespace, name, version string
+}
+
+// matches returns true if the file holds the info for the indicated provider. Empty fields match any value.
+func (f fileProviderInfo) matches(registry, namespace, name, version string) bool {
+	match := func(want, have string) bool {
+		return want == "" || have == "" || want == have
+	}
+	return f.name == name && match(registry, f.registry) && match(namespace, f.namespace) &&
+		match(version, f.version)
+}
+
+// newer returns true if the file holds a newer version of its provider than the other file.
+func (f fileProviderInfo) newer(other fileProviderInfo) bool {
+	v, err := semver.ParseTolerant(f.version)
+	if err != nil {
+		return false
+	}
+	o, err := semver.ParseTolerant(other.version)
+	if err != nil {
+		return true
+	}
+	return v.GT(o)
+}
+
+// fileProviderInfoSource reads provider info from MarshallableProviderInfo JSON files. Files are laid out as one of
+//
+//   - <name>.json
+//   - <name>/<version>.json
+//   - <namespace>/<name>/<version>.json
+//   - <registry>/<namespace>/<name>/<version>.json
+//
+// If more than one file matches a request, the newest version is used.
+type fileProviderInfoSource struct {
+	m sync.Mutex
+
+	fs      fs.FS
+	files   []fileProviderInfo
+	entries map[string]*tfbridge.ProviderInfo
+}
+
+// NewFileProviderInfoSource creates a new ProviderInfoSource that reads serialized provider info from the given
+// filesystem. The filesystem may be an embedded bundle. The source never runs plugins or accesses the network.
+func NewFileProviderInfoSource(fsys fs.FS) ProviderInfoSource {
+	return &fileProviderInfoSource{fs: fsys}
+}
+
+// NewDirectoryProviderInfoSource creates a new ProviderInfoSource that reads serialized provider info from the given
+// directory.
+func NewDirectoryProviderInfoSource(dir string) ProviderInfoSource {
+	return NewFileProviderInfoSource(os.DirFS(dir))
+}
+
+// NewProviderInfoSourceFromEnv returns a ProviderInfoSource for the directory named by PULUMI_PROVIDER_INFO_DIR, or nil
+// if the variable is not set.
+func NewProviderInfoSourceFromEnv() ProviderInfoSource {
+	if dir := os.Getenv(ProviderInfoDirEnvVar); dir != "" {
+		return NewDirectoryProviderInfoSource(dir)
+	}
+	return nil
+}
+
+// index lists the files in the source's filesystem. Files whose paths do not follow the source's layout are ignored.
+func (s *fileProviderInfoSource) index() error {
+	if s.entries != nil {
+		return nil
+	}
+
+	var files []fileProviderInfo
+	err := fs.WalkDir(s.fs, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".json" {
+			return nil
+		}
+
+		parts := strings.Split(strings.TrimSuffix(p, ".json"), "/")
+		f := fileProviderInfo{path: p}
+		switch len(parts) {
+		case 1:
+			f.name = parts[0]
+		case 2:
+			f.name, f.version = parts[0], parts[1]
+		case 3:
+			f.namespace, f.name, f.version = parts[0], parts[1], parts[2]
+		case 4:
+			f.registry, f.namespace, f.name, f.version = parts[0], parts[1], parts[2], parts[3]
+		default:
+			return nil
+		}
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list provider info")
+	}
+
+	s.files, s.entries = files, map[string]*tfbridge.ProviderInfo{}
+	return nil
+}
+
+// GetProviderInfo returns the tfbridge information for the indicated Terraform provider.
+func (s *fileProviderInfoSource) GetProviderInfo(
+	registryName, namespace, name, version string) (*tfbridge.ProviderInfo, error) {
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.index(); err != nil {
+		return nil, err
+	}
+
+	var file *fileProviderInfo
+	for i, f := range s.files {
+		if f.matches(registryName, namespace, name, version) && (file == nil || f.newer(*file)) {
+			file = &s.files[i]
+		}
+	}
+	if file == nil {
+		return nil, fmt.Errorf("could not find provider info for provider %s", name)
+	}
+
+	if info, ok := s.entries[file.path]; ok {
+		return info, nil
+	}
+
+	f, err := s.fs.Open(file.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read provider info for provider %s", name)
+	}
+	defer contract.IgnoreClose(f)
+
+	var m tfbridge.MarshallableProviderInfo
+	if err = jsoniter.NewDecoder(f).Decode(&m); err != nil {
+		return nil, errors.Wrapf(err, "could not decode provider info for provider %s", name)
+	}
+	if err = m.CheckFormatVersion(); err != nil {
+		return nil, err
+	}
+
+	info := m.Unmarshal()
+	s.entries[file.path] = info
+	return info, nil
+}
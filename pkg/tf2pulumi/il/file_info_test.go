@@ -0,0 +1,88 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package il
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func providerInfoFile(name, version string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(`{"name":"` + name + `","version":"` + version + `"}`)}
+}
+
+func TestFileProviderInfoSource(t *testing.T) {
+	source := NewFileProviderInfoSource(fstest.MapFS{
+		"random.json":                                      providerInfoFile("random", "3.1.0"),
+		"aws/4.0.0.json":                                   providerInfoFile("aws", "4.0.0"),
+		"aws/4.10.0.json":                                  providerInfoFile("aws", "4.10.0"),
+		"hashicorp/google/4.1.0.json":                      providerInfoFile("google", "4.1.0"),
+		"example/google/5.0.0.json":                        providerInfoFile("google", "5.0.0"),
+		"registry.example.com/acme/widgets/1.0.0.json":     providerInfoFile("widgets", "1.0.0"),
+		"registry.example.com/acme/widgets/not-json.txt":   providerInfoFile("widgets", "2.0.0"),
+		"too/many/path/components/for/the/layout/any.json": providerInfoFile("any", "1.0.0"),
+	})
+
+	cases := []struct {
+		registry, namespace, name, version string
+		expected                           string
+	}{
+		{name: "random", expected: "3.1.0"},
+		{name: "random", version: "3.1.0", expected: "3.1.0"},
+		{name: "aws", expected: "4.10.0"},
+		{name: "aws", version: "4.0.0", expected: "4.0.0"},
+		{namespace: "hashicorp", name: "google", expected: "4.1.0"},
+		{name: "google", expected: "5.0.0"},
+		{registry: "registry.example.com", namespace: "acme", name: "widgets", expected: "1.0.0"},
+	}
+	for _, c := range cases {
+		info, err := source.GetProviderInfo(c.registry, c.namespace, c.name, c.version)
+		require.NoError(t, err)
+		assert.Equal(t, c.name, info.Name)
+		assert.Equal(t, c.expected, info.Version)
+	}
+
+	for _, name := range []string{"missing", "any"} {
+		_, err := source.GetProviderInfo("", "", name, "")
+		assert.EqualError(t, err, "could not find provider info for provider "+name)
+	}
+
+	_, err := source.GetProviderInfo("", "", "aws", "5.0.0")
+	assert.Error(t, err)
+	_, err = source.GetProviderInfo("registry.example.com", "other", "widgets", "")
+	assert.Error(t, err)
+}
+
+func TestMultiProviderInfoSourceWithoutPlugins(t *testing.T) {
+	source := NewMultiProviderInfoSource(NewFileProviderInfoSource(fstest.MapFS{
+		"aws.json": providerInfoFile("aws", "4.0.0"),
+	}))
+
+	info, err := source.GetProviderInfo("", "", "aws", "")
+	require.NoError(t, err)
+	assert.Equal(t, "4.0.0", info.Version)
+
+	_, err = source.GetProviderInfo("", "", "random", "")
+	assert.EqualError(t, err, "could not find provider info for provider random")
+}
+
+func TestMissingPluginErrorOffline(t *testing.T) {
+	// With PULUMI_PROVIDER_INFO_DIR set, the npm registry is not queried for an install hint.
+	t.Setenv(ProviderInfoDirEnvVar, t.TempDir())
+	assert.EqualError(t, getMissingPluginError("google"), "could not find plugin gcp for provider google")
+}
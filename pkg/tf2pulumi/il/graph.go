@@ -471,7 +471,10 @@ func newBuilder(opts *BuildOptions) *builder {
 		allowMissingProviders, allowMissingVariables = opts.AllowMissingProviders, opts.AllowMissingVariables
 	}
 
-	providerInfo := PluginProviderInfoSource
+	providerInfo := NewProviderInfoSourceFromEnv()
+	if providerInfo == nil {
+		providerInfo = PluginProviderInfoSource
+	}
 	if opts != nil && opts.ProviderInfoSource != nil {
 		providerInfo = opts.ProviderInfoSource
 	}
@@ -1011,7 +1014,7 @@ func (b *builder) buildNodes(conf *config.Config) error {
 // BuildOptions defines the set of optional parameters to `BuildGraph`.
 type BuildOptions struct {
 	// ProviderInfoSource allows the caller to override the default source for provider schema information, which
-	// relies on resource provider plugins.
+	// relies on resource provider plugins unless PULUMI_PROVIDER_INFO_DIR is set.
 	ProviderInfoSource ProviderInfoSource
 	// AllowMissingProviders allows binding to succeed even if schema information is not available for a provider.
 	AllowMissingProviders bool
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"os/exec"
 	"sync"
 
@@ -96,14 +97,21 @@ func NewMultiProviderInfoSource(sources ...ProviderInfoSource) ProviderInfoSourc
 func (s multiProviderInfoSource) GetProviderInfo(
 	registryName, namespace, name, version string) (*tfbridge.ProviderInfo, error) {
 
+	usesPlugins := false
 	for _, s := range s {
 		if s != nil {
 			if info, err := s.GetProviderInfo(registryName, namespace, name, version); err == nil && info != nil {
 				return info, nil
 			}
+			usesPlugins = usesPlugins || s == PluginProviderInfoSource
 		}
 	}
 
+	// Only suggest installing a plugin if plugins were consulted. This also avoids querying the npm registry when
+	// provider info is read from files, which may be done offline.
+	if !usesPlugins {
+		return nil, fmt.Errorf("could not find provider info for provider %s", name)
+	}
 	return nil, getMissingPluginError(name)
 }
 
@@ -172,16 +180,23 @@ func (pluginProviderInfoSource) GetProviderInfo(
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not decode schema information for provider %s", tfProviderName)
 	}
+	if err = info.CheckFormatVersion(); err != nil {
+		return nil, err
+	}
 
 	return info.Unmarshal(), nil
 }
 
 // getMissingPluginError returns an error that informs the user that a plugin for a Terraform provider cannot be found,
-// and how to go about acquiring it if it is hosted on Pulumi.com.
+// and how to go about acquiring it if it is hosted on Pulumi.com. The npm registry is not queried for the latest
+// version of the plugin if PULUMI_PROVIDER_INFO_DIR is set, as provider info is then expected to be available offline.
 func getMissingPluginError(providerName string) error {
 	pluginName := GetPulumiProviderName(providerName)
 
 	message := fmt.Sprintf("could not find plugin %s for provider %s", pluginName, providerName)
+	if os.Getenv(ProviderInfoDirEnvVar) != "" {
+		return errors.New(message)
+	}
 	latest := getLatestPluginVersion(pluginName)
 	if latest != "" {
 		message += fmt.Sprintf("; try running 'pulumi plugin install resource %s %s'", pluginName, latest)
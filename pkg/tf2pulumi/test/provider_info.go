@@ -62,6 +62,9 @@ func (s *ProviderInfoSource) GetProviderInfo(
 	if err = json.NewDecoder(f).Decode(&m); err != nil {
 		return nil, err
 	}
+	if err = m.CheckFormatVersion(); err != nil {
+		return nil, err
+	}
 
 	info := m.Unmarshal()
 	s.entries[name] = info
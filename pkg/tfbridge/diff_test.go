@@ -10,6 +10,7 @@ import (
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 	"github.com/stretchr/testify/assert"
 
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	shimv1 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v1"
 	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
 )
@@ -70,36 +71,37 @@ func TestCustomizeDiff(t *testing.T) {
 				return err
 			},
 		}
-		provider := shimv2.NewProvider(&v2Schema.Provider{
+		tfProvider := &v2Schema.Provider{
 			ResourcesMap: map[string]*v2Schema.Resource{
 				"resource": customDiffRes,
 			},
-		})
-
-		// Convert the inputs and state to TF config and resource attributes.
-		r := Resource{
-			TF:     shimv2.NewResource(customDiffRes),
-			Schema: &ResourceInfo{Fields: info},
-		}
-		tfState, err := MakeTerraformState(r, "id", stateMap)
-		assert.NoError(t, err)
-
-		config, _, err := MakeTerraformConfig(&Provider{tf: provider}, inputsMap, sch, info)
-		assert.NoError(t, err)
-
-		tfDiff, err := provider.Diff("resource", tfState, config)
-		assert.NoError(t, err)
-
-		// ProcessIgnoreChanges
-		doIgnoreChanges(sch, info, stateMap, inputsMap, ignores, tfDiff)
-
-		// Convert the diff to a detailed diff and check the result.
-		diff := makeDetailedDiff(sch, info, stateMap, inputsMap, tfDiff)
-		expectedDiff := map[string]*pulumirpc.PropertyDiff{}
-		for k, v := range expected {
-			expectedDiff[k] = &pulumirpc.PropertyDiff{Kind: v}
 		}
-		assert.Equal(t, expectedDiff, diff)
+		forEachV2Mode(t, tfProvider, func(t *testing.T, provider shim.Provider, _ bool) {
+			// Convert the inputs and state to TF config and resource attributes.
+			r := Resource{
+				TF:     provider.ResourcesMap().Get("resource"),
+				Schema: &ResourceInfo{Fields: info},
+			}
+			tfState, err := MakeTerraformState(r, "id", stateMap)
+			assert.NoError(t, err)
+
+			config, _, err := MakeTerraformConfig(&Provider{tf: provider}, inputsMap, sch, info)
+			assert.NoError(t, err)
+
+			tfDiff, err := provider.Diff("resource", tfState, config)
+			assert.NoError(t, err)
+
+			// ProcessIgnoreChanges
+			doIgnoreChanges(sch, info, stateMap, inputsMap, ignores, tfDiff)
+
+			// Convert the diff to a detailed diff and check the result.
+			diff := makeDetailedDiff(sch, info, stateMap, inputsMap, tfDiff)
+			expectedDiff := map[string]*pulumirpc.PropertyDiff{}
+			for k, v := range expected {
+				expectedDiff[k] = &pulumirpc.PropertyDiff{Kind: v}
+			}
+			assert.Equal(t, expectedDiff, diff)
+		})
 	})
 
 	t.Run("NoCustomDiffCausesNoDiff", func(t *testing.T) {
@@ -110,36 +112,37 @@ func TestCustomizeDiff(t *testing.T) {
 		noCustomDiffRes := &v2Schema.Resource{
 			Schema: tfs,
 		}
-		provider := shimv2.NewProvider(&v2Schema.Provider{
+		tfProvider := &v2Schema.Provider{
 			ResourcesMap: map[string]*v2Schema.Resource{
 				"resource": noCustomDiffRes,
 			},
-		})
-
-		// Convert the inputs and state to TF config and resource attributes.
-		r := Resource{
-			TF:     shimv2.NewResource(noCustomDiffRes),
-			Schema: &ResourceInfo{Fields: info},
-		}
-		tfState, err := MakeTerraformState(r, "id", stateMap)
-		assert.NoError(t, err)
-
-		config, _, err := MakeTerraformConfig(&Provider{tf: provider}, inputsMap, sch, info)
-		assert.NoError(t, err)
-
-		tfDiff, err := provider.Diff("resource", tfState, config)
-		assert.NoError(t, err)
-
-		// ProcessIgnoreChanges
-		doIgnoreChanges(sch, info, stateMap, inputsMap, ignores, tfDiff)
-
-		// Convert the diff to a detailed diff and check the result.
-		diff := makeDetailedDiff(sch, info, stateMap, inputsMap, tfDiff)
-		expectedDiff := map[string]*pulumirpc.PropertyDiff{}
-		for k, v := range expected {
-			expectedDiff[k] = &pulumirpc.PropertyDiff{Kind: v}
 		}
-		assert.Equal(t, expectedDiff, diff)
+		forEachV2Mode(t, tfProvider, func(t *testing.T, provider shim.Provider, _ bool) {
+			// Convert the inputs and state to TF config and resource attributes.
+			r := Resource{
+				TF:     provider.ResourcesMap().Get("resource"),
+				Schema: &ResourceInfo{Fields: info},
+			}
+			tfState, err := MakeTerraformState(r, "id", stateMap)
+			assert.NoError(t, err)
+
+			config, _, err := MakeTerraformConfig(&Provider{tf: provider}, inputsMap, sch, info)
+			assert.NoError(t, err)
+
+			tfDiff, err := provider.Diff("resource", tfState, config)
+			assert.NoError(t, err)
+
+			// ProcessIgnoreChanges
+			doIgnoreChanges(sch, info, stateMap, inputsMap, ignores, tfDiff)
+
+			// Convert the diff to a detailed diff and check the result.
+			diff := makeDetailedDiff(sch, info, stateMap, inputsMap, tfDiff)
+			expectedDiff := map[string]*pulumirpc.PropertyDiff{}
+			for k, v := range expected {
+				expectedDiff[k] = &pulumirpc.PropertyDiff{Kind: v}
+			}
+			assert.Equal(t, expectedDiff, diff)
+		})
 	})
 }
 
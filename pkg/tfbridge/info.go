@@ -16,6 +16,7 @@ package tfbridge
 
 import (
 	"fmt"
+	"sort"
 	"unicode"
 
 	"github.com/blang/semver"
@@ -86,6 +87,17 @@ type ProviderInfo struct {
 	TFProviderModuleVersion  string             // the Go module version of the provider. Default is unversioned e.g. v1
 
 	PreConfigureCallback PreConfigureCallback // a provider-specific callback to invoke prior to TF Configure
+	ProviderMetaCallback ProviderMetaCallback // a provider-specific callback that builds the TF provider_meta
+
+	// UseGRPCServer, if true, drives the TF provider through its own gRPC provider server, which is served in-process,
+	// rather than through the SDK's Go APIs. This matches how Terraform drives the provider. It is only supported by
+	// providers that implement shim.ProviderWithGRPCServer, such as SDKv2 providers created by the sdk-v2/inprocess
+	// package.
+	UseGRPCServer bool
+
+	// DocsParser, if set, overrides the parser used by tfgen for the provider's upstream markdown docs. This is useful
+	// for providers whose docs do not follow the TF registry layout.
+	DocsParser DocsParser
 }
 
 // TFProviderLicense is a way to be able to pass a license type for the upstream Terraform provider.
@@ -257,6 +269,11 @@ type SchemaInfo struct {
 
 	// whether or not to treat this property as secret
 	Secret *bool
+
+	// whether or not this property is write-only: its value is sent to the provider when the resource is created or
+	// updated, but the resource's inputs and outputs store only a hash of it, so that changes to it can be detected.
+	// The value is held in memory between Check and Create or Update.
+	WriteOnly bool
 }
 
 // ConfigInfo represents a synthetic configuration variable that is Pulumi-only, and not passed to Terraform.
@@ -360,12 +377,63 @@ type JavaInfo struct {
 	BasePackage string // the Base package for the Java SDK
 }
 
+// DocsParser selects the parser used by tfgen for a provider's upstream markdown docs. The set of parsers is closed:
+// a DocsParser is either a TFRegistryDocsParser or a TFPluginDocsParser.
+type DocsParser interface {
+	// DocsParserName returns a human-readable name for the parser.
+	DocsParserName() string
+
+	isDocsParser()
+}
+
+// TFRegistryDocsParser parses docs that follow the TF registry conventions: an "Argument Reference" section whose
+// nested blocks are introduced by lines such as "The `foo` block supports:", an "Attributes Reference" section and
+// an "Import" section. Providers that use different headings for these sections may list them here; the standard
+// headings are always recognized.
+type TFRegistryDocsParser struct {
+	ArgumentHeadings  []string // additional H2 headings that introduce the argument reference.
+	AttributeHeadings []string // additional H2 headings that introduce the attributes reference.
+	ImportHeadings    []string // additional H2 headings that introduce the import section.
+}
+
+// DocsParserName implements DocsParser.
+func (TFRegistryDocsParser) DocsParserName() string {
+	return "tfregistry"
+}
+
+func (TFRegistryDocsParser) isDocsParser() {}
+
+// TFPluginDocsParser parses docs generated by tfplugindocs, which describe the arguments and attributes of a resource
+// in a "Schema" section with "Required", "Optional" and "Read-Only" subsections followed by a "Nested Schema for"
+// subsection for each nested block. Required and optional properties are recorded as arguments and read-only
+// properties as attributes.
+type TFPluginDocsParser struct{}
+
+// DocsParserName implements DocsParser.
+func (TFPluginDocsParser) DocsParserName() string {
+	return "tfplugindocs"
+}
+
+func (TFPluginDocsParser) isDocsParser() {}
+
 // PreConfigureCallback is a function to invoke prior to calling the TF provider Configure
 type PreConfigureCallback func(vars resource.PropertyMap, config shim.ResourceConfig) error
 
+// ProviderMetaCallback is a function that builds the value of the TF provider's provider_meta block. It is passed the
+// provider's config and the URN of the resource being operated on, which names the resource's stack and project. The
+// URN is empty when a data source is read. The value is passed to the TF provider when it plans, applies or reads a
+// resource or reads a data source, if the provider supports provider_meta.
+type ProviderMetaCallback func(vars resource.PropertyMap, urn resource.URN) (map[string]interface{}, error)
+
 // The types below are marshallable versions of the schema descriptions associated with a provider. These are used when
-// marshalling a provider info as JSON; Note that these types only represent a subset of the informatino associated
-// with a ProviderInfo; thus, a ProviderInfo cannot be round-tripped through JSON.
+// marshalling a provider info as JSON. Everything in a ProviderInfo that is plain data is preserved; functions and
+// interface values (e.g. default funcs, state funcs, transforms and callbacks) cannot be serialized. Their presence is
+// recorded so that the unmarshaled values have placeholders in their place, and their paths are listed in
+// MarshallableProviderInfo.Unserializable.
+
+// MarshallableProviderInfoVersion is the version of the marshalled provider info format written by this version of the
+// bridge. Provider info written before the format was versioned has version 0.
+const MarshallableProviderInfoVersion = 1
 
 // MarshallableSchema is the JSON-marshallable form of a Terraform schema.
 type MarshallableSchema struct {
@@ -378,6 +446,13 @@ type MarshallableSchema struct {
 	MaxItems           int               `json:"maxItems,omitempty"`
 	MinItems           int               `json:"minItems,omitempty"`
 	DeprecationMessage string            `json:"deprecated,omitempty"`
+	Description        string            `json:"description,omitempty"`
+	Sensitive          bool              `json:"sensitive,omitempty"`
+	ConflictsWith      []string          `json:"conflictsWith,omitempty"`
+	Removed            string            `json:"removed,omitempty"`
+	Default            interface{}       `json:"default,omitempty"`
+	DefaultFunc        bool              `json:"defaultFunc,omitempty"`
+	StateFunc          bool              `json:"stateFunc,omitempty"`
 }
 
 // MarshalSchema converts a Terraform schema into a MarshallableSchema.
@@ -392,24 +467,53 @@ func MarshalSchema(s shim.Schema) *MarshallableSchema {
 		MaxItems:           s.MaxItems(),
 		MinItems:           s.MinItems(),
 		DeprecationMessage: s.Deprecated(),
+		Description:        s.Description(),
+		Sensitive:          s.Sensitive(),
+		ConflictsWith:      s.ConflictsWith(),
+		Removed:            s.Removed(),
+		Default:            s.Default(),
+		DefaultFunc:        s.DefaultFunc() != nil,
+		StateFunc:          s.StateFunc() != nil,
 	}
 }
 
-// Unmarshal creates a mostly-initialized Terraform schema from the given MarshallableSchema.
+// Unmarshal creates a mostly-initialized Terraform schema from the given MarshallableSchema. Default funcs are replaced
+// with funcs that return an error; state funcs are dropped.
 func (m *MarshallableSchema) Unmarshal() shim.Schema {
+	var defaultFunc shim.SchemaDefaultFunc
+	if m.DefaultFunc {
+		defaultFunc = func() (interface{}, error) {
+			return nil, fmt.Errorf("default funcs cannot be run on unmarshaled schemas")
+		}
+	}
+
 	return (&schema.Schema{
-		Type:       m.Type,
-		Optional:   m.Optional,
-		Required:   m.Required,
-		Computed:   m.Computed,
-		ForceNew:   m.ForceNew,
-		Elem:       m.Elem.Unmarshal(),
-		MaxItems:   m.MaxItems,
-		MinItems:   m.MinItems,
-		Deprecated: m.DeprecationMessage,
+		Type:          m.Type,
+		Optional:      m.Optional,
+		Required:      m.Required,
+		Computed:      m.Computed,
+		ForceNew:      m.ForceNew,
+		Elem:          m.Elem.Unmarshal(),
+		MaxItems:      m.MaxItems,
+		MinItems:      m.MinItems,
+		Deprecated:    m.DeprecationMessage,
+		Description:   m.Description,
+		Sensitive:     m.Sensitive,
+		ConflictsWith: m.ConflictsWith,
+		Removed:       m.Removed,
+		Default:       unmarshalSchemaDefault(m.Type, m.Default),
+		DefaultFunc:   defaultFunc,
 	}).Shim()
 }
 
+// unmarshalSchemaDefault restores the Go type of a schema's default value. JSON decodes all numbers as float64.
+func unmarshalSchemaDefault(typ shim.ValueType, v interface{}) interface{} {
+	if f, ok := v.(float64); ok && typ == shim.TypeInt {
+		return int(f)
+	}
+	return v
+}
+
 // MarshallableResource is the JSON-marshallable form of a Terraform resource schema.
 type MarshallableResource map[string]*MarshallableSchema
 
@@ -527,18 +631,30 @@ func (m *MarshallableProvider) Unmarshal() shim.Provider {
 
 // MarshallableSchemaInfo is the JSON-marshallable form of a Pulumi SchemaInfo value.
 type MarshallableSchemaInfo struct {
-	Name        string                             `json:"name,omitempty"`
-	CSharpName  string                             `json:"csharpName,omitempty"`
-	Type        tokens.Type                        `json:"typeomitempty"`
-	AltTypes    []tokens.Type                      `json:"altTypes,omitempty"`
-	Elem        *MarshallableSchemaInfo            `json:"element,omitempty"`
-	Fields      map[string]*MarshallableSchemaInfo `json:"fields,omitempty"`
-	Asset       *AssetTranslation                  `json:"asset,omitempty"`
-	Default     *MarshallableDefaultInfo           `json:"default,omitempty"`
-	MaxItemsOne *bool                              `json:"maxItemsOne,omitempty"`
-	Deprecated  string                             `json:"deprecated,omitempty"`
-	ForceNew    *bool                              `json:"forceNew,omitempty"`
-	Secret      *bool                              `json:"secret,omitempty"`
+	Name                     string                             `json:"name,omitempty"`
+	CSharpName               string                             `json:"csharpName,omitempty"`
+	Type                     tokens.Type                        `json:"type,omitempty"`
+	AltTypes                 []tokens.Type                      `json:"altTypes,omitempty"`
+	NestedType               tokens.Type                        `json:"nestedType,omitempty"`
+	Transform                bool                               `json:"transform,omitempty"`
+	Elem                     *MarshallableSchemaInfo            `json:"element,omitempty"`
+	Fields                   map[string]*MarshallableSchemaInfo `json:"fields,omitempty"`
+	Asset                    *AssetTranslation                  `json:"asset,omitempty"`
+	Default                  *MarshallableDefaultInfo           `json:"default,omitempty"`
+	Stable                   *bool                              `json:"stable,omitempty"`
+	MaxItemsOne              *bool                              `json:"maxItemsOne,omitempty"`
+	SuppressEmptyMapElements *bool                              `json:"suppressEmptyMapElements,omitempty"`
+	MarkAsComputedOnly       *bool                              `json:"markAsComputedOnly,omitempty"`
+	MarkAsOptional           *bool                              `json:"markAsOptional,omitempty"`
+	Deprecated               string                             `json:"deprecated,omitempty"`
+	ForceNew                 *bool                              `json:"forceNew,omitempty"`
+	Removed                  bool                               `json:"removed,omitempty"`
+	Omit                     bool                               `json:"omit,omitempty"`
+	Secret                   *bool                              `json:"secret,omitempty"`
+	WriteOnly                bool                               `json:"writeOnly,omitempty"`
+
+	// LegacyType holds the type written by versions of the bridge that misspelled the type's JSON key.
+	LegacyType tokens.Type `json:"typeomitempty,omitempty"`
 }
 
 // MarshalSchemaInfo converts a Pulumi SchemaInfo value into a MarshallableSchemaInfo value.
@@ -552,50 +668,82 @@ func MarshalSchemaInfo(s *SchemaInfo) *MarshallableSchemaInfo {
 		fields[k] = MarshalSchemaInfo(v)
 	}
 	return &MarshallableSchemaInfo{
-		Name:        s.Name,
-		CSharpName:  s.CSharpName,
-		Type:        s.Type,
-		AltTypes:    s.AltTypes,
-		Elem:        MarshalSchemaInfo(s.Elem),
-		Fields:      fields,
-		Asset:       s.Asset,
-		Default:     MarshalDefaultInfo(s.Default),
-		MaxItemsOne: s.MaxItemsOne,
-		Deprecated:  s.DeprecationMessage,
-		ForceNew:    s.ForceNew,
-		Secret:      s.Secret,
+		Name:                     s.Name,
+		CSharpName:               s.CSharpName,
+		Type:                     s.Type,
+		AltTypes:                 s.AltTypes,
+		NestedType:               s.NestedType,
+		Transform:                s.Transform != nil,
+		Elem:                     MarshalSchemaInfo(s.Elem),
+		Fields:                   fields,
+		Asset:                    s.Asset,
+		Default:                  MarshalDefaultInfo(s.Default),
+		Stable:                   s.Stable,
+		MaxItemsOne:              s.MaxItemsOne,
+		SuppressEmptyMapElements: s.SuppressEmptyMapElements,
+		MarkAsComputedOnly:       s.MarkAsComputedOnly,
+		MarkAsOptional:           s.MarkAsOptional,
+		Deprecated:               s.DeprecationMessage,
+		ForceNew:                 s.ForceNew,
+		Removed:                  s.Removed,
+		Omit:                     s.Omit,
+		Secret:                   s.Secret,
+		WriteOnly:                s.WriteOnly,
 	}
 }
 
 // Unmarshal creates a mostly-=initialized Pulumi SchemaInfo value from the given MarshallableSchemaInfo.
+// Transforms are replaced with transforms that return an error.
 func (m *MarshallableSchemaInfo) Unmarshal() *SchemaInfo {
 	if m == nil {
 		return nil
 	}
 
+	typ := m.Type
+	if typ == "" {
+		typ = m.LegacyType
+	}
+
+	var transform Transformer
+	if m.Transform {
+		transform = func(resource.PropertyValue) (resource.PropertyValue, error) {
+			return resource.PropertyValue{}, fmt.Errorf("transforms cannot be run on unmarshaled SchemaInfo values")
+		}
+	}
+
 	fields := make(map[string]*SchemaInfo)
 	for k, v := range m.Fields {
 		fields[k] = v.Unmarshal()
 	}
 	return &SchemaInfo{
-		Name:               m.Name,
-		CSharpName:         m.CSharpName,
-		Type:               m.Type,
-		AltTypes:           m.AltTypes,
-		Elem:               m.Elem.Unmarshal(),
-		Fields:             fields,
-		Asset:              m.Asset,
-		Default:            m.Default.Unmarshal(),
-		MaxItemsOne:        m.MaxItemsOne,
-		DeprecationMessage: m.Deprecated,
-		ForceNew:           m.ForceNew,
-		Secret:             m.Secret,
+		Name:                     m.Name,
+		CSharpName:               m.CSharpName,
+		Type:                     typ,
+		AltTypes:                 m.AltTypes,
+		NestedType:               m.NestedType,
+		Transform:                transform,
+		Elem:                     m.Elem.Unmarshal(),
+		Fields:                   fields,
+		Asset:                    m.Asset,
+		Default:                  m.Default.Unmarshal(),
+		Stable:                   m.Stable,
+		MaxItemsOne:              m.MaxItemsOne,
+		SuppressEmptyMapElements: m.SuppressEmptyMapElements,
+		MarkAsComputedOnly:       m.MarkAsComputedOnly,
+		MarkAsOptional:           m.MarkAsOptional,
+		DeprecationMessage:       m.Deprecated,
+		ForceNew:                 m.ForceNew,
+		Removed:                  m.Removed,
+		Omit:                     m.Omit,
+		Secret:                   m.Secret,
+		WriteOnly:                m.WriteOnly,
 	}
 }
 
 // MarshallableDefaultInfo is the JSON-marshallable form of a Pulumi DefaultInfo value.
 type MarshallableDefaultInfo struct {
 	AutoNamed bool        `json:"autonamed,omitempty"`
+	Config    string      `json:"config,omitempty"`
 	IsFunc    bool        `json:"isFunc,omitempty"`
 	Value     interface{} `json:"value,omitempty"`
 	EnvVars   []string    `json:"envvars,omitempty"`
@@ -609,6 +757,7 @@ func MarshalDefaultInfo(d *DefaultInfo) *MarshallableDefaultInfo {
 
 	return &MarshallableDefaultInfo{
 		AutoNamed: d.AutoNamed,
+		Config:    d.Config,
 		IsFunc:    d.From != nil,
 		Value:     d.Value,
 		EnvVars:   d.EnvVars,
@@ -630,6 +779,7 @@ func (m *MarshallableDefaultInfo) Unmarshal() *DefaultInfo {
 
 	return &DefaultInfo{
 		AutoNamed: m.AutoNamed,
+		Config:    m.Config,
 		From:      f,
 		Value:     m.Value,
 		EnvVars:   m.EnvVars,
@@ -638,9 +788,15 @@ func (m *MarshallableDefaultInfo) Unmarshal() *DefaultInfo {
 
 // MarshallableResourceInfo is the JSON-marshallable form of a Pulumi ResourceInfo value.
 type MarshallableResourceInfo struct {
-	Tok      tokens.Type                        `json:"tok"`
-	Fields   map[string]*MarshallableSchemaInfo `json:"fields"`
-	IDFields []string                           `json:"idFields"`
+	Tok                 tokens.Type                        `json:"tok"`
+	Fields              map[string]*MarshallableSchemaInfo `json:"fields"`
+	IDFields            []string                           `json:"idFields"`
+	UniqueNameFields    []string                           `json:"uniqueNameFields,omitempty"`
+	Docs                *DocInfo                           `json:"docs,omitempty"`
+	DeleteBeforeReplace bool                               `json:"deleteBeforeReplace,omitempty"`
+	Aliases             []AliasInfo                        `json:"aliases,omitempty"`
+	DeprecationMessage  string                             `json:"deprecated,omitempty"`
+	CSharpName          string                             `json:"csharpName,omitempty"`
 }
 
 // MarshalResourceInfo converts a Pulumi ResourceInfo value into a MarshallableResourceInfo value.
@@ -650,9 +806,15 @@ func MarshalResourceInfo(r *ResourceInfo) *MarshallableResourceInfo {
 		fields[k] = MarshalSchemaInfo(v)
 	}
 	return &MarshallableResourceInfo{
-		Tok:      r.Tok,
-		Fields:   fields,
-		IDFields: r.IDFields,
+		Tok:                 r.Tok,
+		Fields:              fields,
+		IDFields:            r.IDFields,
+		UniqueNameFields:    r.UniqueNameFields,
+		Docs:                r.Docs,
+		DeleteBeforeReplace: r.DeleteBeforeReplace,
+		Aliases:             r.Aliases,
+		DeprecationMessage:  r.DeprecationMessage,
+		CSharpName:          r.CSharpName,
 	}
 }
 
@@ -663,16 +825,24 @@ func (m *MarshallableResourceInfo) Unmarshal() *ResourceInfo {
 		fields[k] = v.Unmarshal()
 	}
 	return &ResourceInfo{
-		Tok:      m.Tok,
-		Fields:   fields,
-		IDFields: m.IDFields,
+		Tok:                 m.Tok,
+		Fields:              fields,
+		IDFields:            m.IDFields,
+		UniqueNameFields:    m.UniqueNameFields,
+		Docs:                m.Docs,
+		DeleteBeforeReplace: m.DeleteBeforeReplace,
+		Aliases:             m.Aliases,
+		DeprecationMessage:  m.DeprecationMessage,
+		CSharpName:          m.CSharpName,
 	}
 }
 
 // MarshallableDataSourceInfo is the JSON-marshallable form of a Pulumi DataSourceInfo value.
 type MarshallableDataSourceInfo struct {
-	Tok    tokens.ModuleMember                `json:"tok"`
-	Fields map[string]*MarshallableSchemaInfo `json:"fields"`
+	Tok                tokens.ModuleMember                `json:"tok"`
+	Fields             map[string]*MarshallableSchemaInfo `json:"fields"`
+	Docs               *DocInfo                           `json:"docs,omitempty"`
+	DeprecationMessage string                             `json:"deprecated,omitempty"`
 }
 
 // MarshalDataSourceInfo converts a Pulumi DataSourceInfo value into a MarshallableDataSourceInfo value.
@@ -682,8 +852,10 @@ func MarshalDataSourceInfo(d *DataSourceInfo) *MarshallableDataSourceInfo {
 		fields[k] = MarshalSchemaInfo(v)
 	}
 	return &MarshallableDataSourceInfo{
-		Tok:    d.Tok,
-		Fields: fields,
+		Tok:                d.Tok,
+		Fields:             fields,
+		Docs:               d.Docs,
+		DeprecationMessage: d.DeprecationMessage,
 	}
 }
 
@@ -694,20 +866,83 @@ func (m *MarshallableDataSourceInfo) Unmarshal() *DataSourceInfo {
 		fields[k] = v.Unmarshal()
 	}
 	return &DataSourceInfo{
-		Tok:    m.Tok,
-		Fields: fields,
+		Tok:                m.Tok,
+		Fields:             fields,
+		Docs:               m.Docs,
+		DeprecationMessage: m.DeprecationMessage,
+	}
+}
+
+// MarshallableConfigInfo is the JSON-marshallable form of a Pulumi ConfigInfo value.
+type MarshallableConfigInfo struct {
+	Info   *MarshallableSchemaInfo `json:"info,omitempty"`
+	Schema *MarshallableSchema     `json:"schema,omitempty"`
+}
+
+// MarshalConfigInfo converts a Pulumi ConfigInfo value into a MarshallableConfigInfo value.
+func MarshalConfigInfo(c *ConfigInfo) *MarshallableConfigInfo {
+	var s *MarshallableSchema
+	if c.Schema != nil {
+		s = MarshalSchema(c.Schema)
+	}
+	return &MarshallableConfigInfo{
+		Info:   MarshalSchemaInfo(c.Info),
+		Schema: s,
+	}
+}
+
+// Unmarshal creates a mostly-initialized Pulumi ConfigInfo value from the given MarshallableConfigInfo.
+func (m *MarshallableConfigInfo) Unmarshal() *ConfigInfo {
+	var s shim.Schema
+	if m.Schema != nil {
+		s = m.Schema.Unmarshal()
+	}
+	return &ConfigInfo{
+		Info:   m.Info.Unmarshal(),
+		Schema: s,
 	}
 }
 
 // MarshallableProviderInfo is the JSON-marshallable form of a Pulumi ProviderInfo value.
 type MarshallableProviderInfo struct {
-	Provider          *MarshallableProvider                  `json:"provider"`
-	Name              string                                 `json:"name"`
-	Version           string                                 `json:"version"`
-	Config            map[string]*MarshallableSchemaInfo     `json:"config,omitempty"`
-	Resources         map[string]*MarshallableResourceInfo   `json:"resources,omitempty"`
-	DataSources       map[string]*MarshallableDataSourceInfo `json:"dataSources,omitempty"`
-	TFProviderVersion string                                 `json:"tfProviderVersion,omitempty"`
+	// FormatVersion is the version of the format in which the provider info was written. See
+	// MarshallableProviderInfoVersion.
+	FormatVersion int `json:"formatVersion,omitempty"`
+
+	Provider                *MarshallableProvider                  `json:"provider"`
+	Name                    string                                 `json:"name"`
+	Version                 string                                 `json:"version"`
+	ResourcePrefix          string                                 `json:"resourcePrefix,omitempty"`
+	GitHubOrg               string                                 `json:"gitHubOrg,omitempty"`
+	GitHubHost              string                                 `json:"gitHubHost,omitempty"`
+	Description             string                                 `json:"description,omitempty"`
+	Keywords                []string                               `json:"keywords,omitempty"`
+	License                 string                                 `json:"license,omitempty"`
+	LogoURL                 string                                 `json:"logoUrl,omitempty"`
+	DisplayName             string                                 `json:"displayName,omitempty"`
+	Publisher               string                                 `json:"publisher,omitempty"`
+	Homepage                string                                 `json:"homepage,omitempty"`
+	Repository              string                                 `json:"repository,omitempty"`
+	Config                  map[string]*MarshallableSchemaInfo     `json:"config,omitempty"`
+	ExtraConfig             map[string]*MarshallableConfigInfo     `json:"extraConfig,omitempty"`
+	Resources               map[string]*MarshallableResourceInfo   `json:"resources,omitempty"`
+	DataSources             map[string]*MarshallableDataSourceInfo `json:"dataSources,omitempty"`
+	ExtraTypes              map[string]pschema.ComplexTypeSpec     `json:"extraTypes,omitempty"`
+	IgnoreMappings          []string                               `json:"ignoreMappings,omitempty"`
+	PluginDownloadURL       string                                 `json:"pluginDownloadUrl,omitempty"`
+	JavaScript              *JavaScriptInfo                        `json:"javascript,omitempty"`
+	Python                  *PythonInfo                            `json:"python,omitempty"`
+	Golang                  *GolangInfo                            `json:"golang,omitempty"`
+	CSharp                  *CSharpInfo                            `json:"csharp,omitempty"`
+	Java                    *JavaInfo                              `json:"java,omitempty"`
+	TFProviderVersion       string                                 `json:"tfProviderVersion,omitempty"`
+	TFProviderLicense       *TFProviderLicense                     `json:"tfProviderLicense,omitempty"`
+	TFProviderModuleVersion string                                 `json:"tfProviderModuleVersion,omitempty"`
+	UseGRPCServer           bool                                   `json:"useGRPCServer,omitempty"`
+
+	// Unserializable lists the paths of the values in the provider info that could not be serialized, e.g.
+	// "resources.aws_s3_bucket_object.fields.source.transform".
+	Unserializable []string `json:"unserializable,omitempty"`
 }
 
 // MarshalProviderInfo converts a Pulumi ProviderInfo value into a MarshallableProviderInfo value.
@@ -716,6 +951,13 @@ func MarshalProviderInfo(p *ProviderInfo) *MarshallableProviderInfo {
 	for k, v := range p.Config {
 		config[k] = MarshalSchemaInfo(v)
 	}
+	var extraConfig map[string]*MarshallableConfigInfo
+	if len(p.ExtraConfig) != 0 {
+		extraConfig = make(map[string]*MarshallableConfigInfo)
+		for k, v := range p.ExtraConfig {
+			extraConfig[k] = MarshalConfigInfo(v)
+		}
+	}
 	resources := make(map[string]*MarshallableResourceInfo)
 	for k, v := range p.Resources {
 		resources[k] = MarshalResourceInfo(v)
@@ -726,24 +968,83 @@ func MarshalProviderInfo(p *ProviderInfo) *MarshallableProviderInfo {
 	}
 
 	info := MarshallableProviderInfo{
-		Provider:          MarshalProvider(p.P),
-		Name:              p.Name,
-		Version:           p.Version,
-		Config:            config,
-		Resources:         resources,
-		DataSources:       dataSources,
-		TFProviderVersion: p.TFProviderVersion,
+		FormatVersion:           MarshallableProviderInfoVersion,
+		Provider:                MarshalProvider(p.P),
+		Name:                    p.Name,
+		Version:                 p.Version,
+		ResourcePrefix:          p.ResourcePrefix,
+		GitHubOrg:               p.GitHubOrg,
+		GitHubHost:              p.GitHubHost,
+		Description:             p.Description,
+		Keywords:                p.Keywords,
+		License:                 p.License,
+		LogoURL:                 p.LogoURL,
+		DisplayName:             p.DisplayName,
+		Publisher:               p.Publisher,
+		Homepage:                p.Homepage,
+		Repository:              p.Repository,
+		Config:                  config,
+		ExtraConfig:             extraConfig,
+		Resources:               resources,
+		DataSources:             dataSources,
+		ExtraTypes:              p.ExtraTypes,
+		IgnoreMappings:          p.IgnoreMappings,
+		PluginDownloadURL:       p.PluginDownloadURL,
+		JavaScript:              p.JavaScript,
+		Python:                  p.Python,
+		Golang:                  p.Golang,
+		CSharp:                  p.CSharp,
+		Java:                    p.Java,
+		TFProviderVersion:       p.TFProviderVersion,
+		TFProviderLicense:       p.TFProviderLicense,
+		TFProviderModuleVersion: p.TFProviderModuleVersion,
+		UseGRPCServer:           p.UseGRPCServer,
 	}
 
+	var unserializable []string
+	if len(p.ExtraResourceHclExamples) != 0 {
+		unserializable = append(unserializable, "extraResourceHclExamples")
+	}
+	if len(p.ExtraFunctionHclExamples) != 0 {
+		unserializable = append(unserializable, "extraFunctionHclExamples")
+	}
+	if p.PreConfigureCallback != nil {
+		unserializable = append(unserializable, "preConfigureCallback")
+	}
+	if p.ProviderMetaCallback != nil {
+		unserializable = append(unserializable, "providerMetaCallback")
+	}
+	if p.DocsParser != nil {
+		unserializable = append(unserializable, "docsParser")
+	}
+	info.Unserializable = append(unserializable, info.unserializable()...)
+
 	return &info
 }
 
+// CheckFormatVersion returns an error if the provider info was written in a newer format than this version of the
+// bridge is able to read.
+func (m *MarshallableProviderInfo) CheckFormatVersion() error {
+	if m.FormatVersion > MarshallableProviderInfoVersion {
+		return fmt.Errorf("provider info for %v has format version %v; this version of the bridge supports format "+
+			"versions up to %v", m.Name, m.FormatVersion, MarshallableProviderInfoVersion)
+	}
+	return nil
+}
+
 // Unmarshal creates a mostly-=initialized Pulumi ProviderInfo value from the given MarshallableProviderInfo.
 func (m *MarshallableProviderInfo) Unmarshal() *ProviderInfo {
 	config := make(map[string]*SchemaInfo)
 	for k, v := range m.Config {
 		config[k] = v.Unmarshal()
 	}
+	var extraConfig map[string]*ConfigInfo
+	if len(m.ExtraConfig) != 0 {
+		extraConfig = make(map[string]*ConfigInfo)
+		for k, v := range m.ExtraConfig {
+			extraConfig[k] = v.Unmarshal()
+		}
+	}
 	resources := make(map[string]*ResourceInfo)
 	for k, v := range m.Resources {
 		resources[k] = v.Unmarshal()
@@ -754,18 +1055,114 @@ func (m *MarshallableProviderInfo) Unmarshal() *ProviderInfo {
 	}
 
 	info := ProviderInfo{
-		P:                 m.Provider.Unmarshal(),
-		Name:              m.Name,
-		Version:           m.Version,
-		Config:            config,
-		Resources:         resources,
-		DataSources:       dataSources,
-		TFProviderVersion: m.TFProviderVersion,
+		P:                       m.Provider.Unmarshal(),
+		Name:                    m.Name,
+		Version:                 m.Version,
+		ResourcePrefix:          m.ResourcePrefix,
+		GitHubOrg:               m.GitHubOrg,
+		GitHubHost:              m.GitHubHost,
+		Description:             m.Description,
+		Keywords:                m.Keywords,
+		License:                 m.License,
+		LogoURL:                 m.LogoURL,
+		DisplayName:             m.DisplayName,
+		Publisher:               m.Publisher,
+		Homepage:                m.Homepage,
+		Repository:              m.Repository,
+		Config:                  config,
+		ExtraConfig:             extraConfig,
+		Resources:               resources,
+		DataSources:             dataSources,
+		ExtraTypes:              m.ExtraTypes,
+		IgnoreMappings:          m.IgnoreMappings,
+		PluginDownloadURL:       m.PluginDownloadURL,
+		JavaScript:              m.JavaScript,
+		Python:                  m.Python,
+		Golang:                  m.Golang,
+		CSharp:                  m.CSharp,
+		Java:                    m.Java,
+		TFProviderVersion:       m.TFProviderVersion,
+		TFProviderLicense:       m.TFProviderLicense,
+		TFProviderModuleVersion: m.TFProviderModuleVersion,
+		UseGRPCServer:           m.UseGRPCServer,
 	}
 
 	return &info
 }
 
+// unserializable returns the sorted paths of the funcs that were dropped when the provider info was marshaled.
+func (m *MarshallableProviderInfo) unserializable() []string {
+	var paths []string
+
+	var visitSchema func(path string, s *MarshallableSchema)
+	visitResource := func(path string, r MarshallableResource) {
+		for k, v := range r {
+			visitSchema(path+"."+k, v)
+		}
+	}
+	visitSchema = func(path string, s *MarshallableSchema) {
+		if s == nil {
+			return
+		}
+		if s.DefaultFunc {
+			paths = append(paths, path+".defaultFunc")
+		}
+		if s.StateFunc {
+			paths = append(paths, path+".stateFunc")
+		}
+		if s.Elem != nil {
+			visitSchema(path+".element", s.Elem.Schema)
+			visitResource(path+".element", s.Elem.Resource)
+		}
+	}
+
+	var visitInfo func(path string, s *MarshallableSchemaInfo)
+	visitFields := func(path string, fields map[string]*MarshallableSchemaInfo) {
+		for k, v := range fields {
+			visitInfo(path+"."+k, v)
+		}
+	}
+	visitInfo = func(path string, s *MarshallableSchemaInfo) {
+		if s == nil {
+			return
+		}
+		if s.Transform {
+			paths = append(paths, path+".transform")
+		}
+		if s.Default != nil && s.Default.IsFunc {
+			paths = append(paths, path+".default.from")
+		}
+		visitInfo(path+".element", s.Elem)
+		visitFields(path+".fields", s.Fields)
+	}
+
+	if m.Provider != nil {
+		for k, v := range m.Provider.Schema {
+			visitSchema("provider.schema."+k, v)
+		}
+		for k, v := range m.Provider.Resources {
+			visitResource("provider.resources."+k, v)
+		}
+		for k, v := range m.Provider.DataSources {
+			visitResource("provider.dataSources."+k, v)
+		}
+	}
+	visitFields("config", m.Config)
+	for k, v := range m.ExtraConfig {
+		visitInfo("extraConfig."+k+".info", v.Info)
+		visitSchema("extraConfig."+k+".schema", v.Schema)
+	}
+	for k, v := range m.Resources {
+		visitFields("resources."+k+".fields", v.Fields)
+	}
+	for k, v := range m.DataSources {
+		visitFields("dataSources."+k+".fields", v.Fields)
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
 // Calculates the major version of a go sdk
 // go module paths only care about appending a version when the version is
 // 2 or greater. github.com/org/my-repo/sdk/v1/go is not a valid
@@ -1,10 +1,16 @@
 package tfbridge
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 )
 
 func TestGetModuleMajorVersion(t *testing.T) {
@@ -64,3 +70,103 @@ func TestStringValue(t *testing.T) {
 	assert.Equal(t, "value1", StringValue(myMap, "key1"))
 	assert.Equal(t, "", StringValue(myMap, "keyThatDoesNotExist"))
 }
+
+func TestMarshalProviderInfoRoundTrip(t *testing.T) {
+	p := (&schema.Provider{
+		Schema: schema.SchemaMap{
+			"region": (&schema.Schema{
+				Type:        shim.TypeString,
+				Optional:    true,
+				Description: "The region.",
+				DefaultFunc: func() (interface{}, error) { return "us-west-2", nil },
+			}).Shim(),
+		},
+		ResourcesMap: schema.ResourceMap{
+			"example_resource": (&schema.Resource{Schema: schema.SchemaMap{
+				"password": (&schema.Schema{
+					Type:          shim.TypeString,
+					Optional:      true,
+					Sensitive:     true,
+					ConflictsWith: []string{"password_file"},
+				}).Shim(),
+				"count": (&schema.Schema{
+					Type:     shim.TypeInt,
+					Optional: true,
+					Default:  3,
+				}).Shim(),
+			}}).Shim(),
+		},
+		DataSourcesMap: schema.ResourceMap{},
+	}).Shim()
+
+	info := &ProviderInfo{
+		P:              p,
+		Name:           "example",
+		ResourcePrefix: "ex",
+		Resources: map[string]*ResourceInfo{
+			"example_resource": {
+				Tok:                 "example:index:Resource",
+				DeleteBeforeReplace: true,
+				Fields: map[string]*SchemaInfo{
+					"source": {
+						Asset:     &AssetTranslation{Kind: FileAsset},
+						Transform: func(v resource.PropertyValue) (resource.PropertyValue, error) { return v, nil },
+						Default:   &DefaultInfo{Config: "source"},
+					},
+				},
+			},
+		},
+		PreConfigureCallback: func(resource.PropertyMap, shim.ResourceConfig) error { return nil },
+	}
+
+	marshaled := MarshalProviderInfo(info)
+	assert.Equal(t, MarshallableProviderInfoVersion, marshaled.FormatVersion)
+	assert.Equal(t, []string{
+		"preConfigureCallback",
+		"provider.schema.region.defaultFunc",
+		"resources.example_resource.fields.source.transform",
+	}, marshaled.Unserializable)
+
+	bytes, err := json.Marshal(marshaled)
+	require.NoError(t, err)
+	var m MarshallableProviderInfo
+	require.NoError(t, json.Unmarshal(bytes, &m))
+	require.NoError(t, m.CheckFormatVersion())
+	actual := m.Unmarshal()
+
+	assert.Equal(t, "ex", actual.ResourcePrefix)
+
+	region := actual.P.Schema().Get("region")
+	assert.Equal(t, "The region.", region.Description())
+	_, err = region.DefaultValue()
+	assert.Error(t, err)
+
+	res := actual.P.ResourcesMap().Get("example_resource")
+	password := res.Schema().Get("password")
+	assert.True(t, password.Sensitive())
+	assert.Equal(t, []string{"password_file"}, password.ConflictsWith())
+	assert.Equal(t, 3, res.Schema().Get("count").Default())
+
+	resInfo := actual.Resources["example_resource"]
+	assert.True(t, resInfo.DeleteBeforeReplace)
+	source := resInfo.Fields["source"]
+	assert.Equal(t, &AssetTranslation{Kind: FileAsset}, source.Asset)
+	assert.Equal(t, "source", source.Default.Config)
+	require.NotNil(t, source.Transform)
+	_, err = source.Transform(resource.NewStringProperty("x"))
+	assert.Error(t, err)
+}
+
+func TestUnmarshalLegacyProviderInfo(t *testing.T) {
+	legacy := `{"name":"example","version":"1.0.0","provider":null,` +
+		`"resources":{"example_resource":{"tok":"example:index:Resource","fields":{"tags":{"typeomitempty":"example:index:Tags"}},"idFields":null}}}`
+
+	var m MarshallableProviderInfo
+	require.NoError(t, json.Unmarshal([]byte(legacy), &m))
+	require.NoError(t, m.CheckFormatVersion())
+	info := m.Unmarshal()
+	assert.Equal(t, tokens.Type("example:index:Tags"), info.Resources["example_resource"].Fields["tags"].Type)
+
+	m.FormatVersion = MarshallableProviderInfoVersion + 1
+	assert.Error(t, m.CheckFormatVersion())
+}
@@ -0,0 +1,203 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// ResourceMetaVersion is the version of the resource metadata format written by this version of the bridge.
+const ResourceMetaVersion = 1
+
+// ResourceMetaKey is the key in a resource's outputs that holds its encoded ResourceMeta.
+const ResourceMetaKey = metaKey
+
+const (
+	// resourceMetaVersionKey is the key in encoded resource metadata that holds the version of its format.
+	resourceMetaVersionKey = "version"
+	// tfSchemaVersionKey is the key in a Terraform instance's private state that holds its schema version.
+	tfSchemaVersionKey = "schema_version"
+	// tfTimeoutsKey is the key in a Terraform instance's private state that holds its timeouts. This matches
+	// TimeoutKey in both versions of the Terraform plugin SDK.
+	tfTimeoutsKey = "e2bfb730-ecaa-11e6-8f88-34363bc7c4c0"
+)
+
+// ResourceMeta is the decoded form of the private state that a Terraform provider keeps alongside a resource
+// instance. The bridge records it in the resource's outputs under ResourceMetaKey.
+type ResourceMeta struct {
+	// SchemaVersion is the version of the resource schema that produced the state, if known.
+	SchemaVersion *int
+	// Timeouts holds the operation timeouts that were configured for the resource, keyed by operation ("create",
+	// "read", "update", "delete", or "default").
+	Timeouts map[string]time.Duration
+	// Private holds any other provider-defined private state.
+	Private map[string]interface{}
+}
+
+// IsEmpty returns true if the metadata holds no information.
+func (m *ResourceMeta) IsEmpty() bool {
+	return m == nil || m.SchemaVersion == nil && len(m.Timeouts) == 0 && len(m.Private) == 0
+}
+
+// MarshalJSON encodes the metadata using the current ResourceMetaVersion. The metadata is encoded as the raw
+// Terraform private state plus the version of the format, so that bridges that predate the version, which pass the
+// encoded metadata to the provider as is, can still read it after a downgrade.
+func (m ResourceMeta) MarshalJSON() ([]byte, error) {
+	raw := m.toTF()
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	raw[resourceMetaVersionKey] = ResourceMetaVersion
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON decodes metadata written by any version of the bridge. Metadata written before the format was
+// versioned has no version key.
+func (m *ResourceMeta) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if v, ok := raw[resourceMetaVersionKey]; ok {
+		version, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for resource metadata version", v)
+		}
+		if version > ResourceMetaVersion {
+			return fmt.Errorf("resource metadata version %v is newer than the supported version %d; "+
+				"upgrade the provider", version, ResourceMetaVersion)
+		}
+		delete(raw, resourceMetaVersionKey)
+	}
+
+	meta, err := resourceMetaFromTF(raw)
+	if err != nil {
+		return err
+	}
+	*m = *meta
+	return nil
+}
+
+// ParseResourceMeta decodes resource metadata from its string form.
+func ParseResourceMeta(s string) (*ResourceMeta, error) {
+	var meta ResourceMeta
+	if err := json.Unmarshal([]byte(s), &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// GetResourceMeta returns the metadata recorded in a resource's outputs, or nil if there is none.
+func GetResourceMeta(outputs resource.PropertyMap) (*ResourceMeta, error) {
+	v, ok := outputs[ResourceMetaKey]
+	if !ok || !v.IsString() {
+		return nil, nil
+	}
+	return ParseResourceMeta(v.StringValue())
+}
+
+// SetResourceMeta records metadata in a resource's outputs, replacing any existing metadata. Empty metadata removes
+// the entry altogether.
+func SetResourceMeta(outputs resource.PropertyMap, meta *ResourceMeta) error {
+	if meta.IsEmpty() {
+		delete(outputs, ResourceMetaKey)
+		return nil
+	}
+	bytes, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	outputs[ResourceMetaKey] = resource.NewStringProperty(string(bytes))
+	return nil
+}
+
+// resourceMetaFromTF decodes the raw private state of a Terraform instance.
+func resourceMetaFromTF(raw map[string]interface{}) (*ResourceMeta, error) {
+	meta := &ResourceMeta{}
+	for k, v := range raw {
+		switch k {
+		case tfSchemaVersionKey:
+			var version int
+			switch v := v.(type) {
+			case string:
+				i, err := strconv.Atoi(v)
+				if err != nil {
+					return nil, fmt.Errorf("invalid schema version: %w", err)
+				}
+				version = i
+			case float64:
+				version = int(v)
+			case int:
+				version = v
+			default:
+				return nil, fmt.Errorf("unexpected type %T for schema version", v)
+			}
+			meta.SchemaVersion = &version
+		case tfTimeoutsKey:
+			timeouts, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected type %T for timeouts", v)
+			}
+			meta.Timeouts = make(map[string]time.Duration, len(timeouts))
+			for op, t := range timeouts {
+				switch t := t.(type) {
+				case int64:
+					meta.Timeouts[op] = time.Duration(t)
+				case int:
+					meta.Timeouts[op] = time.Duration(t)
+				case float64:
+					meta.Timeouts[op] = time.Duration(t)
+				default:
+					return nil, fmt.Errorf("unexpected type %T for %q timeout", t, op)
+				}
+			}
+		default:
+			if meta.Private == nil {
+				meta.Private = map[string]interface{}{}
+			}
+			meta.Private[k] = v
+		}
+	}
+	return meta, nil
+}
+
+// toTF encodes the metadata as the raw private state of a Terraform instance. Timeouts are encoded as float64
+// nanoseconds, which is how they have always been read back from Pulumi state.
+func (m *ResourceMeta) toTF() map[string]interface{} {
+	if m.IsEmpty() {
+		return nil
+	}
+
+	raw := make(map[string]interface{}, len(m.Private)+2)
+	for k, v := range m.Private {
+		raw[k] = v
+	}
+	if m.SchemaVersion != nil {
+		raw[tfSchemaVersionKey] = strconv.Itoa(*m.SchemaVersion)
+	}
+	if len(m.Timeouts) != 0 {
+		timeouts := make(map[string]interface{}, len(m.Timeouts))
+		for op, t := range m.Timeouts {
+			timeouts[op] = float64(t.Nanoseconds())
+		}
+		raw[tfTimeoutsKey] = timeouts
+	}
+	return raw
+}
@@ -0,0 +1,91 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLegacyResourceMeta(t *testing.T) {
+	meta, err := ParseResourceMeta(`{
+		"schema_version": "2",
+		"e2bfb730-ecaa-11e6-8f88-34363bc7c4c0": {"create": 1200000000000, "delete": 300000000000},
+		"_new_extra_shim": {"foo": "bar"}
+	}`)
+	require.NoError(t, err)
+
+	require.NotNil(t, meta.SchemaVersion)
+	assert.Equal(t, 2, *meta.SchemaVersion)
+	assert.Equal(t, map[string]time.Duration{
+		"create": 20 * time.Minute,
+		"delete": 5 * time.Minute,
+	}, meta.Timeouts)
+	assert.Equal(t, map[string]interface{}{
+		"_new_extra_shim": map[string]interface{}{"foo": "bar"},
+	}, meta.Private)
+
+	_, err = ParseResourceMeta(`{"schema_version": "two"}`)
+	assert.Error(t, err)
+}
+
+func TestResourceMetaRoundTrip(t *testing.T) {
+	schemaVersion := 1
+	meta := &ResourceMeta{
+		SchemaVersion: &schemaVersion,
+		Timeouts:      map[string]time.Duration{"create": time.Hour},
+		Private:       map[string]interface{}{"foo": "bar"},
+	}
+
+	outputs := resource.PropertyMap{}
+	require.NoError(t, SetResourceMeta(outputs, meta))
+
+	var encoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(outputs[ResourceMetaKey].StringValue()), &encoded))
+	// The encoding keeps the raw Terraform keys so that older bridges can read it.
+	assert.Equal(t, map[string]interface{}{
+		"version":                              float64(ResourceMetaVersion),
+		"schema_version":                       "1",
+		"e2bfb730-ecaa-11e6-8f88-34363bc7c4c0": map[string]interface{}{"create": float64(time.Hour)},
+		"foo":                                  "bar",
+	}, encoded)
+
+	decoded, err := GetResourceMeta(outputs)
+	require.NoError(t, err)
+	assert.Equal(t, meta, decoded)
+
+	assert.Equal(t, map[string]interface{}{
+		"schema_version":                       "1",
+		"e2bfb730-ecaa-11e6-8f88-34363bc7c4c0": map[string]interface{}{"create": float64(time.Hour)},
+		"foo":                                  "bar",
+	}, decoded.toTF())
+
+	// Setting empty metadata removes it.
+	require.NoError(t, SetResourceMeta(outputs, &ResourceMeta{}))
+	assert.NotContains(t, outputs, ResourceMetaKey)
+	decoded, err = GetResourceMeta(outputs)
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+}
+
+func TestParseNewerResourceMeta(t *testing.T) {
+	_, err := ParseResourceMeta(`{"version": 2}`)
+	assert.Error(t, err)
+}
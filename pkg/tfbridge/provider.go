@@ -60,6 +60,7 @@ type Provider struct {
 	dataSources     map[tokens.ModuleMember]DataSource // a map of Pulumi module tokens to data sources.
 	supportsSecrets bool                               // true if the engine supports secret property values
 	pulumiSchema    []byte                             // the JSON-encoded Pulumi schema.
+	writeOnly       writeOnlyValues                    // the values of checked write-only properties.
 }
 
 // Resource wraps both the Terraform resource type info plus the overlay resource info.
@@ -556,7 +557,7 @@ func (p *Provider) Configure(ctx context.Context,
 	}
 
 	// Now actually attempt to do the configuring and return its resulting error (if any).
-	if err = p.tf.Configure(config); err != nil {
+	if err = p.tfConfigure(ctx, config); err != nil {
 		return nil, err
 	}
 
@@ -565,6 +566,25 @@ func (p *Provider) Configure(ctx context.Context,
 	}, nil
 }
 
+// tfFor returns the TF provider to use for operations on the resource with the given URN. If the provider info has a
+// ProviderMetaCallback, the returned provider passes the callback's result to TF as the value of provider_meta. Each
+// resource operation calls tfFor once and uses the result for every call it makes to TF.
+func (p *Provider) tfFor(urn resource.URN) (shim.Provider, error) {
+	if p.info.ProviderMetaCallback == nil {
+		return p.tf, nil
+	}
+	tf, ok := p.tf.(shim.ProviderWithMeta)
+	if !ok {
+		return p.tf, nil
+	}
+
+	meta, err := p.info.ProviderMetaCallback(p.configValues, urn)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build provider_meta")
+	}
+	return tf.WithProviderMeta(meta)
+}
+
 // Parse the TF error of a missing field:
 // https://github.com/hashicorp/terraform/blob/7f5ffbfe9027c34c4ce1062a42b6e8d80b5504e0/helper/schema/schema.go#L1356
 var requiredFieldRegex = regexp.MustCompile("\"(.*?)\": required field is not set")
@@ -710,8 +730,13 @@ func (p *Provider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (*pul
 
 	// After all is said and done, we need to go back and return only what got populated as a diff from the origin.
 	pinputs := MakeTerraformOutputs(p.tf, inputs, res.TF.Schema(), res.Schema.Fields, assets, false, p.supportsSecrets)
+
+	// Write-only values must never be stored, so the inputs that are returned hold only their salted hashes, as
+	// secrets. Create and Update retrieve the values from the provider.
+	p.writeOnly.hash(urn, pinputs, olds, res.TF.Schema(), res.Schema.Fields)
+
 	minputs, err := plugin.MarshalProperties(pinputs, plugin.MarshalOptions{
-		Label: fmt.Sprintf("%s.inputs", label), KeepUnknowns: true})
+		Label: fmt.Sprintf("%s.inputs", label), KeepUnknowns: true, KeepSecrets: p.supportsSecrets})
 	if err != nil {
 		return nil, err
 	}
@@ -732,6 +757,11 @@ func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulum
 	label := fmt.Sprintf("%s.Diff(%s/%s)", p.label(), urn, res.TFName)
 	glog.V(9).Infof("%s executing", label)
 
+	tf, err := p.tfFor(urn)
+	if err != nil {
+		return nil, err
+	}
+
 	// To figure out if we have a replacement, perform the diff and then look for RequiresNew flags.
 	olds, err := plugin.UnmarshalProperties(req.GetOlds(),
 		plugin.MarshalOptions{Label: fmt.Sprintf("%s.olds", label), SkipNulls: true})
@@ -753,7 +783,7 @@ func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulum
 		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
 	}
 
-	diff, err := p.tf.Diff(res.TFName, state, config)
+	diff, err := p.tfDiff(ctx, tf, urn, res, state, config)
 	if err != nil {
 		return nil, errors.Wrapf(err, "diffing %s", urn)
 	}
@@ -818,6 +848,19 @@ func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulum
 	}, nil
 }
 
+// restoreWriteOnlyValues returns a copy of the given inputs of a resource that holds the values of its write-only
+// properties rather than their hashes. It is an error for a value to be missing unless this is a preview.
+func (p *Provider) restoreWriteOnlyValues(urn resource.URN, res Resource, inputs resource.PropertyMap,
+	preview bool) (resource.PropertyMap, error) {
+
+	values, missing := p.writeOnly.restore(urn, inputs, res.TF.Schema(), res.Schema.Fields)
+	if len(missing) != 0 && !preview {
+		return nil, errors.Errorf("the values of the write-only properties of %s are not available: %s", urn,
+			strings.Join(missing, ", "))
+	}
+	return values, nil
+}
+
 // Create allocates a new instance of the provided resource and returns its unique ID afterwards.  (The input ID
 // must be blank.)  If this call fails, the resource must not have been created (i.e., it is "transactional").
 func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
@@ -832,16 +875,28 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 	label := fmt.Sprintf("%s.Create(%s/%s)", p.label(), urn, res.TFName)
 	glog.V(9).Infof("%s executing", label)
 
+	tf, err := p.tfFor(urn)
+	if err != nil {
+		return nil, err
+	}
+
 	// To get Terraform to create a new resource, the ID must be blank and existing state must be empty (since the
 	// resource does not exist yet), and the diff object should have no old state and all of the new state.
-	config, assets, err := UnmarshalTerraformConfig(
-		p, req.GetProperties(), res.TF.Schema(), res.Schema.Fields,
-		fmt.Sprintf("%s.news", label))
+	news, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label: fmt.Sprintf("%s.news", label), KeepUnknowns: true, SkipNulls: true})
+	if err != nil {
+		return nil, err
+	}
+	values, err := p.restoreWriteOnlyValues(urn, res, news, req.GetPreview())
+	if err != nil {
+		return nil, err
+	}
+	config, assets, err := MakeTerraformConfig(p, values, res.TF.Schema(), res.Schema.Fields)
 	if err != nil {
 		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
 	}
 
-	diff, err := p.tf.Diff(res.TFName, nil, config)
+	diff, err := p.tfDiff(ctx, tf, urn, res, nil, config)
 	if err != nil {
 		return nil, errors.Wrapf(err, "diffing %s", urn)
 	}
@@ -863,7 +918,7 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 	var newstate shim.InstanceState
 	var reasons []string
 	if !req.GetPreview() {
-		newstate, err = p.tf.Apply(res.TFName, nil, diff)
+		newstate, err = p.tfApply(ctx, tf, urn, res, nil, diff)
 		if newstate == nil {
 			if err == nil {
 				return nil, fmt.Errorf("expected non-nil error with nil state during Create of %s", urn)
@@ -884,10 +939,13 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 		}
 	}
 
-	// Create the ID and property maps and return them.
+	// Create the ID and property maps and return them. The outputs record the hashes of write-only values so that
+	// changes to them can be detected.
 	props, err := MakeTerraformResult(p.tf, newstate, res.TF.Schema(), res.Schema.Fields, assets, p.supportsSecrets)
 	if err != nil {
 		reasons = append(reasons, errors.Wrapf(err, "converting result for %s", urn).Error())
+	} else {
+		copyWriteOnlyProperties(props, news, res.TF.Schema(), res.Schema.Fields)
 	}
 
 	mprops, err := plugin.MarshalProperties(props, plugin.MarshalOptions{
@@ -920,13 +978,24 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 	label := fmt.Sprintf("%s.Read(%s, %s/%s)", p.label(), id, urn, res.TFName)
 	glog.V(9).Infof("%s executing", label)
 
+	tf, err := p.tfFor(urn)
+	if err != nil {
+		return nil, err
+	}
+
 	// Manufacture Terraform attributes and state with the provided properties, in preparation for reading.
 	oldInputs, err := plugin.UnmarshalProperties(req.GetInputs(), plugin.MarshalOptions{
 		Label: fmt.Sprintf("%s.inputs", label), KeepUnknowns: true})
 	if err != nil {
 		return nil, err
 	}
-	state, err := UnmarshalTerraformState(res, id, req.GetProperties(), fmt.Sprintf("%s.state", label))
+	olds, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label: fmt.Sprintf("%s.state", label), SkipNulls: true})
+	if err != nil {
+		return nil, err
+	}
+	// Write-only properties are stored as hashes, which mean nothing to the provider.
+	state, err := MakeTerraformState(res, id, withoutWriteOnlyProperties(olds, res.TF.Schema(), res.Schema.Fields))
 	if err != nil {
 		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
 	}
@@ -948,7 +1017,7 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 		}
 	}
 
-	newstate, err := p.tf.Refresh(res.TFName, state)
+	newstate, err := p.tfRefresh(ctx, tf, urn, res, state)
 	if err != nil {
 		return nil, errors.Wrapf(err, "refreshing %s", urn)
 	}
@@ -960,6 +1029,7 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 		if err != nil {
 			return nil, err
 		}
+		copyWriteOnlyProperties(props, olds, res.TF.Schema(), res.Schema.Fields)
 
 		mprops, err := plugin.MarshalProperties(props, plugin.MarshalOptions{
 			Label:       label + ".state",
@@ -1002,28 +1072,41 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 	label := fmt.Sprintf("%s.Update(%s/%s)", p.label(), urn, res.TFName)
 	glog.V(9).Infof("%s executing", label)
 
+	tf, err := p.tfFor(urn)
+	if err != nil {
+		return nil, err
+	}
+
 	// In order to perform the update, we first need to calculate the Terraform view of the diff.
 	olds, err := plugin.UnmarshalProperties(req.GetOlds(),
 		plugin.MarshalOptions{Label: fmt.Sprintf("%s.olds", label), SkipNulls: true})
 	if err != nil {
 		return nil, err
 	}
-	state, err := MakeTerraformState(res, req.GetId(), olds)
-	if err != nil {
-		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
-	}
 
 	news, err := plugin.UnmarshalProperties(req.GetNews(),
 		plugin.MarshalOptions{Label: fmt.Sprintf("%s.news", label), KeepUnknowns: true})
 	if err != nil {
 		return nil, err
 	}
-	config, assets, err := MakeTerraformConfig(p, news, res.TF.Schema(), res.Schema.Fields)
+	values, err := p.restoreWriteOnlyValues(urn, res, news, req.GetPreview())
+	if err != nil {
+		return nil, err
+	}
+
+	// Restore the prior write-only values that have not changed as well, so that the provider does not see a change.
+	priorValues, _ := p.writeOnly.restore(urn, olds, res.TF.Schema(), res.Schema.Fields)
+	state, err := MakeTerraformState(res, req.GetId(), priorValues)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
+	}
+
+	config, assets, err := MakeTerraformConfig(p, values, res.TF.Schema(), res.Schema.Fields)
 	if err != nil {
 		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
 	}
 
-	diff, err := p.tf.Diff(res.TFName, state, config)
+	diff, err := p.tfDiff(ctx, tf, urn, res, state, config)
 	if err != nil {
 		return nil, errors.Wrapf(err, "diffing %s", urn)
 	}
@@ -1049,7 +1132,7 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 	var newstate shim.InstanceState
 	var reasons []string
 	if !req.GetPreview() {
-		newstate, err = p.tf.Apply(res.TFName, state, diff)
+		newstate, err = p.tfApply(ctx, tf, urn, res, state, diff)
 		if newstate == nil {
 			if err != nil {
 				return nil, err
@@ -1075,6 +1158,8 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 	props, err := MakeTerraformResult(p.tf, newstate, res.TF.Schema(), res.Schema.Fields, assets, p.supportsSecrets)
 	if err != nil {
 		reasons = append(reasons, errors.Wrapf(err, "converting result for %s", urn).Error())
+	} else {
+		copyWriteOnlyProperties(props, news, res.TF.Schema(), res.Schema.Fields)
 	}
 	mprops, err := plugin.MarshalProperties(props, plugin.MarshalOptions{
 		Label:        fmt.Sprintf("%s.outs", label),
@@ -1104,8 +1189,20 @@ func (p *Provider) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*p
 	label := fmt.Sprintf("%s.Delete(%s/%s)", p.label(), urn, res.TFName)
 	glog.V(9).Infof("%s executing", label)
 
-	// Fetch the resource attributes since many providers need more than just the ID to perform the delete.
-	state, err := UnmarshalTerraformState(res, req.GetId(), req.GetProperties(), label)
+	tf, err := p.tfFor(urn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the resource attributes since many providers need more than just the ID to perform the delete. Write-only
+	// properties are stored as hashes, which mean nothing to the provider.
+	olds, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label: fmt.Sprintf("%s.state", label), SkipNulls: true})
+	if err != nil {
+		return nil, err
+	}
+	stateProps := withoutWriteOnlyProperties(olds, res.TF.Schema(), res.Schema.Fields)
+	state, err := MakeTerraformState(res, req.GetId(), stateProps)
 	if err != nil {
 		return nil, err
 	}
@@ -1116,7 +1213,7 @@ func (p *Provider) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*p
 		diff.SetTimeout(req.Timeout, shim.TimeoutDelete)
 	}
 
-	if _, err := p.tf.Apply(res.TFName, state, diff); err != nil {
+	if _, err := p.tfApply(ctx, tf, urn, res, state, diff); err != nil {
 		return nil, errors.Wrapf(err, "deleting %s", urn)
 	}
 	return &pbempty.Empty{}, nil
@@ -1184,7 +1281,7 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 			return nil, errors.Wrapf(err, "reading data source diff for %s", tok)
 		}
 
-		invoke, err := p.tf.ReadDataApply(tfname, diff)
+		invoke, err := p.tfReadDataApply(ctx, tok, ds, diff)
 		if err != nil {
 			return nil, errors.Wrapf(err, "invoking %s", tok)
 		}
@@ -2,19 +2,25 @@ package tfbridge
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"sort"
 	"testing"
 
+	diagv2 "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	shimv1 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v1"
 	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2/inprocess"
 )
 
 func TestConvertStringToPropertyValue(t *testing.T) {
@@ -199,7 +205,9 @@ func TestBuildConfig(t *testing.T) {
 	assert.Equal(t, expected, configOut)
 }
 
-func testIgnoreChanges(t *testing.T, provider *Provider) {
+// testIgnoreChanges tests that ignored changes are not applied. If grpc is true, the provider is driven through its
+// gRPC server.
+func testIgnoreChanges(t *testing.T, provider *Provider, grpc bool) {
 	urn := resource.NewURN("stack", "project", "", "ExampleResource", "name")
 
 	// Step 1: create and check an input bag.
@@ -228,7 +236,7 @@ func testIgnoreChanges(t *testing.T, provider *Provider) {
 		"id":                  resource.NewStringProperty(""),
 		"stringPropertyValue": resource.NewStringProperty("foo"),
 		"setPropertyValues":   resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty("foo")}),
-	}.DeepEquals(outs))
+	}.DeepEquals(normalizeOutputs(outs, grpc)))
 
 	// Step 2b: actually create the resource.
 	pulumiIns, err = plugin.MarshalProperties(resource.NewPropertyMapFromMap(map[string]interface{}{
@@ -293,25 +301,30 @@ func TestIgnoreChanges(t *testing.T) {
 			Schema: &ResourceInfo{Tok: "ExampleResource"},
 		},
 	}
-	testIgnoreChanges(t, provider)
+	testIgnoreChanges(t, provider, false)
 }
 
 func TestIgnoreChangesV2(t *testing.T) {
-	provider := &Provider{
-		tf:     shimv2.NewProvider(testTFProviderV2),
-		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
-	}
-	provider.resources = map[tokens.Type]Resource{
-		"ExampleResource": {
-			TF:     shimv2.NewResource(testTFProviderV2.ResourcesMap["example_resource"]),
-			TFName: "example_resource",
-			Schema: &ResourceInfo{Tok: "ExampleResource"},
-		},
-	}
-	testIgnoreChanges(t, provider)
+	forEachV2Mode(t, testTFProviderV2, func(t *testing.T, tf shim.Provider, grpc bool) {
+		provider := &Provider{
+			tf:     tf,
+			config: tf.Schema(),
+		}
+		provider.resources = map[tokens.Type]Resource{
+			"ExampleResource": {
+				TF:     tf.ResourcesMap().Get("example_resource"),
+				TFName: "example_resource",
+				Schema: &ResourceInfo{Tok: "ExampleResource"},
+			},
+		}
+		testIgnoreChanges(t, provider, grpc)
+	})
 }
 
-func testProviderPreview(t *testing.T, provider *Provider) {
+// testProviderPreview tests previews of a resource's creation and update. If grpc is true, the provider is driven
+// through its gRPC server, which reports unset optional properties as null rather than as their zero values, as
+// Terraform does.
+func testProviderPreview(t *testing.T, provider *Provider, grpc bool) {
 	urn := resource.NewURN("stack", "project", "", "ExampleResource", "name")
 
 	unknown := resource.MakeComputed(resource.NewStringProperty(""))
@@ -344,18 +357,21 @@ func testProviderPreview(t *testing.T, provider *Provider) {
 
 	outs, err := plugin.UnmarshalProperties(createResp.GetProperties(), plugin.MarshalOptions{KeepUnknowns: true})
 	assert.NoError(t, err)
+	nestedResources := resource.PropertyMap{
+		"kind": unknown,
+		"configuration": resource.NewObjectProperty(resource.PropertyMap{
+			"name": resource.NewStringProperty("foo"),
+		}),
+	}
+	if !grpc {
+		nestedResources["optBool"] = resource.NewBoolProperty(false)
+	}
 	assert.True(t, resource.PropertyMap{
 		"id":                  resource.NewStringProperty(""),
 		"stringPropertyValue": resource.NewStringProperty("foo"),
 		"setPropertyValues":   resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty("foo")}),
-		"nestedResources": resource.NewObjectProperty(resource.PropertyMap{
-			"kind": unknown,
-			"configuration": resource.NewObjectProperty(resource.PropertyMap{
-				"name": resource.NewStringProperty("foo"),
-			}),
-			"optBool": resource.NewBoolProperty(false),
-		}),
-	}.DeepEquals(outs))
+		"nestedResources":     resource.NewObjectProperty(nestedResources),
+	}.DeepEquals(normalizeOutputs(outs, grpc)))
 
 	// Step 2b: actually create the resource.
 	pulumiIns, err = plugin.MarshalProperties(resource.NewPropertyMapFromMap(map[string]interface{}{
@@ -432,22 +448,24 @@ func TestProviderPreview(t *testing.T) {
 			Schema: &ResourceInfo{Tok: "ExampleResource"},
 		},
 	}
-	testProviderPreview(t, provider)
+	testProviderPreview(t, provider, false)
 }
 
 func TestProviderPreviewV2(t *testing.T) {
-	provider := &Provider{
-		tf:     shimv2.NewProvider(testTFProviderV2),
-		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
-	}
-	provider.resources = map[tokens.Type]Resource{
-		"ExampleResource": {
-			TF:     shimv2.NewResource(testTFProviderV2.ResourcesMap["example_resource"]),
-			TFName: "example_resource",
-			Schema: &ResourceInfo{Tok: "ExampleResource"},
-		},
-	}
-	testProviderPreview(t, provider)
+	forEachV2Mode(t, testTFProviderV2, func(t *testing.T, tf shim.Provider, grpc bool) {
+		provider := &Provider{
+			tf:     tf,
+			config: tf.Schema(),
+		}
+		provider.resources = map[tokens.Type]Resource{
+			"ExampleResource": {
+				TF:     tf.ResourcesMap().Get("example_resource"),
+				TFName: "example_resource",
+				Schema: &ResourceInfo{Tok: "ExampleResource"},
+			},
+		}
+		testProviderPreview(t, provider, grpc)
+	})
 }
 
 func testCheckFailures(t *testing.T, provider *Provider, typeName tokens.Type) []*pulumirpc.CheckFailure {
@@ -500,29 +518,31 @@ func TestProviderCheck(t *testing.T) {
 }
 
 func TestProviderCheckV2(t *testing.T) {
-	provider := &Provider{
-		tf:     shimv2.NewProvider(testTFProviderV2),
-		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
-	}
-	provider.resources = map[tokens.Type]Resource{
-		"SecondResource": {
-			TF:     shimv2.NewResource(testTFProviderV2.ResourcesMap["second_resource"]),
-			TFName: "second_resource",
-			Schema: &ResourceInfo{Tok: "SecondResource"},
-		},
-	}
+	forEachV2Mode(t, testTFProviderV2, func(t *testing.T, tf shim.Provider, _ bool) {
+		provider := &Provider{
+			tf:     tf,
+			config: tf.Schema(),
+		}
+		provider.resources = map[tokens.Type]Resource{
+			"SecondResource": {
+				TF:     tf.ResourcesMap().Get("second_resource"),
+				TFName: "second_resource",
+				Schema: &ResourceInfo{Tok: "SecondResource"},
+			},
+		}
 
-	failures := testCheckFailures(t, provider, "SecondResource")
-	sort.SliceStable(failures, func(i, j int) bool { return failures[i].Reason < failures[j].Reason })
-	assert.Equal(t, "Conflicting configuration arguments: \"conflicting_property\": conflicts with "+
-		"conflicting_property2. Examine values at 'SecondResource.ConflictingProperty'.", failures[0].Reason)
-	assert.Equal(t, "", failures[0].Property)
-	assert.Equal(t, "Conflicting configuration arguments: \"conflicting_property2\": conflicts with "+
-		"conflicting_property. Examine values at 'SecondResource.ConflictingProperty2'.", failures[1].Reason)
-	assert.Equal(t, "", failures[1].Property)
-	assert.Equal(t, "Missing required argument: The argument \"array_property_value\" is required, but no "+
-		"definition was found.. Examine values at 'SecondResource.ArrayPropertyValues'.", failures[2].Reason)
-	assert.Equal(t, "", failures[2].Property)
+		failures := testCheckFailures(t, provider, "SecondResource")
+		sort.SliceStable(failures, func(i, j int) bool { return failures[i].Reason < failures[j].Reason })
+		assert.Equal(t, "Conflicting configuration arguments: \"conflicting_property\": conflicts with "+
+			"conflicting_property2. Examine values at 'SecondResource.ConflictingProperty'.", failures[0].Reason)
+		assert.Equal(t, "", failures[0].Property)
+		assert.Equal(t, "Conflicting configuration arguments: \"conflicting_property2\": conflicts with "+
+			"conflicting_property. Examine values at 'SecondResource.ConflictingProperty2'.", failures[1].Reason)
+		assert.Equal(t, "", failures[1].Property)
+		assert.Equal(t, "Missing required argument: The argument \"array_property_value\" is required, but no "+
+			"definition was found.. Examine values at 'SecondResource.ArrayPropertyValues'.", failures[2].Reason)
+		assert.Equal(t, "", failures[2].Property)
+	})
 }
 
 func testProviderPreConfigureCallback(t *testing.T, provider *Provider) {
@@ -550,14 +570,18 @@ func TestProviderPreConfigureCallbackV1(t *testing.T) {
 }
 
 func TestProviderPreConfigureCallbackV2(t *testing.T) {
-	provider := &Provider{
-		tf:     shimv2.NewProvider(testTFProviderV2),
-		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
-	}
-	testProviderPreConfigureCallback(t, provider)
+	forEachV2Mode(t, testTFProviderV2, func(t *testing.T, tf shim.Provider, _ bool) {
+		provider := &Provider{
+			tf:     tf,
+			config: tf.Schema(),
+		}
+		testProviderPreConfigureCallback(t, provider)
+	})
 }
 
-func testProviderRead(t *testing.T, provider *Provider, typeName tokens.Type) {
+// testProviderRead tests reading a resource. If grpc is true, the provider is driven through its gRPC server, which
+// does not preserve the order of set elements.
+func testProviderRead(t *testing.T, provider *Provider, typeName tokens.Type, grpc bool) {
 	urn := resource.NewURN("stack", "project", "", typeName, "name")
 	readResp, err := provider.Read(context.Background(), &pulumirpc.ReadRequest{
 		Id:         string("resource-id"),
@@ -591,11 +615,18 @@ func testProviderRead(t *testing.T, provider *Provider, typeName tokens.Type) {
 			"configurationValue": resource.NewStringProperty("true"),
 		}),
 	}), ins["nestedResources"])
-	assert.Equal(t, resource.NewArrayProperty(
-		[]resource.PropertyValue{
-			resource.NewStringProperty("set member 2"),
+	if grpc {
+		assert.ElementsMatch(t, []resource.PropertyValue{
 			resource.NewStringProperty("set member 1"),
-		}), ins["setPropertyValues"])
+			resource.NewStringProperty("set member 2"),
+		}, ins["setPropertyValues"].ArrayValue())
+	} else {
+		assert.Equal(t, resource.NewArrayProperty(
+			[]resource.PropertyValue{
+				resource.NewStringProperty("set member 2"),
+				resource.NewStringProperty("set member 1"),
+			}), ins["setPropertyValues"])
+	}
 	assert.Equal(t, resource.NewStringProperty("some ${interpolated:value} with syntax errors"),
 		ins["stringWithBadInterpolation"])
 
@@ -639,23 +670,25 @@ func TestProviderReadV1(t *testing.T) {
 		},
 	}
 
-	testProviderRead(t, provider, "ExampleResource")
+	testProviderRead(t, provider, "ExampleResource", false)
 }
 
 func TestProviderReadV2(t *testing.T) {
-	provider := &Provider{
-		tf:     shimv2.NewProvider(testTFProviderV2),
-		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
-	}
-	provider.resources = map[tokens.Type]Resource{
-		"ExampleResource": {
-			TF:     shimv2.NewResource(testTFProviderV2.ResourcesMap["example_resource"]),
-			TFName: "example_resource",
-			Schema: &ResourceInfo{Tok: "ExampleResource"},
-		},
-	}
+	forEachV2Mode(t, testTFProviderV2, func(t *testing.T, tf shim.Provider, grpc bool) {
+		provider := &Provider{
+			tf:     tf,
+			config: tf.Schema(),
+		}
+		provider.resources = map[tokens.Type]Resource{
+			"ExampleResource": {
+				TF:     tf.ResourcesMap().Get("example_resource"),
+				TFName: "example_resource",
+				Schema: &ResourceInfo{Tok: "ExampleResource"},
+			},
+		}
 
-	testProviderRead(t, provider, "ExampleResource")
+		testProviderRead(t, provider, "ExampleResource", grpc)
+	})
 }
 
 func testProviderReadNestedSecret(t *testing.T, provider *Provider, typeName tokens.Type) {
@@ -719,17 +752,283 @@ func TestProviderReadNestedSecretV1(t *testing.T) {
 }
 
 func TestProviderReadNestedSecretV2(t *testing.T) {
-	provider := &Provider{
-		tf:     shimv2.NewProvider(testTFProviderV2),
-		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
+	forEachV2Mode(t, testTFProviderV2, func(t *testing.T, tf shim.Provider, _ bool) {
+		provider := &Provider{
+			tf:     tf,
+			config: tf.Schema(),
+		}
+		provider.resources = map[tokens.Type]Resource{
+			"NestedSecretResource": {
+				TF:     tf.ResourcesMap().Get("nested_secret_resource"),
+				TFName: "nested_secret_resource",
+				Schema: &ResourceInfo{Tok: "NestedSecretResource"},
+			},
+		}
+
+		testProviderReadNestedSecret(t, provider, "NestedSecretResource")
+	})
+}
+
+func TestProviderMetaCallback(t *testing.T) {
+	tf := &schemav2.Provider{
+		ProviderMetaSchema: map[string]*schemav2.Schema{
+			"stack": {Type: schemav2.TypeString, Optional: true},
+		},
+		ResourcesMap: map[string]*schemav2.Resource{
+			"example_resource": {
+				Schema: map[string]*schemav2.Schema{
+					"stack": {Type: schemav2.TypeString, Computed: true},
+				},
+				CreateContext: func(_ context.Context, d *schemav2.ResourceData, _ interface{}) diagv2.Diagnostics {
+					var meta struct {
+						Stack string `cty:"stack"`
+					}
+					if err := d.GetProviderMeta(&meta); err != nil {
+						return diagv2.FromErr(err)
+					}
+					d.SetId("0")
+					return diagv2.FromErr(d.Set("stack", meta.Stack))
+				},
+				ReadContext: func(context.Context, *schemav2.ResourceData, interface{}) diagv2.Diagnostics {
+					return nil
+				},
+				DeleteContext: func(context.Context, *schemav2.ResourceData, interface{}) diagv2.Diagnostics {
+					return nil
+				},
+			},
+		},
 	}
-	provider.resources = map[tokens.Type]Resource{
-		"NestedSecretResource": {
-			TF:     shimv2.NewResource(testTFProviderV2.ResourcesMap["nested_secret_resource"]),
-			TFName: "nested_secret_resource",
-			Schema: &ResourceInfo{Tok: "NestedSecretResource"},
+
+	forEachV2Mode(t, tf, func(t *testing.T, p shim.Provider, _ bool) {
+		urn := resource.NewURN("dev", "project", "", "ExampleResource", "name")
+		calls := 0
+		provider := &Provider{
+			tf:     p,
+			config: p.Schema(),
+			info: ProviderInfo{
+				ProviderMetaCallback: func(vars resource.PropertyMap, u resource.URN) (map[string]interface{}, error) {
+					assert.Equal(t, urn, u)
+					calls++
+					return map[string]interface{}{"stack": u.Stack().String()}, nil
+				},
+			},
+		}
+		provider.resources = map[tokens.Type]Resource{
+			"ExampleResource": {
+				TF:     p.ResourcesMap().Get("example_resource"),
+				TFName: "example_resource",
+				Schema: &ResourceInfo{Tok: "ExampleResource"},
+			},
+		}
+
+		createResp, err := provider.Create(context.Background(), &pulumirpc.CreateRequest{Urn: string(urn)})
+		require.NoError(t, err)
+
+		outs, err := plugin.UnmarshalProperties(createResp.GetProperties(), plugin.MarshalOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, resource.NewStringProperty("dev"), outs["stack"])
+
+		// The callback runs once per operation, although Create both diffs and applies.
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestProviderWriteOnly(t *testing.T) {
+	var passwords []string
+	setPassword := func(d *schemav2.ResourceData) diagv2.Diagnostics {
+		passwords = append(passwords, d.Get("password").(string))
+		return nil
+	}
+	tf := &schemav2.Provider{
+		ResourcesMap: map[string]*schemav2.Resource{
+			"example_resource": {
+				Schema: map[string]*schemav2.Schema{
+					"name":     {Type: schemav2.TypeString, Optional: true},
+					"password": {Type: schemav2.TypeString, Optional: true, Sensitive: true},
+				},
+				CreateContext: func(_ context.Context, d *schemav2.ResourceData, _ interface{}) diagv2.Diagnostics {
+					d.SetId("0")
+					return setPassword(d)
+				},
+				UpdateContext: func(_ context.Context, d *schemav2.ResourceData, _ interface{}) diagv2.Diagnostics {
+					return setPassword(d)
+				},
+				ReadContext: func(context.Context, *schemav2.ResourceData, interface{}) diagv2.Diagnostics {
+					return nil
+				},
+				DeleteContext: func(context.Context, *schemav2.ResourceData, interface{}) diagv2.Diagnostics {
+					return nil
+				},
+			},
 		},
 	}
 
-	testProviderReadNestedSecret(t, provider, "NestedSecretResource")
+	forEachV2Mode(t, tf, func(t *testing.T, p shim.Provider, _ bool) {
+		passwords = nil
+
+		urn := resource.NewURN("dev", "project", "", "ExampleResource", "name")
+		provider := &Provider{
+			tf:              p,
+			config:          p.Schema(),
+			supportsSecrets: true,
+		}
+		provider.resources = map[tokens.Type]Resource{
+			"ExampleResource": {
+				TF:     p.ResourcesMap().Get("example_resource"),
+				TFName: "example_resource",
+				Schema: &ResourceInfo{
+					Tok:    "ExampleResource",
+					Fields: map[string]*SchemaInfo{"password": {WriteOnly: true}},
+				},
+			},
+		}
+
+		news, err := plugin.MarshalProperties(resource.PropertyMap{
+			"name":     resource.NewStringProperty("foo"),
+			"password": resource.NewStringProperty("hunter2"),
+		}, plugin.MarshalOptions{})
+		require.NoError(t, err)
+
+		// Check replaces the password with a secret, salted hash.
+		checkResp, err := provider.Check(context.Background(), &pulumirpc.CheckRequest{Urn: string(urn), News: news})
+		require.NoError(t, err)
+		inputs, err := plugin.UnmarshalProperties(checkResp.GetInputs(), plugin.MarshalOptions{KeepSecrets: true})
+		require.NoError(t, err)
+		hash := inputs["password"]
+		require.True(t, hash.IsSecret())
+		assert.True(t, matchesWriteOnlyHash(resource.NewStringProperty("hunter2"), hash))
+		unsalted := sha256.Sum256([]byte(`"hunter2"`))
+		assert.NotContains(t, hash.SecretValue().Element.StringValue(), hex.EncodeToString(unsalted[:]))
+
+		// Checking the same password again keeps the old hash.
+		recheckResp, err := provider.Check(context.Background(), &pulumirpc.CheckRequest{
+			Urn:  string(urn),
+			Olds: checkResp.GetInputs(),
+			News: news,
+		})
+		require.NoError(t, err)
+		inputs, err = plugin.UnmarshalProperties(recheckResp.GetInputs(), plugin.MarshalOptions{KeepSecrets: true})
+		require.NoError(t, err)
+		assert.Equal(t, hash, inputs["password"])
+
+		// Create passes the password to the provider, but records only its hash in the outputs.
+		createResp, err := provider.Create(context.Background(), &pulumirpc.CreateRequest{
+			Urn:        string(urn),
+			Properties: checkResp.GetInputs(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"hunter2"}, passwords)
+		outs, err := plugin.UnmarshalProperties(createResp.GetProperties(), plugin.MarshalOptions{KeepSecrets: true})
+		require.NoError(t, err)
+		assert.Equal(t, hash, outs["password"])
+		assert.Equal(t, resource.NewStringProperty("foo"), outs["name"])
+
+		// An unchanged password does not cause a diff.
+		diffResp, err := provider.Diff(context.Background(), &pulumirpc.DiffRequest{
+			Id:   createResp.GetId(),
+			Urn:  string(urn),
+			Olds: createResp.GetProperties(),
+			News: checkResp.GetInputs(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, diffResp.GetChanges())
+
+		// A changed password does.
+		news, err = plugin.MarshalProperties(resource.PropertyMap{
+			"name":     resource.NewStringProperty("foo"),
+			"password": resource.NewStringProperty("hunter3"),
+		}, plugin.MarshalOptions{})
+		require.NoError(t, err)
+		checkResp, err = provider.Check(context.Background(), &pulumirpc.CheckRequest{
+			Urn:  string(urn),
+			Olds: createResp.GetProperties(),
+			News: news,
+		})
+		require.NoError(t, err)
+		diffResp, err = provider.Diff(context.Background(), &pulumirpc.DiffRequest{
+			Id:   createResp.GetId(),
+			Urn:  string(urn),
+			Olds: createResp.GetProperties(),
+			News: checkResp.GetInputs(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, diffResp.GetChanges())
+		assert.Equal(t, []string{"password"}, diffResp.GetDiffs())
+
+		updateResp, err := provider.Update(context.Background(), &pulumirpc.UpdateRequest{
+			Id:   createResp.GetId(),
+			Urn:  string(urn),
+			Olds: createResp.GetProperties(),
+			News: checkResp.GetInputs(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"hunter2", "hunter3"}, passwords)
+		outs, err = plugin.UnmarshalProperties(updateResp.GetProperties(), plugin.MarshalOptions{KeepSecrets: true})
+		require.NoError(t, err)
+		require.True(t, outs["password"].IsSecret())
+		assert.True(t, matchesWriteOnlyHash(resource.NewStringProperty("hunter3"), outs["password"]))
+
+		// Refreshing keeps the hash, and the provider never sees it.
+		readResp, err := provider.Read(context.Background(), &pulumirpc.ReadRequest{
+			Id:         createResp.GetId(),
+			Urn:        string(urn),
+			Properties: updateResp.GetProperties(),
+		})
+		require.NoError(t, err)
+		outs, err = plugin.UnmarshalProperties(readResp.GetProperties(), plugin.MarshalOptions{KeepSecrets: true})
+		require.NoError(t, err)
+		require.True(t, outs["password"].IsSecret())
+		assert.True(t, matchesWriteOnlyHash(resource.NewStringProperty("hunter3"), outs["password"]))
+
+		// Without the value that Check recorded, Update fails rather than sending the hash to the provider.
+		_, err = (&Provider{tf: p, config: p.Schema(), resources: provider.resources}).Update(context.Background(),
+			&pulumirpc.UpdateRequest{
+				Id:   createResp.GetId(),
+				Urn:  string(urn),
+				Olds: createResp.GetProperties(),
+				News: checkResp.GetInputs(),
+			})
+		assert.Error(t, err)
+		assert.Equal(t, []string{"hunter2", "hunter3"}, passwords)
+	})
+}
+
+// forEachV2Mode runs a test against an SDKv2 provider both when it is driven through the SDK's Go APIs and when it is
+// driven through its gRPC server. The test is told whether the provider is driven through its gRPC server.
+func forEachV2Mode(t *testing.T, p *schemav2.Provider, test func(t *testing.T, tf shim.Provider, grpc bool)) {
+	t.Run("sdk", func(t *testing.T) {
+		test(t, shimv2.NewProvider(p), false)
+	})
+	t.Run("grpc", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tf, err := inprocess.NewGRPCProvider(ctx, p)
+		require.NoError(t, err)
+		test(t, tf, true)
+	})
+}
+
+// normalizeOutputs removes the null properties and the private state that the gRPC shim includes in a resource's
+// outputs so that outputs can be compared across modes. An unknown ID, which the gRPC shim reports for a resource that
+// has yet to be created, is replaced by the empty ID that the SDK shim reports. Outputs are only normalized if grpc is
+// true.
+func normalizeOutputs(outs resource.PropertyMap, grpc bool) resource.PropertyMap {
+	if !grpc {
+		return outs
+	}
+	normalized := resource.PropertyMap{}
+	for k, v := range outs {
+		switch {
+		case v.IsNull() || k == metaKey:
+			continue
+		case k == "id" && v.IsComputed():
+			normalized[k] = resource.NewStringProperty("")
+		case v.IsObject():
+			normalized[k] = resource.NewObjectProperty(normalizeOutputs(v.ObjectValue(), grpc))
+		default:
+			normalized[k] = v
+		}
+	}
+	return normalized
 }
@@ -15,7 +15,6 @@
 package tfbridge
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"reflect"
@@ -51,7 +50,10 @@ import (
 //     - map[string]interface{}
 //     - Set (state only; exact type varies between shim implementations; see shim.Provider.IsSet)
 //
-// Unknown values are represented using a sentinel string value (see TerraformUnknownVariableValue).
+// Unknown values are represented using a sentinel string value (see TerraformUnknownVariableValue). Unknown lists and
+// sets are represented as lists of unknown elements unless the shim supports unknown collections (see
+// shim.ProviderWithUnknownCollections and shim.SchemaMapWithUnknownCollections), in which case they are represented
+// by the sentinel itself.
 //
 // The Terraform shim also records a schema for each resource & data source that is used to guide the conversion
 // process. The schema indicates the type or sub-schema for each of the resource's properties. The schema types
@@ -285,16 +287,34 @@ type conversionContext struct {
 	ProviderConfig resource.PropertyMap
 	ApplyDefaults  bool
 	Assets         AssetTable
+
+	// UnknownCollections is true if unknown lists and sets can be represented by a single unknown value rather than
+	// by a collection of unknown elements (see shim.ProviderWithUnknownCollections).
+	UnknownCollections bool
+}
+
+// supportsUnknownCollections returns true if the given provider can represent unknown lists and sets.
+func supportsUnknownCollections(p shim.Provider) bool {
+	u, ok := p.(shim.ProviderWithUnknownCollections)
+	return ok && u.SupportsUnknownCollections()
+}
+
+// schemaSupportsUnknownCollections returns true if the provider of the given schema can represent unknown lists and
+// sets.
+func schemaSupportsUnknownCollections(tfs shim.SchemaMap) bool {
+	u, ok := tfs.(shim.SchemaMapWithUnknownCollections)
+	return ok && u.SupportsUnknownCollections()
 }
 
 func MakeTerraformInputs(instance *PulumiResource, config resource.PropertyMap, olds, news resource.PropertyMap,
 	tfs shim.SchemaMap, ps map[string]*SchemaInfo) (map[string]interface{}, AssetTable, error) {
 
 	ctx := &conversionContext{
-		Instance:       instance,
-		ProviderConfig: config,
-		ApplyDefaults:  true,
-		Assets:         AssetTable{},
+		Instance:           instance,
+		ProviderConfig:     config,
+		ApplyDefaults:      true,
+		Assets:             AssetTable{},
+		UnknownCollections: schemaSupportsUnknownCollections(tfs),
 	}
 	inputs, err := ctx.MakeTerraformInputs(olds, news, tfs, ps, false)
 	if err != nil {
@@ -317,9 +337,12 @@ func (ctx *conversionContext) MakeTerraformInput(name string, old, v resource.Pr
 		} else {
 			old = resource.NewArrayProperty([]resource.PropertyValue{old})
 		}
-		if v.IsNull() {
+		switch {
+		case v.IsNull():
 			v = resource.NewArrayProperty([]resource.PropertyValue{})
-		} else {
+		case ctx.UnknownCollections && (v.IsComputed() || v.IsOutput()):
+			// An unknown value may stand for either no element or one element, so the list itself is unknown.
+		default:
 			v = resource.NewArrayProperty([]resource.PropertyValue{v})
 		}
 	}
@@ -432,6 +455,9 @@ func (ctx *conversionContext) MakeTerraformInput(name string, old, v resource.Pr
 		// If any variables are unknown, we need to mark them in the inputs so the config map treats it right.  This
 		// requires the use of the special UnknownVariableValue sentinel in Terraform, which is how it internally stores
 		// interpolated variables whose inputs are currently unknown.
+		if ctx.UnknownCollections {
+			return TerraformUnknownVariableValue, nil
+		}
 		return makeTerraformUnknown(tfs), nil
 	default:
 		contract.Failf("Unexpected value marshaled: %v", v)
@@ -772,11 +798,18 @@ func MakeTerraformResult(p shim.Provider, state shim.InstanceState,
 
 	outMap := MakeTerraformOutputs(p, outs, tfs, ps, assets, false, supportsSecrets)
 
+	// Write-only properties are never recorded in outputs.
+	removeWriteOnlyProperties(outMap, tfs, ps)
+
 	// If there is any Terraform metadata associated with this state, record it.
 	if state != nil && len(state.Meta()) != 0 {
-		metaJSON, err := json.Marshal(state.Meta())
-		contract.Assert(err == nil)
-		outMap[metaKey] = resource.NewStringProperty(string(metaJSON))
+		meta, err := resourceMetaFromTF(state.Meta())
+		if err != nil {
+			return nil, err
+		}
+		if err := SetResourceMeta(outMap, meta); err != nil {
+			return nil, err
+		}
 	}
 
 	return outMap, nil
@@ -937,8 +970,9 @@ func MakeTerraformConfig(p *Provider, m resource.PropertyMap,
 
 	// Convert the resource bag into an untyped map, and then create the resource config object.
 	ctx := conversionContext{
-		ProviderConfig: p.configValues,
-		Assets:         AssetTable{},
+		ProviderConfig:     p.configValues,
+		Assets:             AssetTable{},
+		UnknownCollections: supportsUnknownCollections(p.tf),
 	}
 	inputs, err := ctx.MakeTerraformInputs(nil, m, tfs, ps, false)
 	if err != nil {
@@ -997,15 +1031,15 @@ func MakeTerraformConfigFromInputs(p shim.Provider, inputs map[string]interface{
 // Terraform represents resource properties (schemas are simply sugar on top).
 func MakeTerraformState(res Resource, id string, m resource.PropertyMap) (shim.InstanceState, error) {
 	// Parse out any metadata from the state.
-	var meta map[string]interface{}
-	if metaProperty, hasMeta := m[metaKey]; hasMeta && metaProperty.IsString() {
-		if err := json.Unmarshal([]byte(metaProperty.StringValue()), &meta); err != nil {
-			return nil, err
-		}
-	} else if res.TF.SchemaVersion() > 0 {
+	meta, err := GetResourceMeta(m)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil && res.TF.SchemaVersion() > 0 {
 		// If there was no metadata in the inputs and this resource has a non-zero schema version, return a meta bag
 		// with the current schema version. This helps avoid migration issues.
-		meta = map[string]interface{}{"schema_version": strconv.Itoa(res.TF.SchemaVersion())}
+		schemaVersion := res.TF.SchemaVersion()
+		meta = &ResourceMeta{SchemaVersion: &schemaVersion}
 	}
 
 	// Turn the resource properties into a map. For the most part, this is a straight Mappable, but we use MapReplace
@@ -1016,7 +1050,7 @@ func MakeTerraformState(res Resource, id string, m resource.PropertyMap) (shim.I
 		return nil, err
 	}
 
-	return res.TF.InstanceState(id, inputs, meta)
+	return res.TF.InstanceState(id, inputs, meta.toTF())
 }
 
 // UnmarshalTerraformState unmarshals a Terraform instance state from an RPC property map.
@@ -913,6 +913,96 @@ func TestComputedAsset(t *testing.T) {
 	}, outputs)
 }
 
+// unknownCollectionsSchemaMap marks a schema map as belonging to a provider that supports unknown collections.
+type unknownCollectionsSchemaMap struct {
+	shim.SchemaMap
+}
+
+func (unknownCollectionsSchemaMap) SupportsUnknownCollections() bool {
+	return true
+}
+
+// objectState is an instance state that is backed by a fixed object.
+type objectState struct {
+	object map[string]interface{}
+}
+
+func (s objectState) Type() string                 { return "" }
+func (s objectState) ID() string                   { return "" }
+func (s objectState) Meta() map[string]interface{} { return nil }
+func (s objectState) Object(sch shim.SchemaMap) (map[string]interface{}, error) {
+	return s.object, nil
+}
+
+func TestUnknownCollections(t *testing.T) {
+	tfs := shimv1.NewSchemaMap(map[string]*schemav1.Schema{
+		"items":   {Type: schemav1.TypeList, Elem: &schemav1.Schema{Type: schemav1.TypeString}},
+		"members": {Type: schemav1.TypeSet, Elem: &schemav1.Schema{Type: schemav1.TypeString}},
+		"values":  {Type: schemav1.TypeList, Elem: &schemav1.Schema{Type: schemav1.TypeString}},
+		"tags":    {Type: schemav1.TypeMap, Elem: &schemav1.Schema{Type: schemav1.TypeString}},
+		"labels":  {Type: schemav1.TypeMap, Elem: &schemav1.Schema{Type: schemav1.TypeString}},
+		"block": {Type: schemav1.TypeList, MaxItems: 1, Elem: &schemav1.Resource{
+			Schema: map[string]*schemav1.Schema{
+				"name": {Type: schemav1.TypeString, Required: true},
+			},
+		}},
+	})
+	computed := resource.MakeComputed(resource.NewStringProperty(""))
+	props := resource.PropertyMap{
+		"items":   computed,
+		"members": computed,
+		"values":  resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty("a"), computed}),
+		"tags":    computed,
+		"labels": resource.NewObjectProperty(resource.PropertyMap{
+			"a": resource.NewStringProperty("x"),
+			"b": computed,
+		}),
+		"block": computed,
+	}
+
+	// By default, unknown collections are represented as collections of unknown elements.
+	inputs, _, err := makeTerraformInputs(nil, props, tfs, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"items":   []interface{}{TerraformUnknownVariableValue},
+		"members": []interface{}{TerraformUnknownVariableValue},
+		"values":  []interface{}{"a", TerraformUnknownVariableValue},
+		"tags":    TerraformUnknownVariableValue,
+		"labels":  map[string]interface{}{"a": "x", "b": TerraformUnknownVariableValue},
+		"block":   []interface{}{TerraformUnknownVariableValue},
+	}, inputs)
+
+	// If the provider supports them, unknown collections are represented as a single unknown, and partially-known
+	// collections keep their known elements.
+	expected := map[string]interface{}{
+		"items":   TerraformUnknownVariableValue,
+		"members": TerraformUnknownVariableValue,
+		"values":  []interface{}{"a", TerraformUnknownVariableValue},
+		"tags":    TerraformUnknownVariableValue,
+		"labels":  map[string]interface{}{"a": "x", "b": TerraformUnknownVariableValue},
+		"block":   TerraformUnknownVariableValue,
+	}
+	ctx := &conversionContext{UnknownCollections: true}
+	inputs, err = ctx.MakeTerraformInputs(nil, props, tfs, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, inputs)
+
+	outputs := MakeTerraformOutputs(shimv1.NewProvider(testTFProvider), inputs, tfs, nil, nil, false, true)
+	assert.Equal(t, props, outputs)
+
+	// Unknown collections in a planned state become computed outputs.
+	outputs, err = MakeTerraformResult(shimv1.NewProvider(testTFProvider), objectState{inputs}, tfs, nil, nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, props, outputs)
+
+	// The exported conversion does the same when the schema map says that its provider supports unknown collections.
+	inputs, _, err = MakeTerraformInputs(nil, nil, nil, props, unknownCollectionsSchemaMap{tfs}, nil)
+	assert.NoError(t, err)
+	for _, k := range []string{"items", "members", "values", "tags", "block"} {
+		assert.Equal(t, expected[k], inputs[k], k)
+	}
+}
+
 func TestInvalidAsset(t *testing.T) {
 	tfs := shimv1.NewSchemaMap(map[string]*schemav1.Schema{
 		"zzz": {Type: schemav1.TypeString},
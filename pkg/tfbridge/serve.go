@@ -16,17 +16,43 @@ package tfbridge
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
 	lumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 )
 
 // Serve fires up a Pulumi resource provider listening to inbound gRPC traffic,
 // and translates calls from Pulumi into actions against the provided Terraform Provider.
 func Serve(module string, version string, info ProviderInfo, pulumiSchema []byte) error {
+	// The TF provider's in-process server, if any, lives as long as the resource provider: Main returns once the
+	// resource provider's server has stopped.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// Create a new resource provider server and listen for and serve incoming connections.
 	return provider.Main(module, func(host *provider.HostClient) (lumirpc.ResourceProviderServer, error) {
+		tf, err := serveProvider(ctx, module, info)
+		if err != nil {
+			return nil, err
+		}
+
 		// Create a new bridge provider.
-		return NewProvider(context.TODO(), host, module, version, info.P, info, pulumiSchema), nil
+		return NewProvider(context.TODO(), host, module, version, tf, info, pulumiSchema), nil
 	})
 }
+
+// serveProvider returns the TF provider that the bridge drives. If the provider opts in to UseGRPCServer, this serves
+// the provider over gRPC in-process and returns a provider that communicates with the server.
+func serveProvider(ctx context.Context, module string, info ProviderInfo) (shim.Provider, error) {
+	if !info.UseGRPCServer {
+		return info.P, nil
+	}
+	p, ok := info.P.(shim.ProviderWithGRPCServer)
+	if !ok {
+		return nil, fmt.Errorf("the %v provider cannot be served over gRPC", module)
+	}
+	return p.GRPCServerProvider(ctx)
+}
@@ -0,0 +1,156 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"golang.org/x/net/context"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+)
+
+// formatWarning formats a warning reported by the Terraform provider. If the warning applies to an attribute, the
+// path of the corresponding Pulumi property is appended to the message.
+func formatWarning(tokenType tokens.Type, res Resource, warning *diagnostics.Warning) string {
+	message := warning.String()
+	if len(warning.AttributePath) > 0 {
+		message += fmt.Sprintf(". Examine values at '%s'.", strings.Join(
+			pathToAttributePath(warning.AttributePath, tokenType, res), ""))
+	}
+	return message
+}
+
+// logWarnings logs the warnings reported by the Terraform provider against the given URN. The engine already
+// attributes warnings that are logged against a URN to their resource, so the prefix is only needed for warnings that
+// are not, such as those reported by data sources.
+func (p *Provider) logWarnings(ctx context.Context, urn resource.URN, prefix string, tokenType tokens.Type,
+	res Resource, warnings []*diagnostics.Warning) error {
+
+	for _, warning := range warnings {
+		message := formatWarning(tokenType, res, warning)
+		if prefix != "" {
+			message = fmt.Sprintf("%v warning: %v", prefix, message)
+		}
+		if err := p.host.Log(ctx, diag.Warning, urn, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dataSourceResource returns a Resource that describes the schema of the given data source. This allows the
+// attribute paths of data source warnings to be translated like those of resources.
+func dataSourceResource(ds DataSource) Resource {
+	info := &ResourceInfo{}
+	if ds.Schema != nil {
+		info.Fields = ds.Schema.Fields
+	}
+	return Resource{Schema: info, TF: ds.TF, TFName: ds.TFName}
+}
+
+// tfConfigure configures the Terraform provider and logs any warnings it reports.
+func (p *Provider) tfConfigure(ctx context.Context, config shim.ResourceConfig) error {
+	tf, ok := p.tf.(shim.ProviderWithWarnings)
+	if !ok {
+		return p.tf.Configure(config)
+	}
+
+	warnings, err := tf.ConfigureWithWarnings(config)
+	for _, warning := range warnings {
+		message := fmt.Sprintf("provider config warning: %v", warning)
+		if logErr := p.host.Log(ctx, diag.Warning, "", message); logErr != nil {
+			return logErr
+		}
+	}
+	return err
+}
+
+// tfDiff diffs a resource using the given Terraform provider and logs any warnings it reports against the resource's
+// URN.
+func (p *Provider) tfDiff(ctx context.Context, provider shim.Provider, urn resource.URN, res Resource,
+	s shim.InstanceState, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+
+	tf, ok := provider.(shim.ProviderWithWarnings)
+	if !ok {
+		return provider.Diff(res.TFName, s, c)
+	}
+
+	diff, warnings, err := tf.DiffWithWarnings(res.TFName, s, c)
+	if logErr := p.logWarnings(ctx, urn, "", urn.Type(), res, warnings); logErr != nil {
+		return nil, logErr
+	}
+	return diff, err
+}
+
+// tfApply applies a diff to a resource using the given Terraform provider and logs any warnings it reports against the
+// resource's URN.
+func (p *Provider) tfApply(ctx context.Context, provider shim.Provider, urn resource.URN, res Resource,
+	s shim.InstanceState, d shim.InstanceDiff) (shim.InstanceState, error) {
+
+	tf, ok := provider.(shim.ProviderWithWarnings)
+	if !ok {
+		return provider.Apply(res.TFName, s, d)
+	}
+
+	state, warnings, err := tf.ApplyWithWarnings(res.TFName, s, d)
+	if logErr := p.logWarnings(ctx, urn, "", urn.Type(), res, warnings); logErr != nil {
+		return nil, logErr
+	}
+	return state, err
+}
+
+// tfRefresh refreshes a resource using the given Terraform provider and logs any warnings it reports against the
+// resource's URN.
+func (p *Provider) tfRefresh(ctx context.Context, provider shim.Provider, urn resource.URN, res Resource,
+	s shim.InstanceState) (shim.InstanceState, error) {
+
+	tf, ok := provider.(shim.ProviderWithWarnings)
+	if !ok {
+		return provider.Refresh(res.TFName, s)
+	}
+
+	state, warnings, err := tf.RefreshWithWarnings(res.TFName, s)
+	if logErr := p.logWarnings(ctx, urn, "", urn.Type(), res, warnings); logErr != nil {
+		return nil, logErr
+	}
+	return state, err
+}
+
+// tfReadDataApply reads a data source using the Terraform provider and logs any warnings it reports.
+func (p *Provider) tfReadDataApply(ctx context.Context, tok tokens.ModuleMember, ds DataSource,
+	d shim.InstanceDiff) (shim.InstanceState, error) {
+
+	provider, err := p.tfFor("")
+	if err != nil {
+		return nil, err
+	}
+	tf, ok := provider.(shim.ProviderWithWarnings)
+	if !ok {
+		return provider.ReadDataApply(ds.TFName, d)
+	}
+
+	state, warnings, err := tf.ReadDataApplyWithWarnings(ds.TFName, d)
+	tokenType := tokens.Type(tok)
+	if logErr := p.logWarnings(ctx, "", string(tok), tokenType, dataSourceResource(ds), warnings); logErr != nil {
+		return nil, logErr
+	}
+	return state, err
+}
@@ -0,0 +1,64 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func TestFormatWarning(t *testing.T) {
+	res := Resource{
+		TF:     shimv2.NewResource(testTFProviderV2.ResourcesMap["example_resource"]),
+		TFName: "example_resource",
+		Schema: &ResourceInfo{Tok: "ExampleResource"},
+	}
+
+	cases := []struct {
+		warning  diagnostics.Warning
+		expected string
+	}{
+		{
+			warning:  diagnostics.Warning{Summary: "deprecated"},
+			expected: "deprecated",
+		},
+		{
+			warning:  diagnostics.Warning{Summary: "deprecated", Detail: "use something else"},
+			expected: "deprecated: use something else",
+		},
+		{
+			warning: diagnostics.Warning{
+				AttributePath: cty.GetAttrPath("string_property_value"),
+				Summary:       "deprecated",
+			},
+			expected: "deprecated. Examine values at 'ExampleResource.StringPropertyValue'.",
+		},
+		{
+			warning: diagnostics.Warning{
+				AttributePath: cty.GetAttrPath("nested_resources").IndexInt(0).GetAttr("kind"),
+				Summary:       "deprecated",
+			},
+			expected: "deprecated. Examine values at 'ExampleResource.NestedResources.Kind'.",
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, formatWarning("pkg:index:ExampleResource", res, &c.warning))
+	}
+}
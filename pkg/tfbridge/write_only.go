@@ -0,0 +1,288 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// writeOnlyVisitor is called by visitWriteOnlyProperties for each write-only property with the property's path, the
+// property map that contains it, and its key in that map.
+type writeOnlyVisitor func(path string, m resource.PropertyMap, key resource.PropertyKey)
+
+// visitWriteOnlyProperties calls the visitor for each write-only property (see SchemaInfo.WriteOnly) in the given
+// property map, including those in nested objects. Paths use the same syntax as ignoreChanges.
+func visitWriteOnlyProperties(m resource.PropertyMap, tfs shim.SchemaMap, ps map[string]*SchemaInfo,
+	visitor writeOnlyVisitor) {
+
+	visitWriteOnlyFields("", m, tfs, ps, false, visitor)
+}
+
+func visitWriteOnlyFields(path string, m resource.PropertyMap, tfs shim.SchemaMap, ps map[string]*SchemaInfo,
+	rawNames bool, visitor writeOnlyVisitor) {
+
+	for k, v := range m {
+		var elementPath string
+		switch {
+		case path == "":
+			elementPath = string(k)
+		case strings.ContainsAny(string(k), `."[]`):
+			elementPath = fmt.Sprintf(`%s.["%s"]`, path, strings.ReplaceAll(string(k), `"`, `\"`))
+		default:
+			elementPath = fmt.Sprintf("%s.%s", path, k)
+		}
+
+		_, etfs, eps := getInfoFromPulumiName(k, tfs, ps, rawNames)
+		if eps != nil && eps.WriteOnly {
+			visitor(elementPath, m, k)
+			continue
+		}
+		visitWriteOnlyValue(elementPath, v, etfs, eps, rawNames, visitor)
+	}
+}
+
+func visitWriteOnlyValue(path string, v resource.PropertyValue, tfs shim.Schema, ps *SchemaInfo, rawNames bool,
+	visitor writeOnlyVisitor) {
+
+	switch {
+	case v.IsSecret():
+		visitWriteOnlyValue(path, v.SecretValue().Element, tfs, ps, rawNames, visitor)
+	case v.IsArray():
+		etfs, eps := elemSchemas(tfs, ps)
+		for i, e := range v.ArrayValue() {
+			visitWriteOnlyValue(fmt.Sprintf("%s[%d]", path, i), e, etfs, eps, rawNames, visitor)
+		}
+	case v.IsObject():
+		// Values with MaxItems==1 are projected as their single element.
+		if IsMaxItemsOne(tfs, ps) {
+			tfs, ps = elemSchemas(tfs, ps)
+		}
+
+		var tfflds shim.SchemaMap
+		if tfs != nil {
+			if res, isres := tfs.Elem().(shim.Resource); isres {
+				tfflds = res.Schema()
+			}
+		}
+		var psflds map[string]*SchemaInfo
+		if ps != nil {
+			psflds = ps.Fields
+		}
+		visitWriteOnlyFields(path, v.ObjectValue(), tfflds, psflds, rawNames || useRawNames(tfs), visitor)
+	}
+}
+
+// writeOnlyPaths returns the paths of the write-only properties in the given property map.
+func writeOnlyPaths(m resource.PropertyMap, tfs shim.SchemaMap, ps map[string]*SchemaInfo) []string {
+	var paths []string
+	visitWriteOnlyProperties(m, tfs, ps, func(path string, _ resource.PropertyMap, _ resource.PropertyKey) {
+		paths = append(paths, path)
+	})
+	return paths
+}
+
+// removeWriteOnlyProperties removes the write-only properties from the given property map.
+func removeWriteOnlyProperties(m resource.PropertyMap, tfs shim.SchemaMap, ps map[string]*SchemaInfo) {
+	visitWriteOnlyProperties(m, tfs, ps, func(_ string, m resource.PropertyMap, key resource.PropertyKey) {
+		delete(m, key)
+	})
+}
+
+// copyWriteOnlyProperties copies the write-only properties in src to the same paths in dst as secrets. Properties
+// whose parent does not exist in dst are not copied.
+func copyWriteOnlyProperties(dst, src resource.PropertyMap, tfs shim.SchemaMap, ps map[string]*SchemaInfo) {
+	for _, path := range writeOnlyPaths(src, tfs, ps) {
+		pp, err := resource.ParsePropertyPath(path)
+		contract.AssertNoErrorf(err, "parsing write-only property path %q", path)
+		if v, ok := pp.Get(resource.NewObjectProperty(src)); ok {
+			pp.Set(resource.NewObjectProperty(dst), resource.MakeSecret(revealPropertyValue(v)))
+		}
+	}
+}
+
+// writeOnlySaltSize is the size in bytes of the random salt that keys the hash of a write-only value.
+const writeOnlySaltSize = 16
+
+// newWriteOnlySalt returns a new random salt for the hash of a write-only value.
+func newWriteOnlySalt() []byte {
+	salt := make([]byte, writeOnlySaltSize)
+	_, err := rand.Read(salt)
+	contract.AssertNoErrorf(err, "generating write-only property salt")
+	return salt
+}
+
+// writeOnlyHash returns the hash that is stored in place of the value of a write-only property. The hash is an
+// HMAC-SHA256 of the value keyed by the given salt, and is stored as "<salt>:<hmac>" so that a value can be checked
+// against it later. Because every value gets a salt of its own, the hash cannot be matched against a table of
+// precomputed hashes of common values.
+func writeOnlyHash(v resource.PropertyValue, salt []byte) string {
+	bytes, err := json.Marshal(revealPropertyValue(v).Mappable())
+	contract.AssertNoErrorf(err, "marshaling write-only property value")
+	mac := hmac.New(sha256.New, salt)
+	_, err = mac.Write(bytes)
+	contract.AssertNoErrorf(err, "hashing write-only property value")
+	return hex.EncodeToString(salt) + ":" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// matchesWriteOnlyHash returns true if the given stored hash is the hash of the given value.
+func matchesWriteOnlyHash(v, hash resource.PropertyValue) bool {
+	hash = revealPropertyValue(hash)
+	if !hash.IsString() {
+		return false
+	}
+	salt, _, ok := strings.Cut(hash.StringValue(), ":")
+	if !ok {
+		return false
+	}
+	saltBytes, err := hex.DecodeString(salt)
+	if err != nil || len(saltBytes) != writeOnlySaltSize {
+		return false
+	}
+	return hmac.Equal([]byte(writeOnlyHash(v, saltBytes)), []byte(hash.StringValue()))
+}
+
+// writeOnlyValues holds the values of the write-only properties of the resources that this provider has checked.
+// Check replaces these values with their hashes in the inputs it returns so that the values are never stored, so this
+// is the only place they are kept. Create and Update, which the engine always calls after Check in the same
+// deployment, retrieve them from here.
+type writeOnlyValues struct {
+	m      sync.Mutex
+	values map[resource.URN]map[string]resource.PropertyValue
+}
+
+// hash records the write-only values in the given inputs of the given resource and replaces them with their hashes,
+// which are marked secret. If the old inputs hold the hash of an unchanged value, that hash is kept so that the value
+// does not appear to change. Unknown values are left as they are.
+func (w *writeOnlyValues) hash(urn resource.URN, inputs, olds resource.PropertyMap, tfs shim.SchemaMap,
+	ps map[string]*SchemaInfo) {
+
+	values := map[string]resource.PropertyValue{}
+	visitWriteOnlyProperties(inputs, tfs, ps, func(path string, m resource.PropertyMap, key resource.PropertyKey) {
+		v := m[key]
+		if v.IsNull() || v.ContainsUnknowns() {
+			return
+		}
+
+		// Create and Update unmarshal their inputs without secrets, so the values are recorded the same way.
+		values[path] = revealPropertyValue(v)
+
+		pp, err := resource.ParsePropertyPath(path)
+		contract.AssertNoErrorf(err, "parsing write-only property path %q", path)
+		if old, ok := pp.Get(resource.NewObjectProperty(olds)); ok && matchesWriteOnlyHash(v, old) {
+			m[key] = resource.MakeSecret(revealPropertyValue(old))
+		} else {
+			m[key] = resource.MakeSecret(resource.NewStringProperty(writeOnlyHash(v, newWriteOnlySalt())))
+		}
+	})
+
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.values == nil {
+		w.values = map[resource.URN]map[string]resource.PropertyValue{}
+	}
+	w.values[urn] = values
+}
+
+// restore returns a copy of the given properties of the given resource in which the hashes of write-only values have
+// been replaced with the values that were recorded when the resource was checked. It also returns the paths of the
+// hashes for which no value was recorded.
+func (w *writeOnlyValues) restore(urn resource.URN, props resource.PropertyMap, tfs shim.SchemaMap,
+	ps map[string]*SchemaInfo) (resource.PropertyMap, []string) {
+
+	w.m.Lock()
+	values := w.values[urn]
+	w.m.Unlock()
+
+	props = copyPropertyValue(resource.NewObjectProperty(props)).ObjectValue()
+
+	var missing []string
+	visitWriteOnlyProperties(props, tfs, ps, func(path string, m resource.PropertyMap, key resource.PropertyKey) {
+		hash := m[key]
+		if !revealPropertyValue(hash).IsString() {
+			return
+		}
+		if v, ok := values[path]; ok && matchesWriteOnlyHash(v, hash) {
+			m[key] = v
+			return
+		}
+		missing = append(missing, path)
+	})
+	return props, missing
+}
+
+// withoutWriteOnlyProperties returns a copy of the given property map without its write-only properties.
+func withoutWriteOnlyProperties(m resource.PropertyMap, tfs shim.SchemaMap,
+	ps map[string]*SchemaInfo) resource.PropertyMap {
+
+	c := copyPropertyValue(resource.NewObjectProperty(m)).ObjectValue()
+	removeWriteOnlyProperties(c, tfs, ps)
+	return c
+}
+
+// copyPropertyValue returns a deep copy of the given property value's arrays, objects, and secrets.
+func copyPropertyValue(v resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case v.IsArray():
+		arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, e := range v.ArrayValue() {
+			arr[i] = copyPropertyValue(e)
+		}
+		return resource.NewArrayProperty(arr)
+	case v.IsObject():
+		obj := make(resource.PropertyMap, len(v.ObjectValue()))
+		for k, e := range v.ObjectValue() {
+			obj[k] = copyPropertyValue(e)
+		}
+		return resource.NewObjectProperty(obj)
+	case v.IsSecret():
+		return resource.MakeSecret(copyPropertyValue(v.SecretValue().Element))
+	default:
+		return v
+	}
+}
+
+// revealPropertyValue returns a copy of the given property value in which every secret is replaced by its element.
+func revealPropertyValue(v resource.PropertyValue) resource.PropertyValue {
+	switch {
+	case v.IsArray():
+		arr := make([]resource.PropertyValue, len(v.ArrayValue()))
+		for i, e := range v.ArrayValue() {
+			arr[i] = revealPropertyValue(e)
+		}
+		return resource.NewArrayProperty(arr)
+	case v.IsObject():
+		obj := make(resource.PropertyMap, len(v.ObjectValue()))
+		for k, e := range v.ObjectValue() {
+			obj[k] = revealPropertyValue(e)
+		}
+		return resource.NewObjectProperty(obj)
+	case v.IsSecret():
+		return revealPropertyValue(v.SecretValue().Element)
+	default:
+		return v
+	}
+}
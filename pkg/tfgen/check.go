@@ -0,0 +1,146 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/pulumi/pulumi/pkg/v3/codegen"
+	"github.com/spf13/afero"
+)
+
+// maxCheckDiffLines is the maximum number of diff lines reported for each file that differs in check mode.
+const maxCheckDiffLines = 50
+
+// checkFiles compares the generated files for the named package with the files present in the generator's root, and
+// returns an error that summarizes the differences, if any. Files that generating the SDK would remove are reported
+// as stale. Like Generate, it ignores the root-level README.md if one already exists.
+func (g *Generator) checkFiles(pkgName string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var summary strings.Builder
+	differences := 0
+	for _, name := range names {
+		if name == "README.md" {
+			if _, err := g.root.Stat(name); err == nil {
+				continue
+			}
+		}
+
+		existing, err := afero.ReadFile(g.root, name)
+		switch {
+		case os.IsNotExist(err):
+			differences++
+			fmt.Fprintf(&summary, "\n%s: missing", name)
+			continue
+		case err != nil:
+			return fmt.Errorf("reading %v: %w", name, err)
+		case bytes.Equal(existing, files[name]):
+			continue
+		}
+
+		differences++
+		fmt.Fprintf(&summary, "\n%s: differs\n%s", name, fileDiff(name, existing, files[name]))
+	}
+
+	if dir, exclusions, ok := g.language.sdkDir(pkgName); ok {
+		stale, err := staleFiles(g.root, dir, exclusions, files)
+		if err != nil {
+			return err
+		}
+		for _, name := range stale {
+			if name == "Pulumi.yaml" {
+				// The project file is written by emitProjectMetadata rather than by the language generator.
+				continue
+			}
+			differences++
+			fmt.Fprintf(&summary, "\n%s: stale", name)
+		}
+	}
+
+	if differences == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d generated file(s) are out of date:%s", differences, summary.String())
+}
+
+// staleFiles returns the sorted names of the files in the given directory of the root that are not among the
+// generated files. The directory's entries named in exclusions are skipped.
+func staleFiles(root afero.Fs, dir string, exclusions codegen.StringSet, files map[string][]byte) ([]string, error) {
+	var stale []string
+	var walk func(dir string, exclusions codegen.StringSet) error
+	walk = func(dir string, exclusions codegen.StringSet) error {
+		entries, err := afero.ReadDir(root, dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("reading %v: %w", dir, err)
+		}
+		for _, entry := range entries {
+			name := path.Join(dir, entry.Name())
+			switch {
+			case exclusions.Has(entry.Name()):
+				continue
+			case entry.IsDir():
+				if err := walk(name, nil); err != nil {
+					return err
+				}
+			default:
+				if _, ok := files[name]; !ok {
+					stale = append(stale, name)
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(dir, exclusions); err != nil {
+		return nil, err
+	}
+	sort.Strings(stale)
+	return stale, nil
+}
+
+// fileDiff returns a unified diff between the old and new contents of the named file, truncated to
+// maxCheckDiffLines lines.
+func fileDiff(name string, old, new []byte) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(old)),
+		B:        difflib.SplitLines(string(new)),
+		FromFile: name + " (on disk)",
+		ToFile:   name + " (generated)",
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Sprintf("    (failed to compute diff: %v)\n", err)
+	}
+
+	lines := strings.SplitAfter(diff, "\n")
+	if len(lines) > maxCheckDiffLines {
+		omitted := len(lines) - maxCheckDiffLines
+		lines = append(lines[:maxCheckDiffLines], fmt.Sprintf("... (%d more lines)\n", omitted))
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,108 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"io"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func TestCheckSchema(t *testing.T) {
+	root := afero.NewMemMapFs()
+	generate := func(check bool) error {
+		return generateTestProvider(t, root, Schema, check)
+	}
+
+	// Checking before anything has been generated reports the schema as missing.
+	err := generate(true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema.json: missing")
+
+	// Once generated, the check passes, which also requires the output to be deterministic.
+	require.NoError(t, generate(false))
+	require.NoError(t, generate(true))
+
+	// Any change to the schema on disk is reported with a diff.
+	require.NoError(t, afero.WriteFile(root, "schema.json", []byte("{}\n"), 0600))
+	err = generate(true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema.json: differs")
+	assert.Contains(t, err.Error(), "+++ schema.json (generated)")
+
+	// Files that are not generated are left alone.
+	require.NoError(t, generate(false))
+	require.NoError(t, afero.WriteFile(root, "main.go", []byte("package main\n"), 0600))
+	require.NoError(t, generate(true))
+}
+
+func TestCheckStaleFiles(t *testing.T) {
+	root := afero.NewMemMapFs()
+	require.NoError(t, generateTestProvider(t, root, NodeJS, false))
+
+	// Files that generating the SDK would remove are reported as stale, except for those in the tests directory.
+	require.NoError(t, afero.WriteFile(root, "types/old.ts", []byte("export {};\n"), 0600))
+	require.NoError(t, afero.WriteFile(root, "tests/index.ts", []byte("export {};\n"), 0600))
+	err := generateTestProvider(t, root, NodeJS, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 generated file(s) are out of date:\ntypes/old.ts: stale")
+
+	// Generating the SDK removes them.
+	require.NoError(t, generateTestProvider(t, root, NodeJS, false))
+	require.NoError(t, generateTestProvider(t, root, NodeJS, true))
+	exists, err := afero.Exists(root, "tests/index.ts")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+// generateTestProvider generates the given language's output for a small provider into the root.
+func generateTestProvider(t *testing.T, root afero.Fs, language Language, check bool) error {
+	g, err := NewGenerator(GeneratorOptions{
+		Package:  "test",
+		Version:  "0.0.1",
+		Language: language,
+		ProviderInfo: tfbridge.ProviderInfo{
+			Name: "test",
+			P: (&schema.Provider{
+				Schema: schema.SchemaMap{
+					"region": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+				},
+				ResourcesMap:   schema.ResourceMap{},
+				DataSourcesMap: schema.ResourceMap{},
+			}).Shim(),
+			ExtraConfig: map[string]*tfbridge.ConfigInfo{
+				"b": {Schema: (&schema.Schema{Type: shim.TypeString, Required: true}).Shim()},
+				"a": {Schema: (&schema.Schema{Type: shim.TypeString, Required: true}).Shim()},
+				"c": {Schema: (&schema.Schema{Type: shim.TypeString, Required: true}).Shim()},
+			},
+		},
+		Root:         root,
+		Sink:         diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never}),
+		SkipDocs:     true,
+		SkipExamples: true,
+		Check:        check,
+	})
+	require.NoError(t, err)
+	return g.Generate()
+}
@@ -419,20 +419,34 @@ func splitGroupLines(s, sep string) [][]string {
 }
 
 // parseTFMarkdown takes a TF website markdown doc and extracts a structured representation for use in
-// generating doc comments
+// generating doc comments. The markdown is parsed by the generator's docs parser.
 func parseTFMarkdown(g *Generator, info tfbridge.ResourceOrDataSourceInfo, kind DocKind,
 	markdown, markdownFileName, resourcePrefix, rawname string) (entityDocs, error) {
 
-	p := &tfMarkdownParser{
-		g:                g,
+	return parseDocs(g, g.docsParser, docsSource{
 		info:             info,
 		kind:             kind,
 		markdown:         markdown,
 		markdownFileName: markdownFileName,
 		resourcePrefix:   resourcePrefix,
 		rawname:          rawname,
+	})
+}
+
+func newTFMarkdownParser(g *Generator, src docsSource, headings map[string]int,
+	schemaArguments bool) *tfMarkdownParser {
+
+	return &tfMarkdownParser{
+		g:                g,
+		info:             src.info,
+		kind:             src.kind,
+		markdown:         src.markdown,
+		markdownFileName: src.markdownFileName,
+		resourcePrefix:   src.resourcePrefix,
+		rawname:          src.rawname,
+		headings:         headings,
+		schemaArguments:  schemaArguments,
 	}
-	return p.parse()
 }
 
 type tfMarkdownParser struct {
@@ -444,6 +458,12 @@ type tfMarkdownParser struct {
 	resourcePrefix   string
 	rawname          string
 
+	// headings maps additional H2 headings to the kind of section they introduce.
+	headings map[string]int
+	// schemaArguments is true if the required and optional properties in a "Schema" section should be recorded as
+	// arguments rather than attributes.
+	schemaArguments bool
+
 	ret entityDocs
 }
 
@@ -609,25 +629,29 @@ func (p *tfMarkdownParser) parseSection(h2Section []string) error {
 	}
 
 	sectionKind := sectionOther
-
-	switch header {
-	case "Timeout", "Timeouts", "User Project Override", "User Project Overrides":
-		p.g.debug("Ignoring doc section [%v] for [%v]", header, p.rawname)
-		ignoredDocHeaders[header]++
-		return nil
-	case "Example Usage":
-		sectionKind = sectionExampleUsage
-	case "Arguments Reference", "Argument Reference", "Argument reference", "Nested Blocks", "Nested blocks":
-		sectionKind = sectionArgsReference
-	case "Attributes Reference", "Attribute Reference", "Attribute reference":
-		sectionKind = sectionAttributesReference
-	case "Import", "Imports":
-		sectionKind = sectionImports
-	case "---":
-		sectionKind = sectionFrontMatter
-	case "Schema":
-		p.parseSchemaWithNestedSections(h2Section)
-		return nil
+	if kind, ok := p.headings[header]; ok {
+		// The docs parser has been configured to recognize this heading.
+		sectionKind = kind
+	} else {
+		switch header {
+		case "Timeout", "Timeouts", "User Project Override", "User Project Overrides":
+			p.g.debug("Ignoring doc section [%v] for [%v]", header, p.rawname)
+			ignoredDocHeaders[header]++
+			return nil
+		case "Example Usage":
+			sectionKind = sectionExampleUsage
+		case "Arguments Reference", "Argument Reference", "Argument reference", "Nested Blocks", "Nested blocks":
+			sectionKind = sectionArgsReference
+		case "Attributes Reference", "Attribute Reference", "Attribute reference":
+			sectionKind = sectionAttributesReference
+		case "Import", "Imports":
+			sectionKind = sectionImports
+		case "---":
+			sectionKind = sectionFrontMatter
+		case "Schema":
+			p.parseSchemaWithNestedSections(h2Section)
+			return nil
+		}
 	}
 
 	// Now split the sections by H3 topics. This is done because we'll ignore sub-sections with code
@@ -710,6 +734,10 @@ func (p *tfMarkdownParser) parseSchemaWithNestedSections(subsection []string) {
 		p.g.warn("Failed to parse top-level Schema section")
 		return
 	}
+	if p.schemaArguments {
+		parseTopLevelSchemaIntoArgumentDocs(&p.ret, topLevelSchema, p.g.warn)
+		return
+	}
 	parseTopLevelSchemaIntoDocs(&p.ret, topLevelSchema, p.g.warn)
 }
 
@@ -1163,18 +1191,28 @@ func (g *Generator) convert(input afero.Fs, languageName string) (files map[stri
 	return
 }
 
-// convertHCLToString hides the implementation details of the upstream implementation for HCL conversion and provides
-// simplified parameters and return values
-func (g *Generator) convertHCLToString(hcl, path, languageName string) (string, error) {
+// exampleFileName returns the name of the temporary file used to hold the HCL for an example at the given path.
+func exampleFileName(path string) string {
+	return fmt.Sprintf("/%s.tf", strings.ReplaceAll(path, "/", "-"))
+}
+
+// exampleInput returns an in-memory filesystem that holds the given HCL example, suitable for passing to convert.
+func exampleInput(hcl, path string) afero.Fs {
 	input := afero.NewMemMapFs()
-	fileName := fmt.Sprintf("/%s.tf", strings.ReplaceAll(path, "/", "-"))
-	f, err := input.Create(fileName)
+	f, err := input.Create(exampleFileName(path))
 	contract.AssertNoError(err)
 	_, err = f.Write([]byte(hcl))
 	contract.AssertNoError(err)
 	contract.IgnoreClose(f)
+	return input
+}
 
-	files, diags, err := g.convert(input, languageName)
+// convertHCLToString hides the implementation details of the upstream implementation for HCL conversion and provides
+// simplified parameters and return values. If exampleDir is not empty, the converted program is also written to that
+// directory under the examples root.
+func (g *Generator) convertHCLToString(hcl, path, exampleDir, languageName string) (string, error) {
+	fileName := exampleFileName(path)
+	files, diags, err := g.convert(exampleInput(hcl, path), languageName)
 
 	// By observation on the GCP provider, convert.Convert() will either panic (in which case the wrapped method above
 	// will return an error) or it will return a non-zero value for diags.
@@ -1197,6 +1235,12 @@ func (g *Generator) convertHCLToString(hcl, path, languageName string) (string,
 
 	contract.Assert(len(files) == 1)
 
+	if g.examplesRoot != nil {
+		if err := g.writeExampleProgram(filepath.Join(exampleDir, languageName), files); err != nil {
+			return "", fmt.Errorf("failed to write example program for %s to %v: %w", path, languageName, err)
+		}
+	}
+
 	convertedHcl := ""
 	for _, output := range files {
 		convertedHcl = strings.TrimSpace(string(output))
@@ -1296,6 +1340,24 @@ func (g *Generator) convertHCL(hcl, path, exampleTitle string, languages []strin
 		hcl = fixed
 	}
 
+	// Examples that are written out as standalone programs each get a directory of their own.
+	exampleDir := ""
+	if g.examplesRoot != nil || g.language == YAML {
+		exampleDir = g.exampleProgramDir(path)
+	}
+
+	// If requested, check the example against the generated schema before converting it to any other language. Examples
+	// that do not bind are dropped entirely.
+	if g.examplesRoot != nil {
+		if err := g.checkExample(hcl, path, exampleDir); err != nil {
+			name := exampleName(path, exampleTitle)
+			g.failedExamples = append(g.failedExamples, name)
+			g.warn("HCL example %s failed to bind against the generated schema: %v. The example will be dropped "+
+				"from any generated docs or SDKs.", name, err)
+			return "", err
+		}
+	}
+
 	hclConversions := map[string]string{}
 	var result strings.Builder
 	var err error
@@ -1304,7 +1366,7 @@ func (g *Generator) convertHCL(hcl, path, exampleTitle string, languages []strin
 
 	for _, lang := range languages {
 		var convertErr error
-		hclConversions[lang], convertErr = g.convertHCLToString(hcl, path, lang)
+		hclConversions[lang], convertErr = g.convertHCLToString(hcl, path, exampleDir, lang)
 		if convertErr != nil {
 			failedLangs[lang] = convertErr
 			err = multierror.Append(err, convertErr)
@@ -1313,6 +1375,18 @@ func (g *Generator) convertHCL(hcl, path, exampleTitle string, languages []strin
 
 	result.WriteString(hclConversionsToString(hclConversions))
 
+	// If the target language emits the examples themselves, hold on to the converted code. PCL examples are kept by
+	// schema path, so the last example for each path wins; YAML examples each get a project directory of their own.
+	if g.language.emitsExamples() {
+		if code := hclConversions[languages[0]]; code != "" {
+			key := exampleDir
+			if g.language == PCL {
+				key = path
+			}
+			g.convertedCode[key] = []byte(code + "\n")
+		}
+	}
+
 	if len(failedLangs) == len(languages) {
 		hclAllLangsConversionFailures++
 
@@ -1343,6 +1417,8 @@ func (g *Generator) convertHCL(hcl, path, exampleTitle string, languages []strin
 				hclCSharpPartialConversionFailures++
 			case convert.LanguageGo:
 				hclGoPartialConversionFailures++
+			case convert.LanguageYaml:
+				hclYAMLPartialConversionFailures++
 			}
 		}
 
@@ -1377,6 +1453,8 @@ func genLanguageToSlice(input Language) []string {
 		return []string{convert.LanguageGo}
 	case PCL:
 		return []string{convert.LanguagePulumi}
+	case YAML:
+		return []string{convert.LanguageYaml}
 	case Schema:
 		return []string{
 			convert.LanguageTypescript,
@@ -0,0 +1,82 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// DocsParser selects the parser for the upstream markdown docs of a resource or data source, which parses the docs
+// into the structured form used when generating doc comments. A parser may be set on either tfbridge.ProviderInfo or
+// GeneratorOptions; the latter takes precedence. If neither is set, TFRegistryDocsParser is used.
+//
+// The available parsers are TFRegistryDocsParser, for docs that follow the TF registry conventions, and
+// TFPluginDocsParser, for docs generated by tfplugindocs.
+type DocsParser = tfbridge.DocsParser
+
+// TFRegistryDocsParser parses docs that follow the TF registry conventions. See tfbridge.TFRegistryDocsParser.
+type TFRegistryDocsParser = tfbridge.TFRegistryDocsParser
+
+// TFPluginDocsParser parses docs generated by tfplugindocs. See tfbridge.TFPluginDocsParser.
+type TFPluginDocsParser = tfbridge.TFPluginDocsParser
+
+// docsSource describes the markdown docs of a single resource or data source.
+type docsSource struct {
+	info             tfbridge.ResourceOrDataSourceInfo
+	kind             DocKind
+	markdown         string
+	markdownFileName string
+	resourcePrefix   string
+	rawname          string
+}
+
+// parseDocs parses the markdown docs of a resource or data source with the given parser. A nil parser parses the docs
+// as TF registry docs.
+func parseDocs(g *Generator, parser DocsParser, src docsSource) (entityDocs, error) {
+	var registry TFRegistryDocsParser
+	switch p := parser.(type) {
+	case TFPluginDocsParser, *TFPluginDocsParser:
+		return newTFMarkdownParser(g, src, nil, true).parse()
+	case TFRegistryDocsParser:
+		registry = p
+	case *TFRegistryDocsParser:
+		if p != nil {
+			registry = *p
+		}
+	}
+
+	headings := map[string]int{}
+	for _, h := range registry.ArgumentHeadings {
+		headings[h] = sectionArgsReference
+	}
+	for _, h := range registry.AttributeHeadings {
+		headings[h] = sectionAttributesReference
+	}
+	for _, h := range registry.ImportHeadings {
+		headings[h] = sectionImports
+	}
+	return newTFMarkdownParser(g, src, headings, false).parse()
+}
+
+// getDocsParser returns the docs parser selected by the given options.
+func getDocsParser(opts GeneratorOptions) DocsParser {
+	if opts.DocsParser != nil {
+		return opts.DocsParser
+	}
+	if opts.ProviderInfo.DocsParser != nil {
+		return opts.ProviderInfo.DocsParser
+	}
+	return TFRegistryDocsParser{}
+}
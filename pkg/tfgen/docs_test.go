@@ -17,12 +17,17 @@ package tfgen
 
 import (
 	"bytes"
+	"io"
 	"strings"
 	"testing"
 	"text/template"
 
-	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 )
 
 type testcase struct {
@@ -741,3 +746,66 @@ func TestOverlayArgsToArgs(t *testing.T) {
 
 	assert.Equal(t, expected, dest)
 }
+
+func TestDocsParsers(t *testing.T) {
+	g := &Generator{
+		pkg:      "test",
+		language: NodeJS,
+		sink:     diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never}),
+	}
+
+	t.Run("registry with custom headings", func(t *testing.T) {
+		markdown := "Provides a widget.\n\n" +
+			"## Widget Settings\n\n" +
+			"* `name` - (Required) The name of the widget.\n\n" +
+			"## Exported Values\n\n" +
+			"* `id` - The ID of the widget.\n"
+
+		doc, err := parseDocs(g, TFRegistryDocsParser{}, docsSource{markdown: markdown, rawname: "test_widget"})
+		require.NoError(t, err)
+		assert.Empty(t, doc.Arguments)
+		assert.Empty(t, doc.Attributes)
+
+		parser := TFRegistryDocsParser{
+			ArgumentHeadings:  []string{"Widget Settings"},
+			AttributeHeadings: []string{"Exported Values"},
+		}
+		doc, err = parseDocs(g, &parser, docsSource{markdown: markdown, rawname: "test_widget"})
+		require.NoError(t, err)
+		require.Contains(t, doc.Arguments, "name")
+		assert.Equal(t, "The name of the widget.", doc.Arguments["name"].description)
+		assert.Equal(t, map[string]string{"id": "The ID of the widget."}, doc.Attributes)
+	})
+
+	t.Run("tfplugindocs", func(t *testing.T) {
+		markdown := readTestFile(t, "mini.md")
+
+		doc, err := parseDocs(g, TFPluginDocsParser{}, docsSource{markdown: markdown, rawname: "test_dashboard"})
+		require.NoError(t, err)
+		require.Contains(t, doc.Arguments, "layout_type")
+		assert.Equal(t, "The layout type of the dashboard, either 'free' or 'ordered'.",
+			doc.Arguments["layout_type"].description)
+		assert.NotContains(t, doc.Attributes, "layout_type")
+		require.Contains(t, doc.Arguments, "widget.group_definition")
+		assert.Equal(t, "The layout type of the group, only 'ordered' for now.",
+			doc.Arguments["widget.group_definition"].arguments["layout_type"])
+
+		doc, err = parseDocs(g, nil, docsSource{markdown: markdown, rawname: "test_dashboard"})
+		require.NoError(t, err)
+		assert.NotContains(t, doc.Arguments, "layout_type")
+		assert.Contains(t, doc.Attributes, "layout_type")
+	})
+}
+
+func TestGetDocsParser(t *testing.T) {
+	assert.Equal(t, TFRegistryDocsParser{}, getDocsParser(GeneratorOptions{}))
+
+	assert.Equal(t, TFPluginDocsParser{}, getDocsParser(GeneratorOptions{
+		ProviderInfo: tfbridge.ProviderInfo{DocsParser: TFPluginDocsParser{}},
+	}))
+
+	assert.Equal(t, TFRegistryDocsParser{ImportHeadings: []string{"Importing"}}, getDocsParser(GeneratorOptions{
+		ProviderInfo: tfbridge.ProviderInfo{DocsParser: TFPluginDocsParser{}},
+		DocsParser:   TFRegistryDocsParser{ImportHeadings: []string{"Importing"}},
+	}))
+}
@@ -0,0 +1,170 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/pulumi/pkg/v3/codegen"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/convert"
+)
+
+// exampleProgramDir returns the directory under the examples root that holds the standalone programs for the next
+// example found at the given schema path. Examples are numbered in the order in which they appear in their docs.
+func (g *Generator) exampleProgramDir(schemaPath string) string {
+	index := g.exampleCounts[schemaPath]
+	g.exampleCounts[schemaPath] = index + 1
+	return path.Join(strings.TrimPrefix(schemaPath, "#/"), strconv.Itoa(index))
+}
+
+// exampleName returns a human-readable name for the example found at the given schema path.
+func exampleName(schemaPath, exampleTitle string) string {
+	if exampleTitle == "" {
+		return schemaPath
+	}
+	return fmt.Sprintf("%s (%s)", schemaPath, exampleTitle)
+}
+
+// writeExampleProgram writes the files that make up a converted example into the given directory under the examples
+// root.
+func (g *Generator) writeExampleProgram(dir string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := emitFile(g.examplesRoot, path.Join(dir, path.Base(name)), files[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkExample converts the given HCL example to PCL and binds the result against the schema of the package being
+// generated. Unlike the conversions used to render the docs, the check does not skip resource typechecking, so any
+// example whose inputs do not match the generated schema fails to bind. Binding is performed using the in-memory
+// schema, and does not require any network access for the package being generated. The PCL program is written to the
+// example's directory under the examples root.
+func (g *Generator) checkExample(hcl, schemaPath, dir string) error {
+	files, diags, err := g.convert(exampleInput(hcl, schemaPath), convert.LanguagePulumi)
+	if err != nil {
+		return err
+	}
+	if diags.All.HasErrors() {
+		return diags.All
+	}
+
+	// The converter names its PCL output after the input files, and may return empty files for inputs that were not
+	// part of the example. Normalize the names and drop the empty files.
+	programFiles := map[string][]byte{}
+	for name, contents := range files {
+		if len(bytes.TrimSpace(contents)) == 0 {
+			continue
+		}
+		name = strings.TrimSuffix(strings.TrimSuffix(path.Base(name), ".pp"), ".tf") + ".pp"
+		programFiles[name] = contents
+	}
+
+	parser := syntax.NewParser()
+	for name, contents := range programFiles {
+		if err = parser.ParseFile(bytes.NewReader(contents), name); err != nil {
+			return err
+		}
+	}
+	if parser.Diagnostics.HasErrors() {
+		return parser.Diagnostics
+	}
+
+	program, bindDiags, err := pcl.BindProgram(parser.Files,
+		pcl.Loader(newLoader(g.pluginHost)),
+		pcl.Cache(g.packageCache),
+		pcl.PluginHost(g.pluginHost),
+		pcl.AllowMissingVariables)
+	if err != nil {
+		return err
+	}
+
+	if err = g.writeExampleProgram(path.Join(dir, convert.LanguagePulumi), programFiles); err != nil {
+		return err
+	}
+
+	if bindDiags.HasErrors() {
+		return bindDiags
+	}
+	if typecheckDiags := typecheckResources(program); typecheckDiags.HasErrors() {
+		return typecheckDiags
+	}
+	return nil
+}
+
+// typecheckResources checks the inputs of each resource in the given program against the resource's schema. The PCL
+// binder unconditionally skips this check for the sake of tf2pulumi, so the examples check performs it separately:
+// each input must be a property of the resource and must be convertible to the property's type, and each required
+// input must be present.
+func typecheckResources(program *pcl.Program) hcl.Diagnostics {
+	var diagnostics hcl.Diagnostics
+	for _, node := range program.Nodes {
+		res, ok := node.(*pcl.Resource)
+		if !ok || res.Schema == nil {
+			continue
+		}
+		inputType, ok := res.InputType.(*model.ObjectType)
+		if !ok {
+			continue
+		}
+
+		inputs := codegen.StringSet{}
+		for _, attr := range res.Inputs {
+			inputs.Add(attr.Name)
+
+			typ, ok := inputType.Properties[attr.Name]
+			if !ok {
+				diagnostics = append(diagnostics, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("unsupported attribute '%v'", attr.Name),
+					Subject:  &attr.Syntax.NameRange,
+				})
+				continue
+			}
+			if !typ.ConversionFrom(attr.Value.Type()).Exists() {
+				diagnostics = append(diagnostics, model.ExprNotConvertible(typ, attr.Value))
+			}
+		}
+
+		for _, prop := range res.Schema.InputProperties {
+			if prop.IsRequired() && !inputs.Has(prop.Name) {
+				missingRange := res.Definition.Syntax.Body.MissingItemRange()
+				diagnostics = append(diagnostics, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("missing required attribute '%v'", prop.Name),
+					Subject:  &missingRange,
+				})
+			}
+		}
+	}
+	return diagnostics
+}
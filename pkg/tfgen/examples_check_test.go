@@ -0,0 +1,146 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func newExamplesTestGenerator(t *testing.T, language Language, examplesRoot afero.Fs) *Generator {
+	info := tfbridge.ProviderInfo{
+		Name:    "test",
+		Version: "0.0.1",
+		P: (&schema.Provider{
+			Schema:         schema.SchemaMap{},
+			DataSourcesMap: schema.ResourceMap{},
+			ResourcesMap: schema.ResourceMap{
+				"test_res": (&schema.Resource{
+					Schema: schema.SchemaMap{
+						"name": (&schema.Schema{Type: shim.TypeString, Required: true}).Shim(),
+					},
+				}).Shim(),
+			},
+		}).Shim(),
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"test_res": {Tok: "test:index/res:Res"},
+		},
+	}
+
+	g, err := NewGenerator(GeneratorOptions{
+		Package:      "test",
+		Version:      "0.0.1",
+		Language:     language,
+		ProviderInfo: info,
+		Root:         afero.NewMemMapFs(),
+		Sink:         diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never}),
+		SkipDocs:     true,
+		ExamplesRoot: examplesRoot,
+	})
+	require.NoError(t, err)
+
+	pack, err := g.gatherPackage()
+	require.NoError(t, err)
+	spec, err := genPulumiSchema(pack, g.pkg, g.version, g.info)
+	require.NoError(t, err)
+	g.providerShim.schema, err = json.Marshal(spec)
+	require.NoError(t, err)
+
+	return g
+}
+
+func TestCheckExamples(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		// TODO[pulumi/pulumi-terraform-bridge#408]
+		t.Skip("Skipped on windows")
+	}
+
+	examplesRoot := afero.NewMemMapFs()
+	g := newExamplesTestGenerator(t, PCL, examplesRoot)
+
+	path := "#/resources/test:index/res:Res"
+	code, err := g.convertHCL(`resource "test_res" "a" { name = "a" }`, path, "Good", []string{"typescript"})
+	require.NoError(t, err)
+	assert.Contains(t, code, `new test.Res("a"`)
+
+	_, err = g.convertHCL(`resource "test_res" "b" { name = ["not", "a", "string"] }`, path, "Bad",
+		[]string{"typescript"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{path + " (Bad)"}, g.failedExamples)
+
+	for _, f := range []string{
+		"resources/test:index/res:Res/0/pulumi/#-resources-test:index-res:Res.pp",
+		"resources/test:index/res:Res/0/typescript/index.ts",
+		"resources/test:index/res:Res/1/pulumi/#-resources-test:index-res:Res.pp",
+	} {
+		exists, err := afero.Exists(examplesRoot, f)
+		require.NoError(t, err)
+		assert.True(t, exists, f)
+	}
+}
+
+func TestYAMLExamples(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		// TODO[pulumi/pulumi-terraform-bridge#408]
+		t.Skip("Skipped on windows")
+	}
+
+	g := newExamplesTestGenerator(t, YAML, nil)
+
+	path := "#/resources/test:index/res:Res"
+	code, err := g.convertHCL(`resource "test_res" "a" { name = "a" }`, path, "", genLanguageToSlice(YAML))
+	require.NoError(t, err)
+	assert.Contains(t, code, "```yaml\n")
+	assert.Contains(t, code, "type: test:Res")
+
+	program, ok := g.convertedCode["resources/test:index/res:Res/0"]
+	require.True(t, ok)
+	assert.Contains(t, string(program), "type: test:Res")
+}
+
+func TestPCLExamples(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		// TODO[pulumi/pulumi-terraform-bridge#408]
+		t.Skip("Skipped on windows")
+	}
+
+	g := newExamplesTestGenerator(t, PCL, nil)
+
+	path := "#/resources/test:index/res:Res"
+	for _, name := range []string{"a", "b"} {
+		hcl := fmt.Sprintf("resource \"test_res\" %q {\n  name = true ? %q : \"\"\n}\n", name, name)
+		_, err := g.convertHCL(hcl, path, "", genLanguageToSlice(PCL))
+		require.NoError(t, err)
+	}
+
+	// PCL examples are kept by schema path, so the last example for a path wins.
+	require.Len(t, g.convertedCode, 1)
+	program, ok := g.convertedCode[path]
+	require.True(t, ok)
+	assert.Contains(t, string(program), `name = true ? "b" : ""`)
+}
@@ -0,0 +1,232 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file implements the stable, versioned coverage report written by `tfgen --coverage`, along with the comparison
+// logic used to detect examples that regressed between two runs.
+
+package tfgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CoverageReportVersion is the version of the coverage report format written by this version of tfgen. Reports with
+// a different version are rejected when read back in.
+const CoverageReportVersion = 1
+
+// CoverageReport is the serialized form of the data collected by a CoverageTracker. Unlike the ad-hoc files written
+// to COVERAGE_OUTPUT_DIR, its layout is stable: examples are sorted by name and results are keyed by language, so that
+// two reports for the same provider can be diffed textually or compared with CompareCoverageReports.
+type CoverageReport struct {
+	Version         int                     `json:"version"`
+	ProviderName    string                  `json:"providerName"`
+	ProviderVersion string                  `json:"providerVersion,omitempty"`
+	Examples        []CoverageReportExample `json:"examples"`
+}
+
+// CoverageReportExample records the conversion results for a single example.
+type CoverageReportExample struct {
+	// The name of the example: the name of its page, suffixed with "#" and a hash of the example's HCL so that the name
+	// does not change when other examples are added to or removed from the page.
+	Name string `json:"name"`
+	// The original HCL source of the example.
+	HCL string `json:"hcl,omitempty"`
+	// The conversion result for each language, keyed by language name.
+	Results map[string]CoverageReportResult `json:"results"`
+}
+
+// CoverageReportResult records the outcome of converting a single example to a single language.
+type CoverageReportResult struct {
+	Outcome     string `json:"outcome"`               // One of "success", "warning", "failure", or "fatal".
+	Diagnostics string `json:"diagnostics,omitempty"` // Any diagnostics reported by the converter.
+}
+
+// CoverageRegression describes an example that converted successfully in a previous report, but not in the current.
+type CoverageRegression struct {
+	Example     string // The name of the example.
+	Language    string // The language the example no longer converts to.
+	Outcome     string // The outcome of the current conversion attempt.
+	Diagnostics string // The diagnostics reported by the current conversion attempt.
+}
+
+func (r CoverageRegression) String() string {
+	if r.Diagnostics == "" {
+		return fmt.Sprintf("%s (%s): %s", r.Example, r.Language, r.Outcome)
+	}
+	return fmt.Sprintf("%s (%s): %s: %s", r.Example, r.Language, r.Outcome, r.Diagnostics)
+}
+
+// outcomeNames maps conversion severities to their names in a coverage report.
+var outcomeNames = map[int]string{
+	Success: "success",
+	Warning: "warning",
+	Failure: "failure",
+	Fatal:   "fatal",
+}
+
+// isSuccessfulOutcome returns true if an example with the given outcome made it into the generated docs.
+func isSuccessfulOutcome(outcome string) bool {
+	return outcome == outcomeNames[Success] || outcome == outcomeNames[Warning]
+}
+
+// report flattens the data collected by the tracker into a CoverageReport.
+func (ct *CoverageTracker) report() *CoverageReport {
+	report := &CoverageReport{
+		Version:         CoverageReportVersion,
+		ProviderName:    ct.ProviderName,
+		ProviderVersion: ct.ProviderVersion,
+		Examples:        []CoverageReportExample{},
+	}
+
+	for _, page := range ct.EncounteredPages {
+		seen := map[string]int{}
+		for _, example := range page.Examples {
+			name := page.Name + "#" + exampleHash(example.OriginalHCL)
+			if n := seen[name]; n > 0 {
+				// The page repeats an example; later copies are numbered in the order they were found.
+				seen[name] = n + 1
+				name = fmt.Sprintf("%s-%d", name, n)
+			} else {
+				seen[name] = 1
+			}
+
+			results := make(map[string]CoverageReportResult, len(example.ConversionResults))
+			for language, result := range example.ConversionResults {
+				results[language] = CoverageReportResult{
+					Outcome:     outcomeNames[result.FailureSeverity],
+					Diagnostics: result.FailureInfo,
+				}
+			}
+
+			report.Examples = append(report.Examples, CoverageReportExample{
+				Name:    name,
+				HCL:     example.OriginalHCL,
+				Results: results,
+			})
+		}
+	}
+
+	sort.Slice(report.Examples, func(i, j int) bool {
+		return report.Examples[i].Name < report.Examples[j].Name
+	})
+
+	return report
+}
+
+// exampleHash returns a short hash of an example's HCL that identifies the example within its page.
+func exampleHash(hcl string) string {
+	sum := sha256.Sum256([]byte(hcl))
+	return hex.EncodeToString(sum[:6])
+}
+
+// writeReport writes the tracker's data to the given path as a versioned coverage report.
+func (ct *CoverageTracker) writeReport(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(ct.report(), "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(bytes, '\n'), 0600)
+}
+
+// ReadCoverageReport reads a coverage report previously written by `tfgen --coverage`.
+func ReadCoverageReport(path string) (*CoverageReport, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var report CoverageReport
+	if err = json.Unmarshal(bytes, &report); err != nil {
+		return nil, fmt.Errorf("reading coverage report %v: %w", path, err)
+	}
+	if report.Version != CoverageReportVersion {
+		return nil, fmt.Errorf("coverage report %v has unsupported version %v (expected %v)",
+			path, report.Version, CoverageReportVersion)
+	}
+	return &report, nil
+}
+
+// CompareCoverageReports returns the set of examples and languages that converted successfully in the previous
+// report, but failed to convert in the current report. Examples or languages that are missing from the current report
+// are not considered regressions, as the upstream docs may have legitimately removed them.
+func CompareCoverageReports(previous, current *CoverageReport) []CoverageRegression {
+	currentExamples := make(map[string]CoverageReportExample, len(current.Examples))
+	for _, example := range current.Examples {
+		currentExamples[example.Name] = example
+	}
+
+	var regressions []CoverageRegression
+	for _, previousExample := range previous.Examples {
+		currentExample, ok := currentExamples[previousExample.Name]
+		if !ok {
+			continue
+		}
+
+		languages := make([]string, 0, len(previousExample.Results))
+		for language := range previousExample.Results {
+			languages = append(languages, language)
+		}
+		sort.Strings(languages)
+
+		for _, language := range languages {
+			currentResult, ok := currentExample.Results[language]
+			if !ok || !isSuccessfulOutcome(previousExample.Results[language].Outcome) ||
+				isSuccessfulOutcome(currentResult.Outcome) {
+				continue
+			}
+
+			regressions = append(regressions, CoverageRegression{
+				Example:     previousExample.Name,
+				Language:    language,
+				Outcome:     currentResult.Outcome,
+				Diagnostics: currentResult.Diagnostics,
+			})
+		}
+	}
+
+	return regressions
+}
+
+// compareWithReport compares the tracker's data with the report at the given path and returns an error describing
+// each regression, if any.
+func (ct *CoverageTracker) compareWithReport(path string) error {
+	previous, err := ReadCoverageReport(path)
+	if err != nil {
+		return err
+	}
+
+	regressions := CompareCoverageReports(previous, ct.report())
+	if len(regressions) == 0 {
+		return nil
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "%d example conversion(s) regressed since %v:", len(regressions), path)
+	for _, r := range regressions {
+		fmt.Fprintf(&message, "\n    %v", r)
+	}
+	return fmt.Errorf("%s", message.String())
+}
@@ -0,0 +1,100 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageReportRoundTrip(t *testing.T) {
+	ct := newCoverageTracker("test", "1.0.0")
+	ct.foundExample("#/resources/test:index/b:B", "resource \"test_b\" \"b\" {}")
+	ct.languageConversionSuccess("typescript")
+	ct.foundExample("#/resources/test:index/a:A", "resource \"test_a\" \"a\" {}")
+	ct.languageConversionSuccess("typescript")
+	ct.languageConversionFailure("python", hcl.Diagnostics{{Summary: "oops"}})
+	ct.foundExample("#/resources/test:index/a:A", "resource \"test_a\" \"a2\" {}")
+	ct.languageConversionPanic("go", "boom")
+	ct.foundExample("#/resources/test:index/a:A", "resource \"test_a\" \"a2\" {}")
+	ct.languageConversionSuccess("go")
+
+	path := filepath.Join(t.TempDir(), "coverage.json")
+	require.NoError(t, ct.writeReport(path))
+
+	report, err := ReadCoverageReport(path)
+	require.NoError(t, err)
+	assert.Equal(t, ct.report(), report)
+
+	assert.Equal(t, CoverageReportVersion, report.Version)
+	// Examples are named after their page and a hash of their HCL, so names do not depend on the order of the
+	// examples within a page. Repeated examples are numbered.
+	a := "#/resources/test:index/a:A#" + exampleHash(`resource "test_a" "a" {}`)
+	a2 := "#/resources/test:index/a:A#" + exampleHash(`resource "test_a" "a2" {}`)
+	examples := map[string]CoverageReportExample{}
+	for _, example := range report.Examples {
+		examples[example.Name] = example
+	}
+	require.Len(t, examples, 4)
+	assert.Contains(t, examples, "#/resources/test:index/b:B#"+exampleHash(`resource "test_b" "b" {}`))
+	assert.Equal(t, CoverageReportResult{Outcome: "failure", Diagnostics: "oops"}, examples[a].Results["python"])
+	assert.Equal(t, CoverageReportResult{Outcome: "fatal", Diagnostics: "boom"}, examples[a2].Results["go"])
+	assert.Equal(t, CoverageReportResult{Outcome: "success"}, examples[a2+"-1"].Results["go"])
+}
+
+func TestCompareCoverageReports(t *testing.T) {
+	previous := &CoverageReport{
+		Version: CoverageReportVersion,
+		Examples: []CoverageReportExample{
+			{
+				Name: "a",
+				Results: map[string]CoverageReportResult{
+					"go":         {Outcome: "success"},
+					"python":     {Outcome: "warning"},
+					"typescript": {Outcome: "failure"},
+				},
+			},
+			{
+				Name:    "removed",
+				Results: map[string]CoverageReportResult{"go": {Outcome: "success"}},
+			},
+		},
+	}
+	current := &CoverageReport{
+		Version: CoverageReportVersion,
+		Examples: []CoverageReportExample{
+			{
+				Name: "a",
+				Results: map[string]CoverageReportResult{
+					"go":         {Outcome: "failure", Diagnostics: "unknown function"},
+					"python":     {Outcome: "success"},
+					"typescript": {Outcome: "fatal"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []CoverageRegression{{
+		Example:     "a",
+		Language:    "go",
+		Outcome:     "failure",
+		Diagnostics: "unknown function",
+	}}, CompareCoverageReports(previous, current))
+	assert.Empty(t, CompareCoverageReports(current, previous))
+}
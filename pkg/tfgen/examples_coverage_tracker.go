@@ -19,10 +19,11 @@ succeeded. Additionally, it allows these diagnostics to be exported in JSON
 format for uploading and further processing.
 
 The tracker records the results of individual translation attempts: a provider
-with 100 examples would have 400 attempts (four languages for each example).
-How many of these attempts succeeded is what the percentages reference. These
-400 attempts can either be exported as a whole, or be grouped by language into
-four categories of 100 attempts, with each corresponding to one example.
+with 100 examples converted to six languages (TypeScript, Python, C#, Go, Java
+and YAML) would have 600 attempts. How many of these attempts succeeded is what
+the percentages reference. These 600 attempts can either be exported as a whole,
+or be grouped by language into six categories of 100 attempts, with each
+corresponding to one example.
 */
 
 package tfgen
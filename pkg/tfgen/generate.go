@@ -68,8 +68,13 @@ type Generator struct {
 	skipDocs         bool
 	skipExamples     bool
 	coverageTracker  *CoverageTracker
+	examplesRoot     afero.Fs   // if non-nil, the root to which standalone example programs are written.
+	check            bool       // true to compare the generated files with those on disk rather than writing them.
+	docsParser       DocsParser // the parser for the upstream markdown docs.
 
-	convertedCode map[string][]byte
+	convertedCode  map[string][]byte // the converted examples, keyed by schema path for PCL and by directory for YAML.
+	exampleCounts  map[string]int    // the number of examples found so far for each schema path.
+	failedExamples []string          // the names of examples that failed to bind against the generated schema.
 }
 
 type Language string
@@ -81,17 +86,29 @@ const (
 	CSharp Language = "dotnet"
 	Schema Language = "schema"
 	PCL    Language = "pulumi"
+	YAML   Language = "yaml"
 )
 
 func (l Language) shouldConvertExamples() bool {
 	switch l {
-	case Golang, NodeJS, Python, CSharp, Schema, PCL:
+	case Golang, NodeJS, Python, CSharp, Schema, PCL, YAML:
 		return true
 	}
 	return false
 }
 
-func (l Language) emitSDK(pkg *pschema.Package, info tfbridge.ProviderInfo, root afero.Fs) (map[string][]byte, error) {
+// emitsExamples returns true if the language's output consists of the converted examples themselves, with each
+// example written as a standalone program.
+func (l Language) emitsExamples() bool {
+	return l == PCL || l == YAML
+}
+
+// emitSDK generates the SDK for the given package. Any overlay files are read from the root and included in the
+// result. If clean is true, the files left over from the last time the SDK was generated are then removed from the
+// root.
+func (l Language) emitSDK(pkg *pschema.Package, info tfbridge.ProviderInfo, root afero.Fs,
+	clean bool) (map[string][]byte, error) {
+
 	var extraFiles map[string][]byte
 	var err error
 
@@ -105,17 +122,10 @@ func (l Language) emitSDK(pkg *pschema.Package, info tfbridge.ProviderInfo, root
 				return nil, err
 			}
 		}
-
-		// We exclude the "tests" directory because some nodejs package dirs (e.g. pulumi-docker)
-		// store tests here. We don't want to include them in the overlays because we don't want it
-		// exported with the module, but we don't want them deleted in a cleanup of the directory.
-		exclusions := codegen.NewStringSet("tests")
-
-		// We don't need to add overlays to the exclusion list because they have already been read
-		// into memory so deleting the files is not a problem.
-		err = cleanDir(root, "", exclusions)
-		if err != nil && !os.IsNotExist(err) {
-			return nil, err
+		if clean {
+			if err = l.cleanSDK(pkg.Name, root); err != nil {
+				return nil, err
+			}
 		}
 		return nodejsgen.GeneratePackage(tfgen, pkg, extraFiles)
 	case Python:
@@ -125,12 +135,10 @@ func (l Language) emitSDK(pkg *pschema.Package, info tfbridge.ProviderInfo, root
 				return nil, err
 			}
 		}
-
-		// python's outdir path follows the pattern [provider]/sdk/python/pulumi_[pkg name]
-		pyOutDir := fmt.Sprintf("pulumi_%s", pkg.Name)
-		err = cleanDir(root, pyOutDir, nil)
-		if err != nil && !os.IsNotExist(err) {
-			return nil, err
+		if clean {
+			if err = l.cleanSDK(pkg.Name, root); err != nil {
+				return nil, err
+			}
 		}
 		return pygen.GeneratePackage(tfgen, pkg, extraFiles)
 	case CSharp:
@@ -140,9 +148,10 @@ func (l Language) emitSDK(pkg *pschema.Package, info tfbridge.ProviderInfo, root
 				return nil, err
 			}
 		}
-		err = cleanDir(root, "", nil)
-		if err != nil && !os.IsNotExist(err) {
-			return nil, err
+		if clean {
+			if err = l.cleanSDK(pkg.Name, root); err != nil {
+				return nil, err
+			}
 		}
 		return dotnetgen.GeneratePackage(tfgen, pkg, extraFiles)
 	default:
@@ -150,6 +159,42 @@ func (l Language) emitSDK(pkg *pschema.Package, info tfbridge.ProviderInfo, root
 	}
 }
 
+// sdkDir returns the directory of the root whose contents are replaced each time the SDK for the named package is
+// generated, along with the names of the directory's entries that are kept. ok is false if the language's output is
+// written over the root without removing anything.
+func (l Language) sdkDir(pkgName string) (dir string, exclusions codegen.StringSet, ok bool) {
+	switch l {
+	case NodeJS:
+		// We exclude the "tests" directory because some nodejs package dirs (e.g. pulumi-docker)
+		// store tests here. We don't want to include them in the overlays because we don't want it
+		// exported with the module, but we don't want them deleted in a cleanup of the directory.
+		return "", codegen.NewStringSet("tests"), true
+	case Python:
+		// python's outdir path follows the pattern [provider]/sdk/python/pulumi_[pkg name]
+		return fmt.Sprintf("pulumi_%s", pkgName), nil, true
+	case CSharp:
+		return "", nil, true
+	default:
+		return "", nil, false
+	}
+}
+
+// cleanSDK removes the files left over from a previous SDK generation for the named package from the root. Overlays
+// must have already been read into memory by emitSDK.
+func (l Language) cleanSDK(pkgName string, root afero.Fs) error {
+	dir, exclusions, ok := l.sdkDir(pkgName)
+	if !ok {
+		return nil
+	}
+
+	// We don't need to add overlays to the exclusion list because they have already been read
+	// into memory so deleting the files is not a problem.
+	if err := cleanDir(root, dir, exclusions); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 var AllLanguages = []Language{Golang, NodeJS, Python, CSharp}
 
 // pkg is a directory containing one or more modules.
@@ -637,6 +682,19 @@ type GeneratorOptions struct {
 	SkipDocs           bool
 	SkipExamples       bool
 	CoverageTracker    *CoverageTracker
+	// Check, if true, causes Generate to compare the generated schema or SDK with the files already present in Root
+	// instead of writing them out, and to fail if they differ.
+	Check bool
+	// ExamplesRoot, if set, causes every converted example to be written to this filesystem as a standalone program
+	// and checked against the generated schema. Examples that fail the check are dropped from the docs.
+	ExamplesRoot afero.Fs
+	// ProviderInfoDir, if set, names a directory of serialized provider info that is used instead of the Pulumi
+	// resource provider plugins when converting examples that reference other providers. Defaults to the value of
+	// PULUMI_PROVIDER_INFO_DIR.
+	ProviderInfoDir string
+	// DocsParser, if set, overrides the parser used for the upstream markdown docs, including any parser set on
+	// ProviderInfo.
+	DocsParser DocsParser
 }
 
 // NewGenerator returns a code-generator for the given language runtime and package info.
@@ -645,11 +703,14 @@ func NewGenerator(opts GeneratorOptions) (*Generator, error) {
 
 	// Ensure the language is valid.
 	switch lang {
-	case Golang, NodeJS, Python, CSharp, Schema, PCL:
+	case Golang, NodeJS, Python, CSharp, Schema, PCL, YAML:
 		// OK
 	default:
 		return nil, errors.Errorf("unrecognized language runtime: %s", lang)
 	}
+	if opts.Check && lang.emitsExamples() {
+		return nil, errors.Errorf("check mode is not supported for %s", lang)
+	}
 
 	// If root is nil, default to sdk/<language>/ in the pwd.
 	if root == nil {
@@ -688,7 +749,15 @@ func NewGenerator(opts GeneratorOptions) (*Generator, error) {
 		pluginHost = ctx.Host
 	}
 
-	infoSources := append([]il.ProviderInfoSource{}, opts.ProviderInfoSource, il.PluginProviderInfoSource)
+	infoSources := []il.ProviderInfoSource{opts.ProviderInfoSource}
+	if infoDir := opts.ProviderInfoDir; infoDir != "" || os.Getenv(il.ProviderInfoDirEnvVar) != "" {
+		if infoDir == "" {
+			infoDir = os.Getenv(il.ProviderInfoDirEnvVar)
+		}
+		infoSources = append(infoSources, il.NewDirectoryProviderInfoSource(infoDir))
+	} else {
+		infoSources = append(infoSources, il.PluginProviderInfoSource)
+	}
 	infoSource := il.NewCachingProviderInfoSource(il.NewMultiProviderInfoSource(infoSources...))
 
 	providerShim := newInMemoryProvider(pkg, nil, info)
@@ -716,6 +785,11 @@ func NewGenerator(opts GeneratorOptions) (*Generator, error) {
 		skipDocs:         opts.SkipDocs,
 		skipExamples:     opts.SkipExamples,
 		coverageTracker:  opts.CoverageTracker,
+		examplesRoot:     opts.ExamplesRoot,
+		check:            opts.Check,
+		docsParser:       getDocsParser(opts),
+		convertedCode:    map[string][]byte{},
+		exampleCounts:    map[string]int{},
 	}, nil
 }
 
@@ -776,6 +850,12 @@ func (g *Generator) Generate() error {
 	// Convert examples.
 	if !g.skipExamples {
 		pulumiPackageSpec = g.convertExamplesInSchema(pulumiPackageSpec)
+
+		if len(g.failedExamples) > 0 {
+			sort.Strings(g.failedExamples)
+			g.warn("%d HCL example(s) failed to bind against the generated schema and were dropped:\n    %s",
+				len(g.failedExamples), strings.Join(g.failedExamples, "\n    "))
+		}
 	}
 
 	// Go ahead and let the language generator do its thing. If we're emitting the schema, just go ahead and serialize
@@ -800,6 +880,15 @@ func (g *Generator) Generate() error {
 			path = strings.TrimPrefix(path, "#/") + ".pp"
 			files[path] = code
 		}
+	case YAML:
+		if g.skipExamples {
+			return fmt.Errorf("Cannot set skipExamples and get YAML")
+		}
+		files = map[string][]byte{}
+		for dir, code := range g.convertedCode {
+			project := fmt.Sprintf("name: %s-example\nruntime: yaml\n", g.pkg)
+			files[path.Join(dir, "Pulumi.yaml")] = append([]byte(project), code...)
+		}
 	default:
 		pulumiPackage, diags, err := pschema.BindSpec(pulumiPackageSpec, nil)
 		if err != nil {
@@ -808,11 +897,17 @@ func (g *Generator) Generate() error {
 		if diags.HasErrors() {
 			return err
 		}
-		if files, err = g.language.emitSDK(pulumiPackage, g.info, g.root); err != nil {
+		if files, err = g.language.emitSDK(pulumiPackage, g.info, g.root, !g.check); err != nil {
 			return errors.Wrapf(err, "failed to generate package")
 		}
 	}
 
+	// In check mode, compare the result with the files on disk rather than writing it out.
+	if g.check {
+		g.pluginHost.Close()
+		return g.checkFiles(pulumiPackageSpec.Name, files)
+	}
+
 	// Write the result to disk. Do not overwrite the root-level README.md if any exists.
 	for f, contents := range files {
 		if f == "README.md" {
@@ -921,7 +1016,8 @@ func (g *Generator) gatherConfig() *module {
 	}
 
 	// Now, if there are any extra config variables, that are Pulumi-only, add them.
-	for key, val := range g.info.ExtraConfig {
+	for _, key := range codegen.SortedKeys(g.info.ExtraConfig) {
+		val := g.info.ExtraConfig[key]
 		if prop := propertyVariable(key, val.Schema, val.Info, "", "", true /*out*/, entityDocs{}); prop != nil {
 			prop.config = true
 			config.addMember(prop)
@@ -1321,7 +1417,7 @@ func (g *Generator) gatherOverlays() (moduleMap, error) {
 		if csharpinfo := g.info.CSharp; csharpinfo != nil {
 			overlay = csharpinfo.Overlay
 		}
-	case Schema, PCL:
+	case Schema, PCL, YAML:
 		// N/A
 	default:
 		contract.Failf("unrecognized language: %s", g.language)
@@ -225,9 +225,11 @@ func (g *schemaGenerator) genPackageSpec(pack *pkg) (pschema.PackageSpec, error)
 
 	var config []*variable
 	for _, mod := range pack.modules.values() {
-		// Generate nested types.
-		for _, t := range gatherSchemaNestedTypesForModule(mod) {
-			tok, ts := g.genObjectType(mod.name, t, false)
+		// Generate nested types. These are visited in a stable order so that the output is deterministic even if
+		// several nested types map to the same token.
+		nestedTypes := gatherSchemaNestedTypesForModule(mod)
+		for _, name := range codegen.SortedKeys(nestedTypes) {
+			tok, ts := g.genObjectType(mod.name, nestedTypes[name], false)
 			spec.Types[tok] = pschema.ComplexTypeSpec{
 				ObjectTypeSpec: ts,
 			}
@@ -252,8 +254,9 @@ func (g *schemaGenerator) genPackageSpec(pack *pkg) (pschema.PackageSpec, error)
 	}
 
 	if pack.provider != nil {
-		for _, t := range gatherSchemaNestedTypesForMember(pack.provider) {
-			tok, ts := g.genObjectType("index", t, false)
+		nestedTypes := gatherSchemaNestedTypesForMember(pack.provider)
+		for _, name := range codegen.SortedKeys(nestedTypes) {
+			tok, ts := g.genObjectType("index", nestedTypes[name], false)
 			spec.Types[tok] = pschema.ComplexTypeSpec{
 				ObjectTypeSpec: ts,
 			}
@@ -759,8 +762,8 @@ func (g *Generator) convertExamplesInPropertySpec(path string, spec pschema.Prop
 
 func (g *Generator) convertExamplesInObjectSpec(path string, spec pschema.ObjectTypeSpec) pschema.ObjectTypeSpec {
 	spec.Description = g.convertExamples(spec.Description, path, false)
-	for name, prop := range spec.Properties {
-		spec.Properties[name] = g.convertExamplesInPropertySpec(fmt.Sprintf("%s/%s", path, name), prop)
+	for _, name := range codegen.SortedKeys(spec.Properties) {
+		spec.Properties[name] = g.convertExamplesInPropertySpec(fmt.Sprintf("%s/%s", path, name), spec.Properties[name])
 	}
 	return spec
 }
@@ -768,11 +771,12 @@ func (g *Generator) convertExamplesInObjectSpec(path string, spec pschema.Object
 func (g *Generator) convertExamplesInResourceSpec(path string, spec pschema.ResourceSpec) pschema.ResourceSpec {
 	spec.Description = g.convertExamples(spec.Description, path, true)
 	spec.DeprecationMessage = g.convertExamples(spec.DeprecationMessage, path, false)
-	for name, prop := range spec.Properties {
-		spec.Properties[name] = g.convertExamplesInPropertySpec(fmt.Sprintf("%s/%s", path, name), prop)
+	for _, name := range codegen.SortedKeys(spec.Properties) {
+		spec.Properties[name] = g.convertExamplesInPropertySpec(fmt.Sprintf("%s/%s", path, name), spec.Properties[name])
 	}
-	for name, prop := range spec.InputProperties {
-		spec.InputProperties[name] = g.convertExamplesInPropertySpec(fmt.Sprintf("%s/%s", path, name), prop)
+	for _, name := range codegen.SortedKeys(spec.InputProperties) {
+		spec.InputProperties[name] = g.convertExamplesInPropertySpec(fmt.Sprintf("%s/%s", path, name),
+			spec.InputProperties[name])
 	}
 	if spec.StateInputs != nil {
 		stateInputs := g.convertExamplesInObjectSpec(path+"/stateInputs", *spec.StateInputs)
@@ -794,20 +798,23 @@ func (g *Generator) convertExamplesInFunctionSpec(path string, spec pschema.Func
 	return spec
 }
 
+// convertExamplesInSchema converts the examples in each description in the schema. Examples are converted in a stable
+// order so that the results, including any conversion diagnostics and coverage data, are the same from run to run.
 func (g *Generator) convertExamplesInSchema(spec pschema.PackageSpec) pschema.PackageSpec {
-	for name, variable := range spec.Config.Variables {
-		spec.Config.Variables[name] = g.convertExamplesInPropertySpec(name, variable)
+	for _, name := range codegen.SortedKeys(spec.Config.Variables) {
+		spec.Config.Variables[name] = g.convertExamplesInPropertySpec(name, spec.Config.Variables[name])
 	}
-	for token, object := range spec.Types {
+	for _, token := range codegen.SortedKeys(spec.Types) {
+		object := spec.Types[token]
 		object.ObjectTypeSpec = g.convertExamplesInObjectSpec("#/types/"+token, object.ObjectTypeSpec)
 		spec.Types[token] = object
 	}
 	spec.Provider = g.convertExamplesInResourceSpec("#/provider", spec.Provider)
-	for token, resource := range spec.Resources {
-		spec.Resources[token] = g.convertExamplesInResourceSpec("#/resources/"+token, resource)
+	for _, token := range codegen.SortedKeys(spec.Resources) {
+		spec.Resources[token] = g.convertExamplesInResourceSpec("#/resources/"+token, spec.Resources[token])
 	}
-	for token, function := range spec.Functions {
-		spec.Functions[token] = g.convertExamplesInFunctionSpec("#/functions/"+token, function)
+	for _, token := range codegen.SortedKeys(spec.Functions) {
+		spec.Functions[token] = g.convertExamplesInFunctionSpec("#/functions/"+token, spec.Functions[token])
 	}
 	return spec
 }
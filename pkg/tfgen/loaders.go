@@ -15,15 +15,19 @@
 package tfgen
 
 import (
+	"errors"
+
 	"github.com/blang/semver"
 
 	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
 type loader struct {
-	innerLoader   schema.Loader
-	emptyPackages map[string]bool
+	innerLoader      schema.Loader
+	emptyPackages    map[string]bool
+	optionalPackages map[string]bool
 }
 
 var _ schema.Loader = &loader{}
@@ -32,18 +36,30 @@ func (l *loader) LoadPackage(name string, ver *semver.Version) (*schema.Package,
 	if l.emptyPackages[name] {
 		return &schema.Package{}, nil
 	}
-	return l.innerLoader.LoadPackage(name, ver)
+	pkg, err := l.innerLoader.LoadPackage(name, ver)
+	var missing *workspace.MissingError
+	if err != nil && l.optionalPackages[name] && errors.As(err, &missing) {
+		return &schema.Package{}, nil
+	}
+	return pkg, err
 }
 
 // Overrides `schema.NewPluginLoader` to load an empty
 // `*schema.Package{}` when `name=''` is requested. In the doc
 // generation context a dummy package seems better than failing in the
 // case.
+//
+// The std package, which provides the helpers that converted examples use in place of TF built-in functions, is
+// also replaced with an empty package if its plugin is not installed. Calls to these helpers are then reported as
+// diagnostics rather than failing the conversion. Other errors loading the std package are returned as-is.
 func newLoader(host plugin.Host) *loader {
 	return &loader{
 		innerLoader: schema.NewPluginLoader(host),
 		emptyPackages: map[string]bool{
 			"": true,
 		},
+		optionalPackages: map[string]bool{
+			"std": true,
+		},
 	}
 }
@@ -0,0 +1,56 @@
+// Copyright 2016-2022, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// errorLoader fails to load every package with the same error.
+type errorLoader struct {
+	err error
+}
+
+func (l errorLoader) LoadPackage(name string, ver *semver.Version) (*schema.Package, error) {
+	return nil, l.err
+}
+
+func TestLoaderOptionalPackages(t *testing.T) {
+	missing := fmt.Errorf("loading std: %w",
+		workspace.NewMissingError(workspace.PluginInfo{Name: "std", Kind: workspace.ResourcePlugin}, false))
+	l := &loader{innerLoader: errorLoader{missing}, optionalPackages: map[string]bool{"std": true}}
+
+	// An optional package whose plugin is not installed is replaced with an empty package.
+	pkg, err := l.LoadPackage("std", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &schema.Package{}, pkg)
+
+	// Other packages, and other errors, are reported.
+	_, err = l.LoadPackage("aws", nil)
+	assert.ErrorIs(t, err, missing)
+
+	broken := errors.New("invalid schema")
+	l.innerLoader = errorLoader{broken}
+	_, err = l.LoadPackage("std", nil)
+	assert.ErrorIs(t, err, broken)
+}
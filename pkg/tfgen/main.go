@@ -24,6 +24,7 @@ import (
 	"runtime/trace"
 
 	"github.com/golang/glog"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tf2pulumi/il"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -52,6 +53,11 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo) *cobra.
 	var debug bool
 	var skipDocs bool
 	var skipExamples bool
+	var coveragePath string
+	var coverageBaseline string
+	var examplesDir string
+	var check bool
+	var providerInfoDir string
 	cmd := &cobra.Command{
 		Use:   os.Args[0] + " <LANGUAGE>",
 		Args:  cmdutil.SpecificArgs([]string{"language"}),
@@ -116,11 +122,24 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo) *cobra.
 				root = afero.NewBasePathFs(afero.NewOsFs(), absOutDir)
 			}
 
+			// Create the directory for standalone example programs, if requested.
+			var examplesRoot afero.Fs
+			if examplesDir != "" {
+				absExamplesDir, err := filepath.Abs(examplesDir)
+				if err != nil {
+					return err
+				}
+				if err = os.MkdirAll(absExamplesDir, 0700); err != nil {
+					return err
+				}
+				examplesRoot = afero.NewBasePathFs(afero.NewOsFs(), absExamplesDir)
+			}
+
 			// Creating an item to keep track of example coverage if the
-			// COVERAGE_OUTPUT_DIR env is set
+			// COVERAGE_OUTPUT_DIR env is set or a coverage report was requested
 			var coverageTracker *CoverageTracker
-			coverageOutputDir, coverageTrackingEnabled := os.LookupEnv("COVERAGE_OUTPUT_DIR")
-			if coverageTrackingEnabled {
+			coverageOutputDir, coverageOutputDirSet := os.LookupEnv("COVERAGE_OUTPUT_DIR")
+			if coverageOutputDirSet || coveragePath != "" || coverageBaseline != "" {
 				coverageTracker = newCoverageTracker(prov.Name, prov.Version)
 			}
 
@@ -135,6 +154,9 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo) *cobra.
 				SkipDocs:        skipDocs,
 				SkipExamples:    skipExamples,
 				CoverageTracker: coverageTracker,
+				ExamplesRoot:    examplesRoot,
+				Check:           check,
+				ProviderInfoDir: providerInfoDir,
 			})
 			if err != nil {
 				return err
@@ -147,11 +169,25 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo) *cobra.
 			}
 
 			// Exporting collected coverage data to the directory specified by COVERAGE_OUTPUT_DIR
-			if coverageTrackingEnabled {
-				err = coverageTracker.exportResults(coverageOutputDir)
+			if coverageOutputDirSet {
+				if err = coverageTracker.exportResults(coverageOutputDir); err != nil {
+					return err
+				}
+			}
+
+			// Writing the versioned coverage report, if requested
+			if coveragePath != "" {
+				if err = coverageTracker.writeReport(coveragePath); err != nil {
+					return err
+				}
+			}
+
+			// Failing if any example that previously converted no longer does
+			if coverageBaseline != "" {
+				return coverageTracker.compareWithReport(coverageBaseline)
 			}
 
-			return err
+			return nil
 		}),
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
 			glog.Flush()
@@ -178,6 +214,22 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo) *cobra.
 		&skipDocs, "skip-docs", false, "Do not convert docs from TF Markdown")
 	cmd.PersistentFlags().BoolVar(
 		&skipExamples, "skip-examples", false, "Do not convert examples from HCL")
+	cmd.PersistentFlags().StringVar(
+		&coveragePath, "coverage", "", "Write a versioned JSON report of example conversion coverage to this file")
+	cmd.PersistentFlags().StringVar(
+		&coverageBaseline, "coverage-baseline", "",
+		"Compare example conversion coverage with this previous report and fail if any example regressed")
+	cmd.PersistentFlags().BoolVar(
+		&check, "check", false,
+		"Compare the generated schema or SDK with the files on disk instead of writing them, and fail if they differ")
+	cmd.PersistentFlags().StringVar(
+		&examplesDir, "examples-dir", "",
+		"Write each converted example to this directory as a standalone program and drop examples that do not "+
+			"bind against the generated schema")
+	cmd.PersistentFlags().StringVar(
+		&providerInfoDir, "provider-info-dir", "",
+		"Read the provider info for providers referenced by examples from the JSON files in this directory instead "+
+			"of from plugins (defaults to $"+il.ProviderInfoDirEnvVar+")")
 
 	cmd.PersistentFlags().StringVar(
 		&overlaysDir, "overlays", "",
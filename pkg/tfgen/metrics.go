@@ -38,6 +38,7 @@ var (
 	hclPythonPartialConversionFailures     int
 	hclTypeScriptPartialConversionFailures int
 	hclCSharpPartialConversionFailures     int
+	hclYAMLPartialConversionFailures       int
 
 	// Arguments metrics:
 	totalArgumentsFromDocs int
@@ -161,6 +162,8 @@ func printDocStats() {
 		hclGoPartialConversionFailures)
 	fmt.Printf("\t%d HCL examples were converted in at least one language but failed to convert to C#\n",
 		hclCSharpPartialConversionFailures)
+	fmt.Printf("\t%d HCL examples were converted in at least one language but failed to convert to YAML\n",
+		hclYAMLPartialConversionFailures)
 	fmt.Printf("\t%d entity document sections contained unexpected HCL code snippets. Examples will be converted, "+
 		"but may not display correctly in the registry, e.g. lacking tabs.\n", unexpectedSnippets)
 	fmt.Println("")
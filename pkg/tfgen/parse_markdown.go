@@ -98,6 +98,27 @@ func parseTopLevelSchemaIntoDocs(
 	}
 }
 
+// parseTopLevelSchemaIntoArgumentDocs is like parseTopLevelSchemaIntoDocs, but records the required and optional
+// parameters as arguments. Only the read-only parameters are recorded as attributes.
+func parseTopLevelSchemaIntoArgumentDocs(
+	accumulatedDocs *entityDocs,
+	schema *topLevelSchema,
+	warn func(fmt string, arg ...interface{})) {
+	for _, param := range append(append([]parameter{}, schema.required...), schema.optional...) {
+		args, created := accumulatedDocs.getOrCreateArgumentDocs(param.name)
+		if !created && args.description != "" && args.description != param.desc {
+			warn("Description conflict for top-level argument %s; candidates are `%s` and `%s`",
+				param.name,
+				args.description,
+				param.desc)
+		}
+		args.description = param.desc
+	}
+
+	readonly := &topLevelSchema{readonly: schema.readonly, nestedSchemata: schema.nestedSchemata}
+	parseTopLevelSchemaIntoDocs(accumulatedDocs, readonly, warn)
+}
+
 func parseTopLevelSchema(node *bf.Node, consumeNode func(node *bf.Node)) (*topLevelSchema, error) {
 	if consumeNode == nil {
 		consumeNode = func(node *bf.Node) {}
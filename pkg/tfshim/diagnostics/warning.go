@@ -0,0 +1,21 @@
+package diagnostics
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+)
+
+// Warning wraps warnings reported by shims, along with the path of the attribute to which they apply, if any.
+type Warning struct {
+	AttributePath cty.Path
+	Summary       string
+	Detail        string
+}
+
+func (w Warning) String() string {
+	if w.Detail != "" {
+		return fmt.Sprintf("%s: %s", w.Summary, w.Detail)
+	}
+	return w.Summary
+}
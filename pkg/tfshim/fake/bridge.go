@@ -0,0 +1,276 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pbstruct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// BridgedProvider is a tfbridge.Provider for a fake provider. Its methods drive the tfbridge.Provider's pulumirpc
+// methods with Pulumi property maps, so that tests can check how a ProviderInfo's mappings transform the properties
+// that pass through the bridge without running the Pulumi engine.
+//
+// Resources and data sources are identified by their Terraform type.
+type BridgedProvider struct {
+	// Provider is the bridged provider.
+	Provider *tfbridge.Provider
+	// Fake is the fake provider that it bridges.
+	Fake *Provider
+
+	name        string
+	resources   map[string]tokens.Type
+	dataSources map[string]tokens.ModuleMember
+}
+
+// Bridge wraps a fake provider in a tfbridge.Provider with the given provider info. The provider's name defaults to
+// "fake", and resources and data sources that have no info are given tokens in the provider's index module.
+func Bridge(p *Provider, info tfbridge.ProviderInfo) *BridgedProvider {
+	if info.Name == "" {
+		info.Name = "fake"
+	}
+	info.P = p.Shim()
+
+	b := &BridgedProvider{
+		Fake:        p,
+		name:        info.Name,
+		resources:   map[string]tokens.Type{},
+		dataSources: map[string]tokens.ModuleMember{},
+	}
+
+	resources := map[string]*tfbridge.ResourceInfo{}
+	for name := range p.Resources {
+		ri, ok := info.Resources[name]
+		if !ok || ri == nil {
+			ri = &tfbridge.ResourceInfo{Tok: tfbridge.MakeType(info.Name, "index", name)}
+		}
+		resources[name], b.resources[name] = ri, ri.Tok
+	}
+	info.Resources = resources
+
+	dataSources := map[string]*tfbridge.DataSourceInfo{}
+	for name := range p.DataSources {
+		dsi, ok := info.DataSources[name]
+		if !ok || dsi == nil {
+			dsi = &tfbridge.DataSourceInfo{Tok: tfbridge.MakeMember(info.Name, "index", name)}
+		}
+		dataSources[name], b.dataSources[name] = dsi, dsi.Tok
+	}
+	info.DataSources = dataSources
+
+	b.Provider = tfbridge.NewProvider(context.Background(), nil, info.Name, info.Version, info.P, info, nil)
+	return b
+}
+
+// URN returns the URN that the bridged provider's methods use for a resource of the given type.
+func (b *BridgedProvider) URN(tfType string) resource.URN {
+	return resource.NewURN("test", "test", "", b.resources[tfType], tokens.QName(tfType))
+}
+
+func (b *BridgedProvider) urn(tfType string) (string, error) {
+	if _, ok := b.resources[tfType]; !ok {
+		return "", fmt.Errorf("unknown resource type %q", tfType)
+	}
+	return string(b.URN(tfType)), nil
+}
+
+// Configure configures the bridged provider with the given config, as the engine does. The provider accepts secrets.
+func (b *BridgedProvider) Configure(config resource.PropertyMap) error {
+	variables := map[string]string{}
+	for k, v := range config {
+		key := fmt.Sprintf("%s:config:%s", b.name, k)
+		if v.IsString() {
+			variables[key] = v.StringValue()
+			continue
+		}
+		bytes, err := json.Marshal(v.Mappable())
+		if err != nil {
+			return err
+		}
+		variables[key] = string(bytes)
+	}
+
+	_, err := b.Provider.Configure(context.Background(), &pulumirpc.ConfigureRequest{
+		Variables:     variables,
+		AcceptSecrets: true,
+	})
+	return err
+}
+
+// Check checks the new inputs of a resource and returns the inputs with which it would be created or updated.
+func (b *BridgedProvider) Check(tfType string, olds, news resource.PropertyMap) (resource.PropertyMap,
+	[]*pulumirpc.CheckFailure, error) {
+
+	urn, err := b.urn(tfType)
+	if err != nil {
+		return nil, nil, err
+	}
+	req := &pulumirpc.CheckRequest{Urn: urn}
+	if req.Olds, err = marshal(olds); err != nil {
+		return nil, nil, err
+	}
+	if req.News, err = marshal(news); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := b.Provider.Check(context.Background(), req)
+	if err != nil {
+		return nil, nil, err
+	}
+	inputs, err := unmarshal(resp.GetInputs())
+	if err != nil {
+		return nil, nil, err
+	}
+	return inputs, resp.GetFailures(), nil
+}
+
+// Diff diffs the prior state of a resource with its new inputs.
+func (b *BridgedProvider) Diff(tfType, id string, olds, news resource.PropertyMap,
+	ignoreChanges ...string) (*pulumirpc.DiffResponse, error) {
+
+	urn, err := b.urn(tfType)
+	if err != nil {
+		return nil, err
+	}
+	req := &pulumirpc.DiffRequest{Id: id, Urn: urn, IgnoreChanges: ignoreChanges}
+	if req.Olds, err = marshal(olds); err != nil {
+		return nil, err
+	}
+	if req.News, err = marshal(news); err != nil {
+		return nil, err
+	}
+	return b.Provider.Diff(context.Background(), req)
+}
+
+// Create creates a resource with the given inputs and returns its ID and state.
+func (b *BridgedProvider) Create(tfType string, news resource.PropertyMap,
+	preview bool) (string, resource.PropertyMap, error) {
+
+	urn, err := b.urn(tfType)
+	if err != nil {
+		return "", nil, err
+	}
+	req := &pulumirpc.CreateRequest{Urn: urn, Preview: preview}
+	if req.Properties, err = marshal(news); err != nil {
+		return "", nil, err
+	}
+
+	resp, err := b.Provider.Create(context.Background(), req)
+	if err != nil {
+		return "", nil, err
+	}
+	outs, err := unmarshal(resp.GetProperties())
+	if err != nil {
+		return "", nil, err
+	}
+	return resp.GetId(), outs, nil
+}
+
+// Read refreshes the state of a resource. If state is nil, the resource is read as if by a `get`. Read returns an
+// empty ID if the resource no longer exists.
+func (b *BridgedProvider) Read(tfType, id string, inputs, state resource.PropertyMap) (string, resource.PropertyMap,
+	error) {
+
+	urn, err := b.urn(tfType)
+	if err != nil {
+		return "", nil, err
+	}
+	req := &pulumirpc.ReadRequest{Id: id, Urn: urn}
+	if req.Inputs, err = marshal(inputs); err != nil {
+		return "", nil, err
+	}
+	if req.Properties, err = marshal(state); err != nil {
+		return "", nil, err
+	}
+
+	resp, err := b.Provider.Read(context.Background(), req)
+	if err != nil {
+		return "", nil, err
+	}
+	outs, err := unmarshal(resp.GetProperties())
+	if err != nil {
+		return "", nil, err
+	}
+	return resp.GetId(), outs, nil
+}
+
+// Update updates a resource from its prior state to its new inputs and returns its new state.
+func (b *BridgedProvider) Update(tfType, id string, olds, news resource.PropertyMap,
+	preview bool) (resource.PropertyMap, error) {
+
+	urn, err := b.urn(tfType)
+	if err != nil {
+		return nil, err
+	}
+	req := &pulumirpc.UpdateRequest{Id: id, Urn: urn, Preview: preview}
+	if req.Olds, err = marshal(olds); err != nil {
+		return nil, err
+	}
+	if req.News, err = marshal(news); err != nil {
+		return nil, err
+	}
+
+	resp, err := b.Provider.Update(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshal(resp.GetProperties())
+}
+
+// Delete deletes a resource with the given state.
+func (b *BridgedProvider) Delete(tfType, id string, olds resource.PropertyMap) error {
+	urn, err := b.urn(tfType)
+	if err != nil {
+		return err
+	}
+	req := &pulumirpc.DeleteRequest{Id: id, Urn: urn}
+	if req.Properties, err = marshal(olds); err != nil {
+		return err
+	}
+
+	_, err = b.Provider.Delete(context.Background(), req)
+	return err
+}
+
+// Invoke reads the data source of the given type with the given arguments.
+func (b *BridgedProvider) Invoke(tfType string, args resource.PropertyMap) (resource.PropertyMap,
+	[]*pulumirpc.CheckFailure, error) {
+
+	tok, ok := b.dataSources[tfType]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown data source %q", tfType)
+	}
+	req := &pulumirpc.InvokeRequest{Tok: string(tok)}
+	var err error
+	if req.Args, err = marshal(args); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := b.Provider.Invoke(context.Background(), req)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret, err := unmarshal(resp.GetReturn())
+	if err != nil {
+		return nil, nil, err
+	}
+	return ret, resp.GetFailures(), nil
+}
+
+func marshal(m resource.PropertyMap) (*pbstruct.Struct, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return plugin.MarshalProperties(m, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+}
+
+func unmarshal(s *pbstruct.Struct) (resource.PropertyMap, error) {
+	return plugin.UnmarshalProperties(s, plugin.MarshalOptions{KeepUnknowns: true, KeepSecrets: true})
+}
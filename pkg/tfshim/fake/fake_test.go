@@ -0,0 +1,170 @@
+package fake
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func newTestProvider() *Provider {
+	return &Provider{
+		Schema: schema.SchemaMap{
+			"region": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		},
+		Resources: map[string]*Resource{
+			"fake_widget": {
+				Schema: schema.SchemaMap{
+					"name":     (&schema.Schema{Type: shim.TypeString, Required: true}).Shim(),
+					"zone":     (&schema.Schema{Type: shim.TypeString, Optional: true, ForceNew: true}).Shim(),
+					"password": (&schema.Schema{Type: shim.TypeString, Optional: true, Sensitive: true}).Shim(),
+					"arn":      (&schema.Schema{Type: shim.TypeString, Computed: true}).Shim(),
+					"settings": (&schema.Schema{
+						Type:     shim.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: (&schema.Resource{
+							Schema: schema.SchemaMap{
+								"enabled": (&schema.Schema{Type: shim.TypeBool, Optional: true}).Shim(),
+							},
+						}).Shim(),
+					}).Shim(),
+				},
+				Computed: map[string]interface{}{"arn": "arn:widget"},
+			},
+		},
+		DataSources: map[string]*Resource{
+			"fake_widgets": {
+				Schema: schema.SchemaMap{
+					"prefix": (&schema.Schema{Type: shim.TypeString, Required: true}).Shim(),
+					"names": (&schema.Schema{
+						Type:     shim.TypeList,
+						Computed: true,
+						Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+					}).Shim(),
+				},
+				Read: func(_ string, config map[string]interface{}) (map[string]interface{}, error) {
+					prefix := config["prefix"].(string)
+					return map[string]interface{}{
+						"prefix": prefix,
+						"names":  []interface{}{prefix + "-a", prefix + "-b"},
+					}, nil
+				},
+			},
+		},
+	}
+}
+
+func TestBridgedResource(t *testing.T) {
+	p := newTestProvider()
+	b := Bridge(p, tfbridge.ProviderInfo{
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"fake_widget": {
+				Tok: "fake:index:Widget",
+				Fields: map[string]*tfbridge.SchemaInfo{
+					"name": {
+						Transform: func(v resource.PropertyValue) (resource.PropertyValue, error) {
+							return resource.NewStringProperty(strings.ToLower(v.StringValue())), nil
+						},
+					},
+					"zone": {Default: &tfbridge.DefaultInfo{Config: "region"}},
+				},
+			},
+		},
+	})
+	require.NoError(t, b.Configure(resource.PropertyMap{"region": resource.NewStringProperty("us-west-2")}))
+	assert.Equal(t, map[string]interface{}{"region": "us-west-2"}, p.Config())
+
+	// Check applies the transform and the config default.
+	inputs, failures, err := b.Check("fake_widget", nil, resource.PropertyMap{
+		"name":     resource.NewStringProperty("Widget"),
+		"password": resource.NewStringProperty("hunter2"),
+		"settings": resource.NewObjectProperty(resource.PropertyMap{"enabled": resource.NewBoolProperty(true)}),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.Equal(t, resource.NewStringProperty("widget"), inputs["name"])
+	assert.Equal(t, resource.NewStringProperty("us-west-2"), inputs["zone"])
+
+	// A preview reports computed attributes as unknown and does not reach the provider.
+	_, outs, err := b.Create("fake_widget", inputs, true)
+	require.NoError(t, err)
+	assert.True(t, outs["arn"].IsComputed())
+	assert.Empty(t, p.CallsTo(MethodCreate))
+
+	// The provider sees the MaxItemsOne block as a list and fills in computed values.
+	id, outs, err := b.Create("fake_widget", inputs, false)
+	require.NoError(t, err)
+	assert.Equal(t, "0", id)
+	creates := p.CallsTo(MethodCreate)
+	require.Len(t, creates, 1)
+	assert.Equal(t, []interface{}{map[string]interface{}{"enabled": true}}, creates[0].Config["settings"])
+	assert.Equal(t, resource.NewStringProperty("arn:widget"), outs["arn"])
+	assert.Equal(t, resource.MakeSecret(resource.NewStringProperty("hunter2")), outs["password"])
+	assert.Equal(t, resource.NewObjectProperty(resource.PropertyMap{"enabled": resource.NewBoolProperty(true)}),
+		outs["settings"])
+
+	// Changing a ForceNew attribute requires replacement.
+	news := inputs.Copy()
+	news["zone"] = resource.NewStringProperty("us-east-1")
+	diff, err := b.Diff("fake_widget", id, outs, news)
+	require.NoError(t, err)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, diff.GetChanges())
+	assert.Equal(t, []string{"zone"}, diff.GetReplaces())
+
+	// Other changes are updates that keep computed values.
+	news = inputs.Copy()
+	news["name"] = resource.NewStringProperty("gadget")
+	diff, err = b.Diff("fake_widget", id, outs, news)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name"}, diff.GetDiffs())
+	assert.Empty(t, diff.GetReplaces())
+
+	outs, err = b.Update("fake_widget", id, outs, news, false)
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewStringProperty("gadget"), outs["name"])
+	assert.Equal(t, resource.NewStringProperty("arn:widget"), outs["arn"])
+	updates := p.CallsTo(MethodUpdate)
+	require.Len(t, updates, 1)
+	assert.Equal(t, "widget", updates[0].State["name"])
+
+	// A resource whose Read returns nil no longer exists.
+	p.Resources["fake_widget"].Read = func(string, map[string]interface{}) (map[string]interface{}, error) {
+		return nil, nil
+	}
+	readID, _, err := b.Read("fake_widget", id, news, outs)
+	require.NoError(t, err)
+	assert.Empty(t, readID)
+
+	require.NoError(t, b.Delete("fake_widget", id, outs))
+	deletes := p.CallsTo(MethodDelete)
+	require.Len(t, deletes, 1)
+	assert.Equal(t, id, deletes[0].ID)
+}
+
+func TestBridgedDataSource(t *testing.T) {
+	p := newTestProvider()
+	b := Bridge(p, tfbridge.ProviderInfo{})
+
+	ret, failures, err := b.Invoke("fake_widgets", resource.PropertyMap{"prefix": resource.NewStringProperty("w")})
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.Equal(t, resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewStringProperty("w-a"),
+		resource.NewStringProperty("w-b"),
+	}), ret["names"])
+
+	// Missing required arguments are reported as failures.
+	_, failures, err = b.Invoke("fake_widgets", resource.PropertyMap{})
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Contains(t, failures[0].Reason, "prefix")
+	assert.Len(t, p.CallsTo(MethodReadDataSource), 1)
+}
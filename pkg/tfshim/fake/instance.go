@@ -0,0 +1,339 @@
+package fake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// This corresponds to the TF plugin SDK's timeouts key.
+const timeoutsKey = "e2bfb730-ecaa-11e6-8f88-34363bc7c4c0"
+
+var _ = shim.InstanceState((*instanceState)(nil))
+
+type instanceState struct {
+	resource *Resource
+	id       string
+	object   map[string]interface{}
+	meta     map[string]interface{}
+}
+
+func (s *instanceState) Type() string {
+	return ""
+}
+
+func (s *instanceState) ID() string {
+	return s.id
+}
+
+// Object returns the resource's state. As with real providers, the state includes the resource's ID.
+func (s *instanceState) Object(sch shim.SchemaMap) (map[string]interface{}, error) {
+	object := make(map[string]interface{}, len(s.object)+1)
+	for k, v := range s.object {
+		object[k] = v
+	}
+	if _, ok := object["id"]; !ok && s.id != "" {
+		object["id"] = s.id
+	}
+	return object, nil
+}
+
+func (s *instanceState) Meta() map[string]interface{} {
+	return s.meta
+}
+
+var _ = shim.InstanceDiff((*instanceDiff)(nil))
+
+type instanceDiff struct {
+	resource    *Resource
+	config      map[string]interface{}
+	prior       map[string]interface{}
+	planned     map[string]interface{}
+	meta        map[string]interface{}
+	destroy     bool
+	requiresNew bool
+	attributes  map[string]shim.ResourceAttrDiff
+}
+
+// newInstanceDiff plans a change to a resource. The planned state is the config plus the resource's computed
+// attributes, which keep their prior values if they have any and are otherwise unknown.
+func newInstanceDiff(r *Resource, state shim.InstanceState, config map[string]interface{}) *instanceDiff {
+	var prior map[string]interface{}
+	if state != nil && state.ID() != "" {
+		prior = state.(*instanceState).object
+	}
+
+	planned := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		planned[k] = v
+	}
+	r.schema().Range(func(k string, s shim.Schema) bool {
+		if v, ok := planned[k]; s.Computed() && (!ok || v == nil) {
+			if p, ok := prior[k]; ok {
+				planned[k] = p
+			} else {
+				planned[k] = schema.UnknownVariableValue
+			}
+		}
+		return true
+	})
+
+	d := &instanceDiff{resource: r, config: config, prior: prior, planned: planned}
+	d.computeAttributes()
+	return d
+}
+
+// computeAttributes diffs the flattened prior and planned states. Replacement is only required if the resource
+// already exists.
+func (d *instanceDiff) computeAttributes() {
+	old, new := map[string]flatValue{}, map[string]flatValue{}
+	flattenObject("", d.prior, d.resource.schema(), false, old)
+	flattenObject("", d.planned, d.resource.schema(), false, new)
+
+	d.attributes, d.requiresNew = map[string]shim.ResourceAttrDiff{}, false
+	for k, o := range old {
+		if _, ok := new[k]; !ok {
+			d.setAttribute(k, shim.ResourceAttrDiff{Old: o.value, NewRemoved: true, RequiresNew: o.forceNew})
+		}
+	}
+	for k, n := range new {
+		o, ok := old[k]
+		if ok && o.value == n.value {
+			continue
+		}
+		d.setAttribute(k, shim.ResourceAttrDiff{
+			Old:         o.value,
+			New:         n.value,
+			NewComputed: n.value == schema.UnknownVariableValue,
+			RequiresNew: n.forceNew || o.forceNew,
+		})
+	}
+}
+
+func (d *instanceDiff) setAttribute(k string, diff shim.ResourceAttrDiff) {
+	diff.RequiresNew = diff.RequiresNew && d.prior != nil
+	d.attributes[k] = diff
+	d.requiresNew = d.requiresNew || diff.RequiresNew
+}
+
+func (d *instanceDiff) Attribute(key string) *shim.ResourceAttrDiff {
+	if diff, ok := d.attributes[key]; ok {
+		return &diff
+	}
+	return nil
+}
+
+func (d *instanceDiff) Attributes() map[string]shim.ResourceAttrDiff {
+	return d.attributes
+}
+
+func (d *instanceDiff) ProposedState(res shim.Resource, priorState shim.InstanceState) (shim.InstanceState, error) {
+	var id string
+	if priorState != nil {
+		id = priorState.ID()
+	}
+	return &instanceState{resource: d.resource, id: id, object: d.planned, meta: d.meta}, nil
+}
+
+func (d *instanceDiff) Destroy() bool {
+	return d.destroy
+}
+
+func (d *instanceDiff) RequiresNew() bool {
+	return d.requiresNew
+}
+
+// IgnoreChanges restores the prior values of the ignored attributes in the planned state and removes their diffs.
+func (d *instanceDiff) IgnoreChanges(ignored map[string]bool) {
+	if len(ignored) == 0 || d.destroy {
+		return
+	}
+
+	if d.prior != nil {
+		for key := range ignored {
+			restore(d.planned, d.prior, strings.Split(key, "."))
+		}
+	}
+	d.computeAttributes()
+	for k := range d.attributes {
+		if isIgnoredKey(k, ignored) {
+			delete(d.attributes, k)
+		}
+	}
+	d.requiresNew = false
+	for _, diff := range d.attributes {
+		d.requiresNew = d.requiresNew || diff.RequiresNew
+	}
+}
+
+// isIgnoredKey returns true if the given flatmap key or one of its parents is ignored.
+func isIgnoredKey(key string, ignored map[string]bool) bool {
+	if ignored[key] {
+		return true
+	}
+	for attr := range ignored {
+		if strings.HasPrefix(key, attr+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// restore copies the value at the given flatmap path in prior to the same path in planned.
+func restore(planned, prior interface{}, path []string) {
+	switch planned := planned.(type) {
+	case map[string]interface{}:
+		prior, _ := prior.(map[string]interface{})
+		p, ok := prior[path[0]]
+		if len(path) > 1 {
+			restore(planned[path[0]], p, path[1:])
+		} else if ok {
+			planned[path[0]] = p
+		} else {
+			delete(planned, path[0])
+		}
+	case []interface{}:
+		prior, _ := prior.([]interface{})
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i >= len(planned) || i >= len(prior) {
+			return
+		}
+		if len(path) > 1 {
+			restore(planned[i], prior[i], path[1:])
+		} else {
+			planned[i] = prior[i]
+		}
+	}
+}
+
+func (d *instanceDiff) EncodeTimeouts(timeouts *shim.ResourceTimeout) error {
+	if timeouts == nil {
+		return nil
+	}
+
+	timeoutsMap := map[string]interface{}{}
+	for key, timeout := range map[string]*time.Duration{
+		"create":  timeouts.Create,
+		"update":  timeouts.Update,
+		"read":    timeouts.Read,
+		"delete":  timeouts.Delete,
+		"default": timeouts.Default,
+	} {
+		if timeout != nil {
+			timeoutsMap[key] = timeout.Nanoseconds()
+		}
+	}
+
+	if d.meta == nil {
+		d.meta = map[string]interface{}{}
+	}
+	d.meta[timeoutsKey] = timeoutsMap
+	return nil
+}
+
+func (d *instanceDiff) SetTimeout(timeout float64, timeoutKey string) {
+	if d.meta == nil {
+		d.meta = map[string]interface{}{}
+	}
+	timeoutsMap, ok := d.meta[timeoutsKey].(map[string]interface{})
+	if !ok {
+		timeoutsMap = map[string]interface{}{}
+		d.meta[timeoutsKey] = timeoutsMap
+	}
+	timeoutsMap[timeoutKey] = time.Duration(timeout * float64(time.Second)).Nanoseconds()
+}
+
+// mergeMeta returns the prior metadata of a resource overlaid with the metadata of a diff.
+func mergeMeta(prior, diff map[string]interface{}) map[string]interface{} {
+	if len(diff) == 0 {
+		return prior
+	}
+	merged := map[string]interface{}{}
+	for k, v := range prior {
+		merged[k] = v
+	}
+	for k, v := range diff {
+		merged[k] = v
+	}
+	return merged
+}
+
+// flatValue is the string form of a value in a flattened state, in the style of Terraform's flatmap.
+type flatValue struct {
+	value    string
+	forceNew bool
+}
+
+func flattenObject(prefix string, object map[string]interface{}, sch shim.SchemaMap, forceNew bool,
+	out map[string]flatValue) {
+
+	for k, v := range object {
+		var s shim.Schema
+		if sch != nil {
+			s, _ = sch.GetOk(k)
+		}
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		flattenValue(key, v, s, forceNew || (s != nil && s.ForceNew()), out)
+	}
+}
+
+// flattenValue flattens a value. List elements are keyed by their index and set elements by their hash, as computed
+// by the set's schema.
+func flattenValue(key string, v interface{}, s shim.Schema, forceNew bool, out map[string]flatValue) {
+	var elemSchema shim.Schema
+	var elemResource shim.SchemaMap
+	if s != nil {
+		switch e := s.Elem().(type) {
+		case shim.Schema:
+			elemSchema = e
+		case shim.Resource:
+			elemResource = e.Schema()
+		}
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return
+	case []interface{}:
+		out[key+".#"] = flatValue{strconv.Itoa(len(v)), forceNew}
+		for i, e := range v {
+			index := strconv.Itoa(i)
+			if s != nil && s.Type() == shim.TypeSet {
+				index = strconv.Itoa(s.SetHash(e))
+			}
+			if m, ok := e.(map[string]interface{}); ok && elemResource != nil {
+				flattenObject(key+"."+index, m, elemResource, forceNew, out)
+			} else {
+				flattenValue(key+"."+index, e, elemSchema, forceNew, out)
+			}
+		}
+	case map[string]interface{}:
+		if elemResource != nil && s.Type() != shim.TypeMap {
+			flattenObject(key, v, elemResource, forceNew, out)
+			return
+		}
+		out[key+".%"] = flatValue{strconv.Itoa(len(v)), forceNew}
+		for k, e := range v {
+			flattenValue(key+"."+k, e, elemSchema, forceNew, out)
+		}
+	case string:
+		if v == schema.UnknownVariableValue && s != nil {
+			switch s.Type() {
+			case shim.TypeList, shim.TypeSet:
+				key += ".#"
+			case shim.TypeMap:
+				key += ".%"
+			}
+		}
+		out[key] = flatValue{v, forceNew}
+	default:
+		out[key] = flatValue{fmt.Sprint(v), forceNew}
+	}
+}
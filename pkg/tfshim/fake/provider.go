@@ -0,0 +1,360 @@
+// Package fake implements an in-memory shim.Provider for testing provider mappings without a real Terraform provider.
+//
+// A fake provider's schema is declared with the types in the tfshim/schema package. Its resources store their state
+// in memory and can be scripted to behave like real resources: they may fill in computed values and replace any of
+// their Create, Read, Update, and Delete operations. Each operation that reaches the provider is recorded so that
+// tests can check exactly what the bridge sent to it.
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// The methods recorded by a fake provider.
+const (
+	MethodConfigure      = "Configure"
+	MethodCreate         = "Create"
+	MethodRead           = "Read"
+	MethodUpdate         = "Update"
+	MethodDelete         = "Delete"
+	MethodReadDataSource = "ReadDataSource"
+)
+
+// Call records an operation that was sent to a fake provider.
+type Call struct {
+	// Method is the operation, one of the Method constants.
+	Method string
+	// Type is the resource or data source type. It is empty for Configure.
+	Type string
+	// ID is the ID of the resource, if any.
+	ID string
+	// Config is the provider's config for Configure, the planned state of the resource for Create and Update, and
+	// the data source's config for ReadDataSource.
+	Config map[string]interface{}
+	// State is the prior state of the resource for Read, Update, and Delete.
+	State map[string]interface{}
+}
+
+// Provider is a fake provider with the given schema, resources, and data sources.
+type Provider struct {
+	Schema      shim.SchemaMap
+	Resources   map[string]*Resource
+	DataSources map[string]*Resource
+
+	// ConfigureFunc, if set, is called with the provider's config when the provider is configured.
+	ConfigureFunc func(config map[string]interface{}) error
+
+	m      sync.Mutex
+	calls  []Call
+	config map[string]interface{}
+	nextID int
+}
+
+// Shim returns the shim.Provider for this provider.
+func (p *Provider) Shim() shim.Provider {
+	return providerShim{p}
+}
+
+// Calls returns the operations that have been sent to the provider, in order.
+func (p *Provider) Calls() []Call {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return append([]Call(nil), p.calls...)
+}
+
+// CallsTo returns the operations with the given method that have been sent to the provider, in order.
+func (p *Provider) CallsTo(method string) []Call {
+	var calls []Call
+	for _, c := range p.Calls() {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// ResetCalls forgets the operations that have been sent to the provider.
+func (p *Provider) ResetCalls() {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.calls = nil
+}
+
+// Config returns the config with which the provider was last configured.
+func (p *Provider) Config() map[string]interface{} {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.config
+}
+
+func (p *Provider) record(c Call) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.calls = append(p.calls, c)
+}
+
+func (p *Provider) newID() string {
+	p.m.Lock()
+	defer p.m.Unlock()
+	id := strconv.Itoa(p.nextID)
+	p.nextID++
+	return id
+}
+
+var _ = shim.Provider(providerShim{})
+
+type providerShim struct {
+	p *Provider
+}
+
+func (s providerShim) Schema() shim.SchemaMap {
+	if s.p.Schema == nil {
+		return schema.SchemaMap{}
+	}
+	return s.p.Schema
+}
+
+func (s providerShim) ResourcesMap() shim.ResourceMap {
+	return resourceMap(s.p.Resources)
+}
+
+func (s providerShim) DataSourcesMap() shim.ResourceMap {
+	return resourceMap(s.p.DataSources)
+}
+
+func (s providerShim) resource(t string) (*Resource, error) {
+	if r, ok := s.p.Resources[t]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown resource type %q", t)
+}
+
+func (s providerShim) dataSource(t string) (*Resource, error) {
+	if r, ok := s.p.DataSources[t]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown data source %q", t)
+}
+
+func (s providerShim) Validate(c shim.ResourceConfig) ([]string, []error) {
+	return nil, validateConfig(s.Schema(), configObject(c))
+}
+
+func (s providerShim) ValidateResource(t string, c shim.ResourceConfig) ([]string, []error) {
+	r, err := s.resource(t)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return nil, validateConfig(r.schema(), configObject(c))
+}
+
+func (s providerShim) ValidateDataSource(t string, c shim.ResourceConfig) ([]string, []error) {
+	r, err := s.dataSource(t)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return nil, validateConfig(r.schema(), configObject(c))
+}
+
+func (s providerShim) Configure(c shim.ResourceConfig) error {
+	config := configObject(c)
+	s.p.record(Call{Method: MethodConfigure, Config: config})
+
+	if s.p.ConfigureFunc != nil {
+		if err := s.p.ConfigureFunc(config); err != nil {
+			return err
+		}
+	}
+
+	s.p.m.Lock()
+	defer s.p.m.Unlock()
+	s.p.config = config
+	return nil
+}
+
+func (s providerShim) Diff(t string, state shim.InstanceState, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+	r, err := s.resource(t)
+	if err != nil {
+		return nil, err
+	}
+	return newInstanceDiff(r, state, configObject(c)), nil
+}
+
+func (s providerShim) Apply(t string, state shim.InstanceState, d shim.InstanceDiff) (shim.InstanceState, error) {
+	r, err := s.resource(t)
+	if err != nil {
+		return nil, err
+	}
+	diff := d.(*instanceDiff)
+
+	var prior *instanceState
+	if state != nil {
+		prior = state.(*instanceState)
+	}
+
+	switch {
+	case diff.destroy:
+		if prior == nil {
+			return nil, nil
+		}
+		s.p.record(Call{Method: MethodDelete, Type: t, ID: prior.id, State: prior.object})
+		if r.Delete != nil {
+			if err := r.Delete(prior.id, prior.object); err != nil {
+				return state, err
+			}
+		}
+		return nil, nil
+	case prior == nil || prior.id == "":
+		planned := r.resolveComputed(diff.planned)
+		s.p.record(Call{Method: MethodCreate, Type: t, Config: planned})
+
+		id, object := "", planned
+		if r.Create != nil {
+			id, object, err = r.Create(planned)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if id == "" {
+			id = s.p.newID()
+		}
+		return &instanceState{resource: r, id: id, object: object, meta: diff.meta}, nil
+	default:
+		planned := r.resolveComputed(diff.planned)
+		s.p.record(Call{Method: MethodUpdate, Type: t, ID: prior.id, Config: planned, State: prior.object})
+
+		object := planned
+		if r.Update != nil {
+			object, err = r.Update(prior.id, prior.object, planned)
+			if err != nil {
+				return state, err
+			}
+		}
+		return &instanceState{resource: r, id: prior.id, object: object, meta: mergeMeta(prior.meta, diff.meta)}, nil
+	}
+}
+
+func (s providerShim) Refresh(t string, state shim.InstanceState) (shim.InstanceState, error) {
+	r, err := s.resource(t)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, nil
+	}
+	prior := state.(*instanceState)
+	s.p.record(Call{Method: MethodRead, Type: t, ID: prior.id, State: prior.object})
+
+	object := prior.object
+	if r.Read != nil {
+		object, err = r.Read(prior.id, prior.object)
+		if err != nil {
+			return nil, err
+		}
+		if object == nil {
+			return nil, nil
+		}
+	}
+	return &instanceState{resource: r, id: prior.id, object: object, meta: prior.meta}, nil
+}
+
+func (s providerShim) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+	r, err := s.dataSource(t)
+	if err != nil {
+		return nil, err
+	}
+	return newInstanceDiff(r, nil, configObject(c)), nil
+}
+
+func (s providerShim) ReadDataApply(t string, d shim.InstanceDiff) (shim.InstanceState, error) {
+	r, err := s.dataSource(t)
+	if err != nil {
+		return nil, err
+	}
+	config := d.(*instanceDiff).config
+	s.p.record(Call{Method: MethodReadDataSource, Type: t, Config: config})
+
+	object := r.resolveComputed(d.(*instanceDiff).planned)
+	if r.Read != nil {
+		object, err = r.Read("", config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	id, _ := object["id"].(string)
+	if id == "" {
+		id = t
+	}
+	return &instanceState{resource: r, id: id, object: object}, nil
+}
+
+func (s providerShim) Meta() interface{} {
+	return s.p.Config()
+}
+
+func (s providerShim) Stop() error {
+	return nil
+}
+
+func (s providerShim) InitLogging() {}
+
+func (s providerShim) NewDestroyDiff() shim.InstanceDiff {
+	return &instanceDiff{destroy: true}
+}
+
+func (s providerShim) NewResourceConfig(object map[string]interface{}) shim.ResourceConfig {
+	return resourceConfig(object)
+}
+
+func (s providerShim) IsSet(v interface{}) ([]interface{}, bool) {
+	return nil, false
+}
+
+var _ = shim.ResourceConfig(resourceConfig(nil))
+
+type resourceConfig map[string]interface{}
+
+func (c resourceConfig) IsSet(k string) bool {
+	_, ok := c[k]
+	return ok
+}
+
+func configObject(c shim.ResourceConfig) map[string]interface{} {
+	if c == nil {
+		return map[string]interface{}{}
+	}
+	return c.(resourceConfig)
+}
+
+// validateConfig checks that every required attribute is set and that every attribute is part of the schema.
+func validateConfig(sch shim.SchemaMap, config map[string]interface{}) []error {
+	var errs []error
+	if sch != nil {
+		sch.Range(func(k string, s shim.Schema) bool {
+			if s.Required() {
+				if v, ok := config[k]; !ok || v == nil {
+					errs = append(errs, fmt.Errorf("%q: required field is not set", k))
+				}
+			}
+			return true
+		})
+	}
+	for k := range config {
+		if sch == nil {
+			errs = append(errs, fmt.Errorf("%q: invalid or unknown key", k))
+		} else if _, ok := sch.GetOk(k); !ok {
+			errs = append(errs, fmt.Errorf("%q: invalid or unknown key", k))
+		}
+	}
+
+	// Report errors in a stable order.
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
@@ -0,0 +1,140 @@
+package fake
+
+import (
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// Resource is a fake resource or data source. Its state is a map from attribute names to values in the same form as
+// the config that the bridge passes to the provider: strings, bools, ints, float64s, []interface{} for lists and
+// sets, and map[string]interface{} for maps and nested blocks.
+//
+// By default, a resource's state is its config plus its computed values, and reading a resource returns its prior
+// state unchanged. Each operation can be replaced by a function.
+type Resource struct {
+	Schema        shim.SchemaMap
+	SchemaVersion int
+	Timeouts      *shim.ResourceTimeout
+
+	// Computed holds the values that the provider assigns to computed attributes that are not set by the config.
+	// Computed attributes keep their prior values, if any. Otherwise, they are unknown until the resource is created
+	// or updated, at which point they take their values from Computed.
+	Computed map[string]interface{}
+
+	// Create is called with the planned state of a new resource, in which computed attributes have been filled in from
+	// Computed. It returns the new resource's ID and state. If the ID is empty, the provider assigns one.
+	Create func(planned map[string]interface{}) (string, map[string]interface{}, error)
+	// Read is called with the ID and prior state of a resource and returns its current state, or nil if the resource
+	// no longer exists. For a data source, Read is called with an empty ID and the data source's config.
+	Read func(id string, state map[string]interface{}) (map[string]interface{}, error)
+	// Update is called with the ID and prior state of a resource and its planned state, in which computed attributes
+	// have been filled in from Computed. It returns the resource's new state.
+	Update func(id string, state, planned map[string]interface{}) (map[string]interface{}, error)
+	// Delete is called with the ID and prior state of a resource.
+	Delete func(id string, state map[string]interface{}) error
+}
+
+// Shim returns the shim.Resource for this resource.
+func (r *Resource) Shim() shim.Resource {
+	return resourceShim{r}
+}
+
+func (r *Resource) schema() shim.SchemaMap {
+	if r.Schema == nil {
+		return schema.SchemaMap{}
+	}
+	return r.Schema
+}
+
+// resolveComputed replaces the unknown values in a planned state with the values in Computed. Unknown values that are
+// not listed are removed.
+func (r *Resource) resolveComputed(planned map[string]interface{}) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(planned))
+	for k, v := range planned {
+		if v != schema.UnknownVariableValue {
+			resolved[k] = v
+		} else if c, ok := r.Computed[k]; ok {
+			resolved[k] = c
+		}
+	}
+	return resolved
+}
+
+var _ = shim.Resource(resourceShim{})
+
+type resourceShim struct {
+	r *Resource
+}
+
+func (s resourceShim) Schema() shim.SchemaMap {
+	return s.r.schema()
+}
+
+func (s resourceShim) SchemaVersion() int {
+	return s.r.SchemaVersion
+}
+
+func (s resourceShim) Importer() shim.ImportFunc {
+	return nil
+}
+
+func (s resourceShim) DeprecationMessage() string {
+	return ""
+}
+
+func (s resourceShim) Timeouts() *shim.ResourceTimeout {
+	return s.r.Timeouts
+}
+
+func (s resourceShim) InstanceState(id string, object, meta map[string]interface{}) (shim.InstanceState, error) {
+	// Drop any attributes that are not part of the schema, such as the ID and the bridge's private properties.
+	sch, state := s.r.schema(), map[string]interface{}{}
+	for k, v := range object {
+		if _, ok := sch.GetOk(k); ok {
+			state[k] = v
+		}
+	}
+	return &instanceState{resource: s.r, id: id, object: state, meta: meta}, nil
+}
+
+func (s resourceShim) DecodeTimeouts(config shim.ResourceConfig) (*shim.ResourceTimeout, error) {
+	return s.r.Timeouts, nil
+}
+
+var _ = shim.ResourceMap(resourceMap{})
+
+type resourceMap map[string]*Resource
+
+func (m resourceMap) Len() int {
+	return len(m)
+}
+
+func (m resourceMap) Get(key string) shim.Resource {
+	if r, ok := m[key]; ok {
+		return resourceShim{r}
+	}
+	return nil
+}
+
+func (m resourceMap) GetOk(key string) (shim.Resource, bool) {
+	if r, ok := m[key]; ok {
+		return resourceShim{r}, true
+	}
+	return nil, false
+}
+
+func (m resourceMap) Range(each func(key string, value shim.Resource) bool) {
+	for key, r := range m {
+		if !each(key, resourceShim{r}) {
+			return
+		}
+	}
+}
+
+func (m resourceMap) Set(key string, value shim.Resource) {
+	if s, ok := value.(resourceShim); ok {
+		m[key] = s.r
+		return
+	}
+	m[key] = &Resource{Schema: value.Schema(), SchemaVersion: value.SchemaVersion(), Timeouts: value.Timeouts()}
+}
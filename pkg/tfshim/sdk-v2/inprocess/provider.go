@@ -0,0 +1,40 @@
+// Package inprocess drives SDKv2 providers through their own gRPC provider servers, as Terraform does, instead of
+// through the SDK's helper/schema APIs. The servers run in-process.
+//
+// This package is separate from the sdk-v2 shim because it links the tfplugin5 protocol types, which cannot be linked
+// into the same binary as terraform-plugin-go's gRPC server.
+package inprocess
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5"
+)
+
+var _ = shim.ProviderWithGRPCServer(provider{})
+
+type provider struct {
+	shim.Provider
+
+	tf *schema.Provider
+}
+
+// NewProvider returns a shim.Provider for the given provider that supports ProviderInfo.UseGRPCServer. Until it is
+// served, the provider behaves like the one returned by the sdk-v2 shim's NewProvider.
+func NewProvider(p *schema.Provider) shim.Provider {
+	return provider{Provider: shimv2.NewProvider(p), tf: p}
+}
+
+// NewGRPCProvider serves the given provider's gRPC provider server in-process and returns a shim.Provider that
+// communicates with it. The server is stopped when the context is done.
+func NewGRPCProvider(ctx context.Context, p *schema.Provider) (shim.Provider, error) {
+	return tfplugin5.ServeProvider(ctx, schema.NewGRPCProviderServer(p), "")
+}
+
+func (p provider) GRPCServerProvider(ctx context.Context) (shim.Provider, error) {
+	return NewGRPCProvider(ctx, p.tf)
+}
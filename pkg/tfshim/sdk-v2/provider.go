@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -11,7 +12,7 @@ import (
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 )
 
-var _ = shim.Provider(v2Provider{})
+var _ = shim.ProviderWithMeta(v2Provider{})
 
 func configFromShim(c shim.ResourceConfig) *terraform.ResourceConfig {
 	if c == nil {
@@ -50,10 +51,41 @@ func diffToShim(d *terraform.InstanceDiff) shim.InstanceDiff {
 
 type v2Provider struct {
 	tf *schema.Provider
+
+	// providerMeta is the value of the provider_meta block, if any.
+	providerMeta cty.Value
 }
 
 func NewProvider(p *schema.Provider) shim.Provider {
-	return v2Provider{p}
+	return v2Provider{tf: p}
+}
+
+// WithProviderMeta returns a copy of the provider that passes the given provider_meta value to resources when they
+// are diffed, applied, or refreshed. The SDK does not pass provider_meta to data sources.
+func (p v2Provider) WithProviderMeta(meta map[string]interface{}) (shim.Provider, error) {
+	if p.tf.ProviderMetaSchema == nil {
+		return p, nil
+	}
+
+	val, err := schema.InternalMap(p.tf.ProviderMetaSchema).CoreConfigSchema().CoerceValue(
+		schema.HCL2ValueFromConfigValue(meta))
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider_meta: %w", err)
+	}
+	p.providerMeta = val
+	return p, nil
+}
+
+// withProviderMeta stamps the provider's provider_meta value, if any, into the given state.
+func (p v2Provider) withProviderMeta(state *terraform.InstanceState) *terraform.InstanceState {
+	if p.providerMeta == cty.NilVal {
+		return state
+	}
+	if state == nil {
+		state = &terraform.InstanceState{}
+	}
+	state.ProviderMeta = p.providerMeta
+	return state
 }
 
 func (p v2Provider) Schema() shim.SchemaMap {
@@ -103,7 +135,7 @@ func (p v2Provider) Diff(t string, s shim.InstanceState, c shim.ResourceConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upgrade resource state: %w", err)
 	}
-	diff, err := r.SimpleDiff(context.TODO(), state, config, p.tf.Meta())
+	diff, err := r.SimpleDiff(context.TODO(), p.withProviderMeta(state), config, p.tf.Meta())
 	if diff != nil {
 		diff.RawConfig = rawConfig
 	}
@@ -119,7 +151,7 @@ func (p v2Provider) Apply(t string, s shim.InstanceState, d shim.InstanceDiff) (
 	if err != nil {
 		return nil, fmt.Errorf("failed to upgrade resource state: %w", err)
 	}
-	state, diags := r.Apply(context.TODO(), state, diffFromShim(d), p.tf.Meta())
+	state, diags := r.Apply(context.TODO(), p.withProviderMeta(state), diffFromShim(d), p.tf.Meta())
 	return stateToShim(state), errors(diags)
 }
 
@@ -132,7 +164,7 @@ func (p v2Provider) Refresh(t string, s shim.InstanceState) (shim.InstanceState,
 	if err != nil {
 		return nil, fmt.Errorf("failed to upgrade resource state: %w", err)
 	}
-	state, diags := r.RefreshWithoutUpgrade(context.TODO(), state, p.tf.Meta())
+	state, diags := r.RefreshWithoutUpgrade(context.TODO(), p.withProviderMeta(state), p.tf.Meta())
 	return stateToShim(state), errors(diags)
 }
 
@@ -0,0 +1,82 @@
+package sdkv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+func TestProviderMeta(t *testing.T) {
+	type providerMeta struct {
+		Module string `cty:"module_name"`
+	}
+	readMeta := func(d *schema.ResourceData) diag.Diagnostics {
+		var meta providerMeta
+		if err := d.GetProviderMeta(&meta); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := d.Set("module_name", meta.Module); err != nil {
+			return diag.FromErr(err)
+		}
+		return nil
+	}
+
+	tf := &schema.Provider{
+		ProviderMetaSchema: map[string]*schema.Schema{
+			"module_name": {Type: schema.TypeString, Optional: true},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"example_resource": {
+				Schema: map[string]*schema.Schema{
+					"input":       {Type: schema.TypeString, Optional: true},
+					"module_name": {Type: schema.TypeString, Computed: true},
+				},
+				CreateContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+					d.SetId("0")
+					return readMeta(d)
+				},
+				ReadContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+					return readMeta(d)
+				},
+				DeleteContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+					return nil
+				},
+			},
+		},
+	}
+
+	p, err := NewProvider(tf).(shim.ProviderWithMeta).WithProviderMeta(map[string]interface{}{
+		"module_name": "pulumi/dev",
+	})
+	require.NoError(t, err)
+
+	config := p.NewResourceConfig(map[string]interface{}{"input": "foo"})
+	diff, err := p.Diff("example_resource", nil, config)
+	require.NoError(t, err)
+
+	// The provider_meta passed to Diff does not change the diff of a new resource.
+	plain, err := NewProvider(tf).Diff("example_resource", nil, config)
+	require.NoError(t, err)
+	assert.Equal(t, plain.Attributes(), diff.Attributes())
+	assert.Equal(t, plain.RequiresNew(), diff.RequiresNew())
+
+	state, err := p.Apply("example_resource", nil, diff)
+	require.NoError(t, err)
+	assert.Equal(t, "pulumi/dev", stateFromShim(state).Attributes["module_name"])
+
+	stateFromShim(state).Attributes["module_name"] = ""
+	state, err = p.Refresh("example_resource", state)
+	require.NoError(t, err)
+	assert.Equal(t, "pulumi/dev", stateFromShim(state).Attributes["module_name"])
+
+	// Providers without a provider_meta schema ignore the value.
+	tf.ProviderMetaSchema = nil
+	_, err = NewProvider(tf).(shim.ProviderWithMeta).WithProviderMeta(map[string]interface{}{"foo": "bar"})
+	assert.NoError(t, err)
+}
@@ -1,7 +1,10 @@
 package shim
 
 import (
+	"context"
 	"time"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 )
 
 type ResourceConfig interface {
@@ -158,3 +161,52 @@ type Provider interface {
 	NewResourceConfig(object map[string]interface{}) ResourceConfig
 	IsSet(v interface{}) ([]interface{}, bool)
 }
+
+// ProviderWithWarnings is implemented by providers that report the warnings issued by operations that otherwise only
+// report errors. Warnings carry the path of the attribute to which they apply, if any.
+type ProviderWithWarnings interface {
+	Provider
+
+	ConfigureWithWarnings(c ResourceConfig) ([]*diagnostics.Warning, error)
+	DiffWithWarnings(t string, s InstanceState, c ResourceConfig) (InstanceDiff, []*diagnostics.Warning, error)
+	ApplyWithWarnings(t string, s InstanceState, d InstanceDiff) (InstanceState, []*diagnostics.Warning, error)
+	RefreshWithWarnings(t string, s InstanceState) (InstanceState, []*diagnostics.Warning, error)
+	ReadDataApplyWithWarnings(t string, d InstanceDiff) (InstanceState, []*diagnostics.Warning, error)
+}
+
+// ProviderWithMeta is implemented by providers that accept the value of Terraform's provider_meta block.
+type ProviderWithMeta interface {
+	Provider
+
+	// WithProviderMeta returns a copy of the provider that passes the given provider_meta value to the TF provider
+	// when it plans, applies, or reads resources and reads data sources.
+	WithProviderMeta(meta map[string]interface{}) (Provider, error)
+}
+
+// ProviderWithGRPCServer is implemented by providers that can be driven through their own gRPC provider server, as
+// Terraform drives them, rather than through their native Go APIs.
+type ProviderWithGRPCServer interface {
+	Provider
+
+	// GRPCServerProvider serves the provider over gRPC in-process and returns a Provider that communicates with the
+	// server. The server is stopped when the context is done.
+	GRPCServerProvider(ctx context.Context) (Provider, error)
+}
+
+// ProviderWithUnknownCollections is implemented by providers whose resource configs can represent unknown lists, sets,
+// and maps. For these providers, an unknown collection is represented by the UnknownVariableValue sentinel itself
+// rather than by a collection of unknown elements, so its length is not assumed.
+type ProviderWithUnknownCollections interface {
+	Provider
+
+	SupportsUnknownCollections() bool
+}
+
+// SchemaMapWithUnknownCollections is implemented by the schema maps of the configs, resources, and data sources of
+// providers that implement ProviderWithUnknownCollections, so that conversions that are only given a schema can
+// represent unknown collections in the same way.
+type SchemaMapWithUnknownCollections interface {
+	SchemaMap
+
+	SupportsUnknownCollections() bool
+}
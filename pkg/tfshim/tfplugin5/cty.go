@@ -171,11 +171,6 @@ func reflectToCty(v reflect.Value, ty cty.Type) (cty.Value, error) {
 				if err != nil {
 					return cty.NilVal, err
 				}
-
-				// Sets cannot be partially-known: if any element is unknown, the entire set is unknown.
-				if !val.IsKnown() {
-					return cty.UnknownVal(ty), nil
-				}
 				values[i] = val
 			}
 			return cty.SetVal(values), nil
@@ -140,3 +140,60 @@ func TestObject(t *testing.T) {
 		"baz": cty.ListVal([]cty.Value{cty.StringVal("qux"), cty.StringVal("zed")}),
 	}))
 }
+
+func TestGoToCtyUnknowns(t *testing.T) {
+	blockT := cty.Object(map[string]cty.Type{"name": cty.String})
+	objectT := cty.Object(map[string]cty.Type{
+		"list":   cty.List(cty.String),
+		"set":    cty.Set(cty.String),
+		"blocks": cty.List(blockT),
+		"map":    cty.Map(cty.String),
+	})
+
+	// Unknown collections, partially-known sets, and unknown nested objects are all preserved.
+	cases := []struct {
+		value    interface{}
+		expected cty.Value
+	}{
+		{
+			value: map[string]interface{}{
+				"list":   UnknownVariableValue,
+				"set":    UnknownVariableValue,
+				"blocks": UnknownVariableValue,
+				"map":    UnknownVariableValue,
+			},
+			expected: cty.ObjectVal(map[string]cty.Value{
+				"list":   cty.UnknownVal(cty.List(cty.String)),
+				"set":    cty.UnknownVal(cty.Set(cty.String)),
+				"blocks": cty.UnknownVal(cty.List(blockT)),
+				"map":    cty.UnknownVal(cty.Map(cty.String)),
+			}),
+		},
+		{
+			value: map[string]interface{}{
+				"list":   []interface{}{"foo", UnknownVariableValue},
+				"set":    []interface{}{"foo", UnknownVariableValue},
+				"blocks": []interface{}{UnknownVariableValue, map[string]interface{}{"name": UnknownVariableValue}},
+				"map":    map[string]interface{}{"foo": "bar", "baz": UnknownVariableValue},
+			},
+			expected: cty.ObjectVal(map[string]cty.Value{
+				"list": cty.ListVal([]cty.Value{cty.StringVal("foo"), cty.UnknownVal(cty.String)}),
+				"set":  cty.SetVal([]cty.Value{cty.StringVal("foo"), cty.UnknownVal(cty.String)}),
+				"blocks": cty.ListVal([]cty.Value{
+					cty.UnknownVal(blockT),
+					cty.ObjectVal(map[string]cty.Value{"name": cty.UnknownVal(cty.String)}),
+				}),
+				"map": cty.MapVal(map[string]cty.Value{
+					"foo": cty.StringVal("bar"),
+					"baz": cty.UnknownVal(cty.String),
+				}),
+			}),
+		},
+	}
+	for _, c := range cases {
+		actual, err := goToCty(c.value, objectT)
+		if assert.NoError(t, err) {
+			assert.True(t, c.expected.RawEquals(actual), "expected %#v, got %#v", c.expected, actual)
+		}
+	}
+}
@@ -32,13 +32,28 @@ func unmarshalWarningsAndErrors(diags []*proto.Diagnostic) ([]string, []error) {
 // unmarshalErrors converts a set of diagnostics from its wire format to a (possibly multi-) error. Diagnostics that
 // are not errors are dropped.
 func unmarshalErrors(diags []*proto.Diagnostic) error {
+	_, err := unmarshalDiagnostics(diags)
+	return err
+}
+
+// unmarshalDiagnostics converts a set of diagnostics from its wire format to a list of warnings and a (possibly multi-)
+// error. Diagnostics with unknown severity are dropped.
+func unmarshalDiagnostics(diags []*proto.Diagnostic) ([]*diagnostics.Warning, error) {
+	var warnings []*diagnostics.Warning
 	var err error
 	for _, d := range diags {
-		if d.Severity == proto.Diagnostic_ERROR {
+		switch d.Severity {
+		case proto.Diagnostic_ERROR:
 			err = multierror.Append(err, fromTF5ProtoDiag(d))
+		case proto.Diagnostic_WARNING:
+			warnings = append(warnings, &diagnostics.Warning{
+				AttributePath: pathToCty(d.Attribute),
+				Summary:       d.Summary,
+				Detail:        d.Detail,
+			})
 		}
 	}
-	return err
+	return warnings, err
 }
 
 func fromTF5ProtoDiag(diagnostic *proto.Diagnostic) error {
@@ -4,6 +4,7 @@ import (
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/assert"
 
@@ -54,3 +55,30 @@ func TestErrors(t *testing.T) {
 	assert.Equal(t, multierror.Append(nil, &diagnostics.ValidationError{Summary: "error 1"},
 		&diagnostics.ValidationError{Summary: "error 2"}), err)
 }
+
+func TestDiagnostics(t *testing.T) {
+	attribute := &proto.AttributePath{Steps: []*proto.AttributePath_Step{
+		{Selector: &proto.AttributePath_Step_AttributeName{AttributeName: "foo"}},
+		{Selector: &proto.AttributePath_Step_ElementKeyInt{ElementKeyInt: 0}},
+	}}
+	diags := append([]*proto.Diagnostic{
+		{Severity: proto.Diagnostic_WARNING, Summary: "warning 3", Detail: "detail", Attribute: attribute},
+	}, mixed...)
+
+	warnings, err := unmarshalDiagnostics(diags)
+	assert.Equal(t, []*diagnostics.Warning{
+		{
+			Summary:       "warning 3",
+			Detail:        "detail",
+			AttributePath: cty.GetAttrPath("foo").IndexInt(0),
+		},
+		{Summary: "warning 1"},
+		{Summary: "warning 2"},
+	}, warnings)
+	assert.Equal(t, multierror.Append(nil, &diagnostics.ValidationError{Summary: "error 1"},
+		&diagnostics.ValidationError{Summary: "error 2"}), err)
+
+	warnings, err = unmarshalDiagnostics(warningsOnly)
+	assert.Len(t, warnings, 2)
+	assert.NoError(t, err)
+}
@@ -21,6 +21,7 @@ var _ = shim.InstanceDiff((*instanceDiff)(nil))
 
 type instanceDiff struct {
 	config      cty.Value
+	prior       cty.Value
 	planned     cty.Value
 	meta        map[string]interface{}
 	destroy     bool
@@ -34,6 +35,7 @@ func newInstanceDiff(config, prior, planned cty.Value, meta map[string]interface
 	attributes, requiresNew := computeDiff(prior, planned, requiresReplace)
 	return &instanceDiff{
 		config:      config,
+		prior:       prior,
 		planned:     planned,
 		meta:        meta,
 		destroy:     planned.IsNull(),
@@ -80,19 +82,78 @@ func (d *instanceDiff) RequiresNew() bool {
 	return d.requiresNew
 }
 
+// IgnoreChanges removes the diffs of the ignored attributes. Unlike the SDK shims, which apply the attribute diffs
+// themselves, this shim sends the planned state to the provider when the diff is applied. Removing the attribute diffs
+// alone would therefore still apply the ignored changes, so the prior values of the ignored attributes are restored
+// in the planned state as well. Whether the resource must be replaced is recomputed from the remaining diffs, since an
+// ignored change must not force a replacement.
 func (d *instanceDiff) IgnoreChanges(ignored map[string]bool) {
-	for k := range d.attributes {
-		if ignored[k] {
+	if len(ignored) == 0 {
+		return
+	}
+
+	d.requiresNew = false
+	for k, diff := range d.attributes {
+		if isIgnoredKey(k, ignored) {
 			delete(d.attributes, k)
-		} else {
-			for attr := range ignored {
-				if strings.HasPrefix(k, attr+".") {
-					delete(d.attributes, k)
-					break
-				}
+		} else if diff.RequiresNew {
+			d.requiresNew = true
+		}
+	}
+
+	// There are no prior values to restore for a resource that is being created or destroyed.
+	if d.planned.IsNull() || d.prior == (cty.Value{}) || d.prior.IsNull() {
+		return
+	}
+	planned, err := cty.Transform(d.planned, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if len(path) == 0 || !isIgnoredKey(pathKey(path), ignored) {
+			return v, nil
+		}
+		if prior, err := path.Apply(d.prior); err == nil {
+			return prior, nil
+		}
+		return v, nil
+	})
+	if err == nil {
+		d.planned = planned
+	}
+}
+
+// isIgnoredKey returns true if the given flatmap key or one of its parents is ignored.
+func isIgnoredKey(key string, ignored map[string]bool) bool {
+	if ignored[key] {
+		return true
+	}
+	for attr := range ignored {
+		if strings.HasPrefix(key, attr+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// pathKey returns the flatmap key for the given path. Set elements are keyed by their hash, as they are in diffs.
+func pathKey(path cty.Path) string {
+	var builder strings.Builder
+	for _, step := range path {
+		if builder.Len() != 0 {
+			builder.WriteString(".")
+		}
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			builder.WriteString(step.Name)
+		case cty.IndexStep:
+			switch {
+			case step.Key.Type() == cty.String:
+				builder.WriteString(step.Key.AsString())
+			case step.Key.Type() == cty.Number:
+				builder.WriteString(step.Key.AsBigFloat().Text('f', -1))
+			default:
+				builder.WriteString(setIndex(step.Key))
 			}
 		}
 	}
+	return builder.String()
 }
 
 func (d *instanceDiff) EncodeTimeouts(timeouts *shim.ResourceTimeout) error {
@@ -1,6 +1,7 @@
 package tfplugin5
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/hashicorp/go-cty/cty"
@@ -93,6 +94,7 @@ func diffTest(t *testing.T, attributes map[string]cty.Type, requiresReplace []*p
 
 	expectedDiff := &instanceDiff{
 		config:     plannedVal,
+		prior:      priorVal,
 		planned:    plannedVal,
 		attributes: expected,
 	}
@@ -904,3 +906,94 @@ func TestUnknownSetUpdateReplace(t *testing.T) {
 			"prop.#": update("1", UnknownVariableValue, true),
 		})
 }
+
+// ignoreChangesTest returns a diff that updates every attribute of an object. Changes to "set" and "list.0.kind"
+// require replacement.
+func ignoreChangesTest() (prior cty.Value, diff *instanceDiff) {
+	objectType := cty.Object(map[string]cty.Type{
+		"prop": cty.String,
+		"set":  cty.Set(cty.String),
+		"list": cty.List(cty.Object(map[string]cty.Type{
+			"kind": cty.String,
+			"name": cty.String,
+		})),
+	})
+
+	prior = cty.ObjectVal(map[string]cty.Value{
+		"prop": cty.StringVal("foo"),
+		"set":  cty.SetVal([]cty.Value{cty.StringVal("foo")}),
+		"list": cty.ListVal([]cty.Value{listElement("a", "foo")}),
+	})
+	planned := cty.ObjectVal(map[string]cty.Value{
+		"prop": cty.StringVal("bar"),
+		"set":  cty.SetVal([]cty.Value{cty.StringVal("foo"), cty.StringVal("bar")}),
+		"list": cty.ListVal([]cty.Value{listElement("b", "bar")}),
+	})
+	requiresReplace := []*proto.AttributePath{path("set"), path("list", 0, "kind")}
+	for _, p := range requiresReplace {
+		resolvePath(p, objectType)
+	}
+	return prior, newInstanceDiff(planned, prior, planned, nil, requiresReplace)
+}
+
+func listElement(kind, name string) cty.Value {
+	return cty.ObjectVal(map[string]cty.Value{"kind": cty.StringVal(kind), "name": cty.StringVal(name)})
+}
+
+func TestIgnoreChangesRemovesDiffs(t *testing.T) {
+	_, diff := ignoreChangesTest()
+	diff.IgnoreChanges(map[string]bool{"prop": true, "list": true})
+	assert.Equal(t, []string{"set.#", "set." + setIndex(cty.StringVal("bar"))}, sortedKeys(diff.Attributes()))
+}
+
+func TestIgnoreChangesRequiresNew(t *testing.T) {
+	_, diff := ignoreChangesTest()
+	assert.True(t, diff.RequiresNew())
+
+	// Ignoring an update leaves the replacements in place.
+	diff.IgnoreChanges(map[string]bool{"prop": true})
+	assert.True(t, diff.RequiresNew())
+
+	// Ignoring one replacement leaves the other in place.
+	diff.IgnoreChanges(map[string]bool{"set": true})
+	assert.True(t, diff.RequiresNew())
+
+	// Ignoring every replacement turns the diff into an update.
+	diff.IgnoreChanges(map[string]bool{"list.0.kind": true})
+	assert.False(t, diff.RequiresNew())
+	assert.Equal(t, map[string]shim.ResourceAttrDiff{
+		"list.0.name": update("foo", "bar", false),
+	}, diff.Attributes())
+}
+
+func TestIgnoreChangesRestoresPriorValues(t *testing.T) {
+	_, diff := ignoreChangesTest()
+	diff.IgnoreChanges(map[string]bool{"set": true, "list.0.kind": true})
+
+	expected := cty.ObjectVal(map[string]cty.Value{
+		"prop": cty.StringVal("bar"),
+		"set":  cty.SetVal([]cty.Value{cty.StringVal("foo")}),
+		"list": cty.ListVal([]cty.Value{listElement("a", "bar")}),
+	})
+	assert.True(t, expected.RawEquals(diff.planned), "expected %#v, got %#v", expected, diff.planned)
+}
+
+func TestIgnoreChangesOnCreate(t *testing.T) {
+	prior, diff := ignoreChangesTest()
+	planned := diff.planned
+
+	// A resource that is being created has no prior values to restore.
+	diff = newInstanceDiff(planned, cty.NullVal(prior.Type()), planned, nil, nil)
+	diff.IgnoreChanges(map[string]bool{"prop": true})
+	assert.NotContains(t, diff.Attributes(), "prop")
+	assert.True(t, planned.RawEquals(diff.planned), "expected %#v, got %#v", planned, diff.planned)
+}
+
+func sortedKeys(m map[string]shim.ResourceAttrDiff) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
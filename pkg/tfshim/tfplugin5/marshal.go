@@ -219,6 +219,7 @@ func unmarshalResource(p *provider, typeName string, resourceSchema *proto.Schem
 		provider:      p,
 		resourceType:  typeName,
 		ctyType:       ctyType,
+		block:         resourceSchema.Block,
 		schema:        properties,
 		schemaVersion: int(resourceSchema.Version),
 	}, nil
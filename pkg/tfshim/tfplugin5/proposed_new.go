@@ -0,0 +1,230 @@
+package tfplugin5
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
+)
+
+// proposedNew constructs the proposed new state of a resource from its prior state and its configuration, following
+// the semantics of Terraform's objchange.ProposedNew: computed attributes that are not set by the configuration retain
+// their prior values, and nested blocks are correlated with their prior counterparts according to their nesting mode.
+// The result is sent to the provider as the ProposedNewState of PlanResourceChange.
+func proposedNew(block *proto.Schema_Block, prior, config cty.Value) cty.Value {
+	// If both the config and the prior state are null, return early. This prevents non-null nested blocks from
+	// appearing in the proposed state.
+	if config.IsNull() && prior.IsNull() {
+		return prior
+	}
+
+	if prior.IsNull() {
+		// Construct a synthetic prior value that is similar to the result of decoding an empty configuration block.
+		// This gives us one non-null level of object to pull values from.
+		prior = emptyBlockValue(block, prior.Type())
+	}
+	return proposedNewObject(block, prior, config)
+}
+
+// emptyBlockValue returns the value of an empty block of the given object type: all attributes are null, nested
+// single blocks are null, nested group blocks are empty, and nested collections of blocks are empty.
+func emptyBlockValue(block *proto.Schema_Block, ty cty.Type) cty.Value {
+	attrs := map[string]cty.Value{}
+	for name, ty := range ty.AttributeTypes() {
+		attrs[name] = cty.NullVal(ty)
+	}
+	for _, nestedBlock := range block.BlockTypes {
+		ty := ty.AttributeType(nestedBlock.TypeName)
+		switch nestedBlock.Nesting {
+		case proto.Schema_NestedBlock_GROUP:
+			attrs[nestedBlock.TypeName] = emptyBlockValue(nestedBlock.Block, ty)
+		case proto.Schema_NestedBlock_LIST:
+			attrs[nestedBlock.TypeName] = cty.ListValEmpty(ty.ElementType())
+		case proto.Schema_NestedBlock_SET:
+			attrs[nestedBlock.TypeName] = cty.SetValEmpty(ty.ElementType())
+		case proto.Schema_NestedBlock_MAP:
+			attrs[nestedBlock.TypeName] = cty.MapValEmpty(ty.ElementType())
+		}
+	}
+	return cty.ObjectVal(attrs)
+}
+
+func proposedNewObject(block *proto.Schema_Block, prior, config cty.Value) cty.Value {
+	if config.IsNull() || !config.IsKnown() {
+		return config
+	}
+
+	attrs := proposedNewAttributes(block.Attributes, prior, config)
+	for _, nestedBlock := range block.BlockTypes {
+		name := nestedBlock.TypeName
+		attrs[name] = proposedNewNestedBlock(nestedBlock, prior.GetAttr(name), config.GetAttr(name))
+	}
+	return cty.ObjectVal(attrs)
+}
+
+func proposedNewAttributes(attributes []*proto.Schema_Attribute, prior, config cty.Value) map[string]cty.Value {
+	attrs := make(map[string]cty.Value, len(attributes))
+	for _, attr := range attributes {
+		priorV, configV := prior.GetAttr(attr.Name), config.GetAttr(attr.Name)
+
+		switch {
+		case attr.Computed && attr.Optional:
+			// Keep the prior value unless the config overrides it. As in Terraform, this means that a value that is
+			// set in config and later removed from config remains "sticky" unless the provider overrides it during
+			// planning.
+			if configV.IsNull() {
+				attrs[attr.Name] = priorV
+			} else {
+				attrs[attr.Name] = configV
+			}
+		case attr.Computed:
+			// The config value is always null for purely computed attributes.
+			attrs[attr.Name] = priorV
+		default:
+			// Non-computed attributes always take the config value, even if it is null.
+			attrs[attr.Name] = configV
+		}
+	}
+	return attrs
+}
+
+func proposedNewNestedBlock(nestedBlock *proto.Schema_NestedBlock, prior, config cty.Value) cty.Value {
+	// An entirely unknown block can only come from a dynamic block with an unknown for_each expression.
+	if !config.IsKnown() {
+		return config
+	}
+
+	switch nestedBlock.Nesting {
+	case proto.Schema_NestedBlock_LIST:
+		return proposedNewBlockList(nestedBlock.Block, prior, config)
+	case proto.Schema_NestedBlock_SET:
+		return proposedNewBlockSet(nestedBlock.Block, prior, config)
+	case proto.Schema_NestedBlock_MAP:
+		return proposedNewBlockMap(nestedBlock.Block, prior, config)
+	default:
+		return proposedNew(nestedBlock.Block, prior, config)
+	}
+}
+
+// proposedNewBlockList correlates list blocks by index.
+func proposedNewBlockList(block *proto.Schema_Block, prior, config cty.Value) cty.Value {
+	if config.IsNull() || config.LengthInt() == 0 {
+		return cty.ListValEmpty(config.Type().ElementType())
+	}
+
+	values := make([]cty.Value, 0, config.LengthInt())
+	for it := config.ElementIterator(); it.Next(); {
+		idx, configEV := it.Element()
+		if prior.IsKnown() && (prior.IsNull() || !prior.HasIndex(idx).True()) {
+			// If there is no corresponding prior element, take the config value as-is.
+			values = append(values, configEV)
+			continue
+		}
+		values = append(values, proposedNew(block, prior.Index(idx), configEV))
+	}
+	return cty.ListVal(values)
+}
+
+// proposedNewBlockMap correlates map blocks by key.
+func proposedNewBlockMap(block *proto.Schema_Block, prior, config cty.Value) cty.Value {
+	if config.IsNull() || config.LengthInt() == 0 {
+		return cty.MapValEmpty(config.Type().ElementType())
+	}
+
+	values := make(map[string]cty.Value, config.LengthInt())
+	for it := config.ElementIterator(); it.Next(); {
+		idx, configEV := it.Element()
+		if prior.IsKnown() && (prior.IsNull() || !prior.HasIndex(idx).True()) {
+			// If there is no corresponding prior element, take the config value as-is.
+			values[idx.AsString()] = configEV
+			continue
+		}
+		values[idx.AsString()] = proposedNew(block, prior.Index(idx), configEV)
+	}
+	return cty.MapVal(values)
+}
+
+// proposedNewBlockSet correlates set blocks by comparing their values after eliminating all computed attributes. Any
+// change to the config of a block therefore produces an entirely new block, and prior computed values are only
+// propagated if the non-computed values are identical.
+func proposedNewBlockSet(block *proto.Schema_Block, prior, config cty.Value) cty.Value {
+	if config.IsNull() || config.LengthInt() == 0 {
+		return cty.SetValEmpty(config.Type().ElementType())
+	}
+
+	var priorElements, compareValues []cty.Value
+	if prior.IsKnown() && !prior.IsNull() {
+		for it := prior.ElementIterator(); it.Next(); {
+			_, priorEV := it.Element()
+			priorElements = append(priorElements, priorEV)
+			compareValues = append(compareValues, setElementCompareValue(block, priorEV))
+		}
+	}
+
+	// Track which prior elements have been used in case several have the same compare value.
+	used := make([]bool, len(priorElements))
+	values := make([]cty.Value, 0, config.LengthInt())
+	for it := config.ElementIterator(); it.Next(); {
+		_, configEV := it.Element()
+
+		priorEV := cty.NullVal(configEV.Type())
+		for i, compareValue := range compareValues {
+			if !used[i] && compareValue.RawEquals(configEV) {
+				priorEV, used[i] = priorElements[i], true
+				break
+			}
+		}
+		values = append(values, proposedNew(block, priorEV, configEV))
+	}
+	return cty.SetVal(values)
+}
+
+// setElementCompareValue returns the value of a prior set element with all of its computed attributes set to null.
+// The result can be compared with an element of the config.
+func setElementCompareValue(block *proto.Schema_Block, v cty.Value) cty.Value {
+	if v.IsNull() || !v.IsKnown() {
+		return v
+	}
+
+	attrs := map[string]cty.Value{}
+	for _, attr := range block.Attributes {
+		if attr.Computed {
+			attrs[attr.Name] = cty.NullVal(v.Type().AttributeType(attr.Name))
+		} else {
+			attrs[attr.Name] = v.GetAttr(attr.Name)
+		}
+	}
+	for _, nestedBlock := range block.BlockTypes {
+		name, nv := nestedBlock.TypeName, v.GetAttr(nestedBlock.TypeName)
+		switch nestedBlock.Nesting {
+		case proto.Schema_NestedBlock_LIST, proto.Schema_NestedBlock_SET:
+			if nv.IsNull() || !nv.IsKnown() || nv.LengthInt() == 0 {
+				attrs[name] = nv
+				continue
+			}
+			elements := make([]cty.Value, 0, nv.LengthInt())
+			for it := nv.ElementIterator(); it.Next(); {
+				_, ev := it.Element()
+				elements = append(elements, setElementCompareValue(nestedBlock.Block, ev))
+			}
+			if nestedBlock.Nesting == proto.Schema_NestedBlock_SET {
+				attrs[name] = cty.SetVal(elements)
+			} else {
+				attrs[name] = cty.ListVal(elements)
+			}
+		case proto.Schema_NestedBlock_MAP:
+			if nv.IsNull() || !nv.IsKnown() || nv.LengthInt() == 0 {
+				attrs[name] = nv
+				continue
+			}
+			elements := make(map[string]cty.Value, nv.LengthInt())
+			for it := nv.ElementIterator(); it.Next(); {
+				k, ev := it.Element()
+				elements[k.AsString()] = setElementCompareValue(nestedBlock.Block, ev)
+			}
+			attrs[name] = cty.MapVal(elements)
+		default:
+			attrs[name] = setElementCompareValue(nestedBlock.Block, nv)
+		}
+	}
+	return cty.ObjectVal(attrs)
+}
@@ -0,0 +1,252 @@
+package tfplugin5
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
+)
+
+func testAttribute(name string, required, optional, computed bool) *proto.Schema_Attribute {
+	return &proto.Schema_Attribute{Name: name, Required: required, Optional: optional, Computed: computed}
+}
+
+func testNestedBlock(name string, nesting proto.Schema_NestedBlock_NestingMode,
+	block *proto.Schema_Block) *proto.Schema_NestedBlock {
+
+	return &proto.Schema_NestedBlock{TypeName: name, Nesting: nesting, Block: block}
+}
+
+// The expected values in these tests are the proposed new states computed by Terraform's objchange.ProposedNew for
+// the same schemas, prior states, and configs.
+func TestProposedNew(t *testing.T) {
+	blockSchema := &proto.Schema_Block{
+		Attributes: []*proto.Schema_Attribute{
+			testAttribute("foo", false, true, false),
+			testAttribute("bar", false, true, true),
+			testAttribute("baz", false, false, true),
+		},
+	}
+	blockType := cty.Object(map[string]cty.Type{
+		"foo": cty.String,
+		"bar": cty.String,
+		"baz": cty.String,
+	})
+	blockVal := func(foo, bar, baz cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"foo": foo, "bar": bar, "baz": baz})
+	}
+	null := cty.NullVal(cty.String)
+
+	nestedSchema := func(nesting proto.Schema_NestedBlock_NestingMode) *proto.Schema_Block {
+		return &proto.Schema_Block{
+			Attributes: []*proto.Schema_Attribute{
+				testAttribute("id", false, true, true),
+			},
+			BlockTypes: []*proto.Schema_NestedBlock{
+				testNestedBlock("block", nesting, blockSchema),
+			},
+		}
+	}
+	nestedType := func(ty cty.Type) cty.Type {
+		return cty.Object(map[string]cty.Type{"id": cty.String, "block": ty})
+	}
+	nestedVal := func(id, block cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{"id": id, "block": block})
+	}
+
+	cases := []struct {
+		name     string
+		schema   *proto.Schema_Block
+		prior    cty.Value
+		config   cty.Value
+		expected cty.Value
+	}{
+		{
+			name:     "empty",
+			schema:   &proto.Schema_Block{},
+			prior:    cty.NullVal(cty.EmptyObject),
+			config:   cty.NullVal(cty.EmptyObject),
+			expected: cty.NullVal(cty.EmptyObject),
+		},
+		{
+			name:     "no prior",
+			schema:   blockSchema,
+			prior:    cty.NullVal(blockType),
+			config:   blockVal(cty.StringVal("hello"), null, null),
+			expected: blockVal(cty.StringVal("hello"), null, null),
+		},
+		{
+			name:     "computed attributes keep prior values",
+			schema:   blockSchema,
+			prior:    blockVal(cty.StringVal("bonjour"), cty.StringVal("petit dejeuner"), cty.StringVal("grande dejeuner")),
+			config:   blockVal(cty.StringVal("hello"), null, null),
+			expected: blockVal(cty.StringVal("hello"), cty.StringVal("petit dejeuner"), cty.StringVal("grande dejeuner")),
+		},
+		{
+			name:     "optional and computed attributes take config values",
+			schema:   blockSchema,
+			prior:    blockVal(cty.StringVal("bonjour"), cty.StringVal("petit dejeuner"), cty.StringVal("grande dejeuner")),
+			config:   blockVal(null, cty.StringVal("breakfast"), null),
+			expected: blockVal(null, cty.StringVal("breakfast"), cty.StringVal("grande dejeuner")),
+		},
+		{
+			name:     "unknown config",
+			schema:   blockSchema,
+			prior:    blockVal(cty.StringVal("bonjour"), null, null),
+			config:   cty.UnknownVal(blockType),
+			expected: cty.UnknownVal(blockType),
+		},
+		{
+			name:   "nested single block",
+			schema: nestedSchema(proto.Schema_NestedBlock_SINGLE),
+			prior: nestedVal(cty.StringVal("a"),
+				blockVal(cty.StringVal("bonjour"), cty.StringVal("petit"), cty.StringVal("grande"))),
+			config: nestedVal(null, blockVal(cty.StringVal("hello"), null, null)),
+			expected: nestedVal(cty.StringVal("a"),
+				blockVal(cty.StringVal("hello"), cty.StringVal("petit"), cty.StringVal("grande"))),
+		},
+		{
+			name:     "nested single block removed",
+			schema:   nestedSchema(proto.Schema_NestedBlock_SINGLE),
+			prior:    nestedVal(cty.StringVal("a"), blockVal(cty.StringVal("bonjour"), null, null)),
+			config:   nestedVal(null, cty.NullVal(blockType)),
+			expected: nestedVal(cty.StringVal("a"), cty.NullVal(blockType)),
+		},
+		{
+			name:     "nested single block without prior",
+			schema:   nestedSchema(proto.Schema_NestedBlock_SINGLE),
+			prior:    cty.NullVal(nestedType(blockType)),
+			config:   nestedVal(null, blockVal(cty.StringVal("hello"), null, null)),
+			expected: nestedVal(null, blockVal(cty.StringVal("hello"), null, null)),
+		},
+		{
+			name:     "nested group block",
+			schema:   nestedSchema(proto.Schema_NestedBlock_GROUP),
+			prior:    nestedVal(cty.StringVal("a"), blockVal(null, cty.StringVal("petit"), cty.StringVal("grande"))),
+			config:   nestedVal(null, blockVal(cty.StringVal("hello"), null, null)),
+			expected: nestedVal(cty.StringVal("a"), blockVal(cty.StringVal("hello"), cty.StringVal("petit"), cty.StringVal("grande"))),
+		},
+		{
+			name:   "nested list blocks are correlated by index",
+			schema: nestedSchema(proto.Schema_NestedBlock_LIST),
+			prior: nestedVal(cty.StringVal("a"), cty.ListVal([]cty.Value{
+				blockVal(cty.StringVal("one"), cty.StringVal("petit"), cty.StringVal("grande")),
+			})),
+			config: nestedVal(null, cty.ListVal([]cty.Value{
+				blockVal(cty.StringVal("uno"), null, null),
+				blockVal(cty.StringVal("dos"), null, null),
+			})),
+			expected: nestedVal(cty.StringVal("a"), cty.ListVal([]cty.Value{
+				blockVal(cty.StringVal("uno"), cty.StringVal("petit"), cty.StringVal("grande")),
+				blockVal(cty.StringVal("dos"), null, null),
+			})),
+		},
+		{
+			name:   "nested list blocks removed",
+			schema: nestedSchema(proto.Schema_NestedBlock_LIST),
+			prior: nestedVal(cty.StringVal("a"), cty.ListVal([]cty.Value{
+				blockVal(cty.StringVal("one"), cty.StringVal("petit"), cty.StringVal("grande")),
+			})),
+			config:   nestedVal(null, cty.NullVal(cty.List(blockType))),
+			expected: nestedVal(cty.StringVal("a"), cty.ListValEmpty(blockType)),
+		},
+		{
+			name:   "nested list blocks of unknown length",
+			schema: nestedSchema(proto.Schema_NestedBlock_LIST),
+			prior: nestedVal(cty.StringVal("a"), cty.ListVal([]cty.Value{
+				blockVal(cty.StringVal("one"), null, null),
+			})),
+			config:   nestedVal(null, cty.UnknownVal(cty.List(blockType))),
+			expected: nestedVal(cty.StringVal("a"), cty.UnknownVal(cty.List(blockType))),
+		},
+		{
+			name:   "nested map blocks are correlated by key",
+			schema: nestedSchema(proto.Schema_NestedBlock_MAP),
+			prior: nestedVal(cty.StringVal("a"), cty.MapVal(map[string]cty.Value{
+				"a": blockVal(cty.StringVal("one"), cty.StringVal("petit"), cty.StringVal("grande")),
+				"b": blockVal(cty.StringVal("two"), cty.StringVal("small"), cty.StringVal("large")),
+			})),
+			config: nestedVal(null, cty.MapVal(map[string]cty.Value{
+				"a": blockVal(cty.StringVal("uno"), null, null),
+				"c": blockVal(cty.StringVal("tres"), null, null),
+			})),
+			expected: nestedVal(cty.StringVal("a"), cty.MapVal(map[string]cty.Value{
+				"a": blockVal(cty.StringVal("uno"), cty.StringVal("petit"), cty.StringVal("grande")),
+				"c": blockVal(cty.StringVal("tres"), null, null),
+			})),
+		},
+		{
+			name:   "nested set blocks are correlated by non-computed values",
+			schema: nestedSchema(proto.Schema_NestedBlock_SET),
+			prior: nestedVal(cty.StringVal("a"), cty.SetVal([]cty.Value{
+				blockVal(cty.StringVal("one"), cty.StringVal("petit"), cty.StringVal("grande")),
+				blockVal(cty.StringVal("two"), cty.StringVal("small"), cty.StringVal("large")),
+			})),
+			config: nestedVal(null, cty.SetVal([]cty.Value{
+				blockVal(cty.StringVal("one"), null, null),
+				blockVal(cty.StringVal("three"), null, null),
+			})),
+			expected: nestedVal(cty.StringVal("a"), cty.SetVal([]cty.Value{
+				blockVal(cty.StringVal("one"), cty.StringVal("petit"), cty.StringVal("grande")),
+				blockVal(cty.StringVal("three"), null, null),
+			})),
+		},
+		{
+			name:   "nested set blocks with optional and computed values set in config",
+			schema: nestedSchema(proto.Schema_NestedBlock_SET),
+			prior: nestedVal(cty.StringVal("a"), cty.SetVal([]cty.Value{
+				blockVal(cty.StringVal("one"), cty.StringVal("petit"), cty.StringVal("grande")),
+			})),
+			config: nestedVal(null, cty.SetVal([]cty.Value{
+				blockVal(cty.StringVal("one"), cty.StringVal("breakfast"), null),
+			})),
+			expected: nestedVal(cty.StringVal("a"), cty.SetVal([]cty.Value{
+				blockVal(cty.StringVal("one"), cty.StringVal("breakfast"), null),
+			})),
+		},
+		{
+			name:     "nested set blocks without prior",
+			schema:   nestedSchema(proto.Schema_NestedBlock_SET),
+			prior:    cty.NullVal(nestedType(cty.Set(blockType))),
+			config:   nestedVal(null, cty.SetValEmpty(blockType)),
+			expected: nestedVal(null, cty.SetValEmpty(blockType)),
+		},
+		{
+			name: "deeply nested set blocks",
+			schema: &proto.Schema_Block{
+				BlockTypes: []*proto.Schema_NestedBlock{
+					testNestedBlock("outer", proto.Schema_NestedBlock_SET, nestedSchema(proto.Schema_NestedBlock_LIST)),
+				},
+			},
+			prior: cty.ObjectVal(map[string]cty.Value{
+				"outer": cty.SetVal([]cty.Value{
+					nestedVal(cty.StringVal("a"), cty.ListVal([]cty.Value{
+						blockVal(cty.StringVal("one"), cty.StringVal("petit"), cty.StringVal("grande")),
+					})),
+				}),
+			}),
+			config: cty.ObjectVal(map[string]cty.Value{
+				"outer": cty.SetVal([]cty.Value{
+					nestedVal(null, cty.ListVal([]cty.Value{
+						blockVal(cty.StringVal("one"), null, null),
+					})),
+				}),
+			}),
+			expected: cty.ObjectVal(map[string]cty.Value{
+				"outer": cty.SetVal([]cty.Value{
+					nestedVal(cty.StringVal("a"), cty.ListVal([]cty.Value{
+						blockVal(cty.StringVal("one"), cty.StringVal("petit"), cty.StringVal("grande")),
+					})),
+				}),
+			}),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := proposedNew(c.schema, c.prior, c.config)
+			assert.True(t, c.expected.RawEquals(actual), "expected %#v, got %#v", c.expected, actual)
+		})
+	}
+}
@@ -10,16 +10,25 @@ import (
 	"github.com/hashicorp/go-cty/cty/msgpack"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/diagnostics"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
 )
 
+var _ = shim.ProviderWithWarnings((*provider)(nil))
+var _ = shim.ProviderWithMeta((*provider)(nil))
+var _ = shim.ProviderWithUnknownCollections((*provider)(nil))
+
 type provider struct {
 	client           proto.ProviderClient
 	terraformVersion string
 
-	resources   resourceMap
-	dataSources resourceMap
-	config      *resource
+	resources    resourceMap
+	dataSources  resourceMap
+	config       *resource
+	providerMeta *resource
+
+	// providerMetaValue is the encoded value of the provider_meta block, if any.
+	providerMetaValue *proto.DynamicValue
 }
 
 func NewProvider(ctx context.Context, client proto.ProviderClient, terraformVersion string) (shim.Provider, error) {
@@ -27,6 +36,9 @@ func NewProvider(ctx context.Context, client proto.ProviderClient, terraformVers
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving schema: %w", err)
 	}
+	if err = unmarshalErrors(schemaResponse.Diagnostics); err != nil {
+		return nil, fmt.Errorf("error retrieving schema: %w", err)
+	}
 
 	// Default to reporting 0.13.2.
 	if terraformVersion == "" {
@@ -53,9 +65,38 @@ func NewProvider(ctx context.Context, client proto.ProviderClient, terraformVers
 		return nil, fmt.Errorf("error unmarshaling provider config: %w", err)
 	}
 
+	if schemaResponse.ProviderMeta != nil && schemaResponse.ProviderMeta.Block != nil {
+		p.providerMeta, err = unmarshalResource(p, "", schemaResponse.ProviderMeta)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling provider meta: %w", err)
+		}
+	}
+
 	return p, nil
 }
 
+// WithProviderMeta returns a copy of the provider that sends the given provider_meta value with each request to plan,
+// apply, or read a resource or to read a data source. If the provider has no provider_meta schema, the value is
+// ignored.
+func (p *provider) WithProviderMeta(meta map[string]interface{}) (shim.Provider, error) {
+	if p.providerMeta == nil {
+		return p, nil
+	}
+
+	val, err := goToCty(meta, p.providerMeta.ctyType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider_meta: %w", err)
+	}
+	bytes, err := msgpack.Marshal(val, p.providerMeta.ctyType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider_meta: %w", err)
+	}
+
+	withMeta := *p
+	withMeta.providerMetaValue = &proto.DynamicValue{Msgpack: bytes}
+	return &withMeta, nil
+}
+
 func (p *provider) decodeState(resource *resource, s *instanceState,
 	val cty.Value, meta map[string]interface{}) (shim.InstanceState, error) {
 
@@ -90,9 +131,11 @@ func (p *provider) decodeState(resource *resource, s *instanceState,
 	return s, nil
 }
 
-func (p *provider) upgradeResourceState(resource *resource, s *instanceState) (*instanceState, error) {
+func (p *provider) upgradeResourceState(resource *resource,
+	s *instanceState) (*instanceState, []*diagnostics.Warning, error) {
+
 	if s == nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	schemaVersion := int64(0)
@@ -100,7 +143,7 @@ func (p *provider) upgradeResourceState(resource *resource, s *instanceState) (*
 		if schemaVersionString, ok := schemaVersionValue.(string); ok {
 			sv, err := strconv.ParseInt(schemaVersionString, 10, 64)
 			if err != nil {
-				return nil, fmt.Errorf("could not parse schema version: %v", err)
+				return nil, nil, fmt.Errorf("could not parse schema version: %v", err)
 			}
 			schemaVersion = sv
 		}
@@ -108,7 +151,7 @@ func (p *provider) upgradeResourceState(resource *resource, s *instanceState) (*
 
 	stateBytes, err := json.Marshal(s.object)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	resp, err := p.client.UpgradeResourceState(context.TODO(), &proto.UpgradeResourceState_Request{
@@ -117,20 +160,21 @@ func (p *provider) upgradeResourceState(resource *resource, s *instanceState) (*
 		RawState: &proto.RawState{Json: stateBytes},
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if err = unmarshalErrors(resp.Diagnostics); err != nil {
-		return nil, err
+	warnings, err := unmarshalDiagnostics(resp.Diagnostics)
+	if err != nil {
+		return nil, warnings, err
 	}
 
 	upgradedVal, err := msgpack.Unmarshal(resp.UpgradedState.Msgpack, resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	upgradedShim, err := p.decodeState(resource, s, upgradedVal, s.meta)
 	upgradedState, _ := upgradedShim.(*instanceState)
-	return upgradedState, err
+	return upgradedState, warnings, err
 }
 
 func (p *provider) importResourceState(t, id string, _ interface{}) ([]shim.InstanceState, error) {
@@ -141,6 +185,9 @@ func (p *provider) importResourceState(t, id string, _ interface{}) ([]shim.Inst
 	if err != nil {
 		return nil, err
 	}
+	if err = unmarshalErrors(resp.Diagnostics); err != nil {
+		return nil, err
+	}
 
 	states := make([]shim.InstanceState, len(resp.ImportedResources))
 	for i, importedResource := range resp.ImportedResources {
@@ -168,7 +215,7 @@ func (p *provider) importResourceState(t, id string, _ interface{}) ([]shim.Inst
 }
 
 func (p *provider) Schema() shim.SchemaMap {
-	return p.config.schema
+	return schemaMap{p.config.schema}
 }
 
 func (p *provider) ResourcesMap() shim.ResourceMap {
@@ -255,14 +302,19 @@ func (p *provider) ValidateDataSource(t string, c shim.ResourceConfig) ([]string
 }
 
 func (p *provider) Configure(c shim.ResourceConfig) error {
+	_, err := p.ConfigureWithWarnings(c)
+	return err
+}
+
+func (p *provider) ConfigureWithWarnings(c shim.ResourceConfig) ([]*diagnostics.Warning, error) {
 	config, ok := c.(resourceConfig)
 	if !ok {
-		return fmt.Errorf("internal error: foreign resource config")
+		return nil, fmt.Errorf("internal error: foreign resource config")
 	}
 
 	val, err := config.marshal(p.config.ctyType)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	resp, err := p.client.Configure(context.TODO(), &proto.Configure_Request{
@@ -270,116 +322,139 @@ func (p *provider) Configure(c shim.ResourceConfig) error {
 		Config:           &proto.DynamicValue{Msgpack: val},
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return unmarshalErrors(resp.Diagnostics)
+	return unmarshalDiagnostics(resp.Diagnostics)
 }
 
 func (p *provider) Diff(t string, s shim.InstanceState, c shim.ResourceConfig) (shim.InstanceDiff, error) {
+	diff, _, err := p.DiffWithWarnings(t, s, c)
+	return diff, err
+}
+
+func (p *provider) DiffWithWarnings(t string, s shim.InstanceState,
+	c shim.ResourceConfig) (shim.InstanceDiff, []*diagnostics.Warning, error) {
+
 	state, ok := s.(*instanceState)
 	if s != nil && !ok {
-		return nil, fmt.Errorf("internal error: foreign resource state")
+		return nil, nil, fmt.Errorf("internal error: foreign resource state")
 	}
 	config, ok := c.(resourceConfig)
 	if !ok {
-		return nil, fmt.Errorf("internal error: foreign resource config")
+		return nil, nil, fmt.Errorf("internal error: foreign resource config")
 	}
 
 	resource, ok := p.resources[t]
 	if !ok {
-		return nil, fmt.Errorf("unknown resource type %v", t)
+		return nil, nil, fmt.Errorf("unknown resource type %v", t)
 	}
 
-	state, err := p.upgradeResourceState(resource, state)
+	state, warnings, err := p.upgradeResourceState(resource, state)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	stateVal, err := goToCty(state.getObject(), resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 	configVal, err := goToCty(config, resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	stateBytes, err := msgpack.Marshal(stateVal, resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 	var metaBytes []byte
 	if state != nil {
 		m, err := json.Marshal(state.meta)
 		if err != nil {
-			return nil, err
+			return nil, warnings, err
 		}
 		metaBytes = m
 	}
 	configBytes, err := msgpack.Marshal(configVal, resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
+	}
+	proposedBytes, err := msgpack.Marshal(proposedNew(resource.block, stateVal, configVal), resource.ctyType)
+	if err != nil {
+		return nil, warnings, err
 	}
 
 	resp, err := p.client.PlanResourceChange(context.TODO(), &proto.PlanResourceChange_Request{
 		TypeName:         resource.resourceType,
 		PriorState:       &proto.DynamicValue{Msgpack: stateBytes},
-		ProposedNewState: &proto.DynamicValue{Msgpack: configBytes},
+		ProposedNewState: &proto.DynamicValue{Msgpack: proposedBytes},
 		Config:           &proto.DynamicValue{Msgpack: configBytes},
 		PriorPrivate:     metaBytes,
+		ProviderMeta:     p.providerMetaValue,
 	})
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
+	}
+	planWarnings, err := unmarshalDiagnostics(resp.Diagnostics)
+	if warnings = append(warnings, planWarnings...); err != nil {
+		return nil, warnings, err
 	}
 
 	plannedVal, err := msgpack.Unmarshal(resp.PlannedState.Msgpack, resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	var plannedMeta map[string]interface{}
 	if err = json.Unmarshal(resp.PlannedPrivate, &plannedMeta); err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
-	return newInstanceDiff(configVal, stateVal, plannedVal, plannedMeta, resp.RequiresReplace), nil
+	return newInstanceDiff(configVal, stateVal, plannedVal, plannedMeta, resp.RequiresReplace), warnings, nil
 }
 
 func (p *provider) Apply(t string, s shim.InstanceState, d shim.InstanceDiff) (shim.InstanceState, error) {
+	state, _, err := p.ApplyWithWarnings(t, s, d)
+	return state, err
+}
+
+func (p *provider) ApplyWithWarnings(t string, s shim.InstanceState,
+	d shim.InstanceDiff) (shim.InstanceState, []*diagnostics.Warning, error) {
+
 	state, ok := s.(*instanceState)
 	if s != nil && !ok {
-		return nil, fmt.Errorf("internal error: foreign resource state")
+		return nil, nil, fmt.Errorf("internal error: foreign resource state")
 	}
 	diff, ok := d.(*instanceDiff)
 	if !ok {
-		return nil, fmt.Errorf("internal error: foreign instance diff")
+		return nil, nil, fmt.Errorf("internal error: foreign instance diff")
 	}
 
 	resource, ok := p.resources[t]
 	if !ok {
-		return nil, fmt.Errorf("unknown resource type %v", t)
+		return nil, nil, fmt.Errorf("unknown resource type %v", t)
 	}
 
-	state, err := p.upgradeResourceState(resource, state)
+	state, warnings, err := p.upgradeResourceState(resource, state)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	stateBytes, err := state.marshal(resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 	if diff.planned == (cty.Value{}) {
 		diff.planned = cty.NullVal(resource.ctyType)
 	}
 	plannedStateBytes, err := msgpack.Marshal(diff.planned, resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 	plannedMetaBytes, err := json.Marshal(diff.meta)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	if diff.config == (cty.Value{}) {
@@ -387,7 +462,7 @@ func (p *provider) Apply(t string, s shim.InstanceState, d shim.InstanceDiff) (s
 	}
 	configBytes, err := msgpack.Marshal(diff.config, resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	resp, err := p.client.ApplyResourceChange(context.TODO(), &proto.ApplyResourceChange_Request{
@@ -396,87 +471,104 @@ func (p *provider) Apply(t string, s shim.InstanceState, d shim.InstanceDiff) (s
 		PlannedState:   &proto.DynamicValue{Msgpack: plannedStateBytes},
 		Config:         &proto.DynamicValue{Msgpack: configBytes},
 		PlannedPrivate: plannedMetaBytes,
+		ProviderMeta:   p.providerMetaValue,
 	})
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
+	applyWarnings, applyErr := unmarshalDiagnostics(resp.Diagnostics)
+	warnings = append(warnings, applyWarnings...)
 
+	// Decode the new state even if the apply failed: it records any partial changes.
+	if resp.NewState == nil {
+		return nil, warnings, applyErr
+	}
 	newStateVal, err := msgpack.Unmarshal(resp.NewState.Msgpack, resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	var newMetaVal map[string]interface{}
 	if len(resp.Private) != 0 {
 		if err = json.Unmarshal(resp.Private, &newMetaVal); err != nil {
-			return nil, err
+			return nil, warnings, err
 		}
 	}
 
 	newState, err := p.decodeState(resource, state, newStateVal, newMetaVal)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
-	return newState, unmarshalErrors(resp.Diagnostics)
+	return newState, warnings, applyErr
 }
 
 func (p *provider) Refresh(t string, s shim.InstanceState) (shim.InstanceState, error) {
+	state, _, err := p.RefreshWithWarnings(t, s)
+	return state, err
+}
+
+func (p *provider) RefreshWithWarnings(t string,
+	s shim.InstanceState) (shim.InstanceState, []*diagnostics.Warning, error) {
+
 	state, ok := s.(*instanceState)
 	if s != nil && !ok {
-		return nil, fmt.Errorf("internal error: foreign resource state")
+		return nil, nil, fmt.Errorf("internal error: foreign resource state")
 	}
 
 	resource, ok := p.resources[t]
 	if !ok {
-		return nil, fmt.Errorf("unknown resource type %v", t)
+		return nil, nil, fmt.Errorf("unknown resource type %v", t)
 	}
 
-	state, err := p.upgradeResourceState(resource, state)
+	state, warnings, err := p.upgradeResourceState(resource, state)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	stateBytes, err := state.marshal(resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 	metaBytes, err := json.Marshal(state.meta)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	resp, err := p.client.ReadResource(context.TODO(), &proto.ReadResource_Request{
 		TypeName:     resource.resourceType,
 		CurrentState: &proto.DynamicValue{Msgpack: stateBytes},
 		Private:      metaBytes,
+		ProviderMeta: p.providerMetaValue,
 	})
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
+	readWarnings, readErr := unmarshalDiagnostics(resp.Diagnostics)
+	warnings = append(warnings, readWarnings...)
 
 	if resp.NewState == nil {
-		return nil, unmarshalErrors(resp.Diagnostics)
+		return nil, warnings, readErr
 	}
 
 	newStateVal, err := msgpack.Unmarshal(resp.NewState.Msgpack, resource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
 	var newMetaVal map[string]interface{}
 	if len(resp.Private) != 0 {
 		if err = json.Unmarshal(resp.Private, &newMetaVal); err != nil {
-			return nil, err
+			return nil, warnings, err
 		}
 	}
 
 	newState, err := p.decodeState(resource, state, newStateVal, newMetaVal)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
-	return newState, unmarshalErrors(resp.Diagnostics)
+	return newState, warnings, readErr
 }
 
 func (p *provider) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceDiff, error) {
@@ -494,35 +586,48 @@ func (p *provider) ReadDataDiff(t string, c shim.ResourceConfig) (shim.InstanceD
 }
 
 func (p *provider) ReadDataApply(t string, d shim.InstanceDiff) (shim.InstanceState, error) {
+	state, _, err := p.ReadDataApplyWithWarnings(t, d)
+	return state, err
+}
+
+func (p *provider) ReadDataApplyWithWarnings(t string,
+	d shim.InstanceDiff) (shim.InstanceState, []*diagnostics.Warning, error) {
+
 	diff, ok := d.(*instanceDiff)
 	if d != nil && !ok {
-		return nil, fmt.Errorf("internal error: foreign instance diff")
+		return nil, nil, fmt.Errorf("internal error: foreign instance diff")
 	}
 
 	dataSource, ok := p.dataSources[t]
 	if !ok {
-		return nil, fmt.Errorf("unknown data source %v", t)
+		return nil, nil, fmt.Errorf("unknown data source %v", t)
 	}
 
 	configBytes, err := msgpack.Marshal(diff.planned, dataSource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	resp, err := p.client.ReadDataSource(context.TODO(), &proto.ReadDataSource_Request{
-		TypeName: t,
-		Config:   &proto.DynamicValue{Msgpack: configBytes},
+		TypeName:     t,
+		Config:       &proto.DynamicValue{Msgpack: configBytes},
+		ProviderMeta: p.providerMetaValue,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	warnings, err := unmarshalDiagnostics(resp.Diagnostics)
+	if err != nil {
+		return nil, warnings, err
 	}
 
 	stateVal, err := msgpack.Unmarshal(resp.State.Msgpack, dataSource.ctyType)
 	if err != nil {
-		return nil, err
+		return nil, warnings, err
 	}
 
-	return p.decodeState(dataSource, nil, stateVal, nil)
+	state, err := p.decodeState(dataSource, nil, stateVal, nil)
+	return state, warnings, err
 }
 
 func (p *provider) Meta() interface{} {
@@ -541,6 +646,11 @@ func (p *provider) Stop() error {
 	}
 }
 
+// SupportsUnknownCollections returns true: unknown collections are sent to the provider as cty unknowns.
+func (p *provider) SupportsUnknownCollections() bool {
+	return true
+}
+
 func (p *provider) InitLogging() {
 	// Nothing to do.
 }
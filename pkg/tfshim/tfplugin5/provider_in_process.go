@@ -0,0 +1,67 @@
+package tfplugin5
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
+)
+
+const (
+	// inProcessBufferSize is the size of the in-memory connection's buffer.
+	inProcessBufferSize = 1 << 20
+
+	// inProcessMaxMessageSize matches the maximum message size of servers that use terraform-plugin-go, which is
+	// raised from gRPC's 4MB default to accommodate large schemas and states.
+	inProcessMaxMessageSize = 256 << 20
+)
+
+// ServeProvider serves a terraform-plugin-go provider server over gRPC in-process and returns a shim.Provider that
+// communicates with it over an in-memory connection. The server is stopped when the context is done.
+func ServeProvider(ctx context.Context, providerServer tfprotov5.ProviderServer,
+	terraformVersion string) (shim.Provider, error) {
+
+	server := grpc.NewServer(grpc.MaxRecvMsgSize(inProcessMaxMessageSize), grpc.MaxSendMsgSize(inProcessMaxMessageSize))
+	proto.RegisterProviderServer(server, &serverV5{server: providerServer})
+
+	listener := bufconn.Listen(inProcessBufferSize)
+	go func() {
+		contract.IgnoreError(server.Serve(listener))
+	}()
+
+	conn, err := grpc.DialContext(ctx, "in-process",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(inProcessMaxMessageSize),
+			grpc.MaxCallSendMsgSize(inProcessMaxMessageSize)))
+	if err != nil {
+		server.Stop()
+		return nil, fmt.Errorf("error connecting to provider: %w", err)
+	}
+	stop := func() {
+		contract.IgnoreError(conn.Close())
+		server.Stop()
+	}
+
+	p, err := NewProvider(ctx, proto.NewProviderClient(conn), terraformVersion)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return p, nil
+}
@@ -2,12 +2,17 @@ package tfplugin5
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"google.golang.org/grpc"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
@@ -36,44 +41,228 @@ func (p *providerPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) e
 	return fmt.Errorf("unsupported")
 }
 
-func StartProvider(ctx context.Context, executablePath, terraformVersion string) (shim.Provider, error) {
-	var logger hclog.Logger
+// providerClientPlugin dispenses the raw gRPC client for a provider plugin.
+type providerClientPlugin struct {
+	plugin.Plugin
+}
+
+func (p *providerClientPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker,
+	c *grpc.ClientConn) (interface{}, error) {
+
+	return proto.NewProviderClient(c), nil
+}
+
+func (p *providerClientPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	return fmt.Errorf("unsupported")
+}
+
+// ReattachProvidersEnvVar is the environment variable that lists providers that are already running, e.g. under a
+// debugger. Its value uses the format of Terraform's variable of the same name: a JSON object that maps provider
+// addresses to reattach configurations.
+const ReattachProvidersEnvVar = "TF_REATTACH_PROVIDERS"
+
+// reattachConfig is the JSON representation of a go-plugin ReattachConfig used by TF_REATTACH_PROVIDERS.
+type reattachConfig struct {
+	Protocol        string
+	ProtocolVersion int
+	Pid             int
+	Test            bool
+	Addr            struct {
+		Network string
+		String  string
+	}
+}
+
+// ParseReattachProviders parses the value of TF_REATTACH_PROVIDERS into a map from provider addresses to reattach
+// configurations.
+func ParseReattachProviders(value string) (map[string]*plugin.ReattachConfig, error) {
+	var configs map[string]reattachConfig
+	if err := json.Unmarshal([]byte(value), &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", ReattachProvidersEnvVar, err)
+	}
+
+	result := map[string]*plugin.ReattachConfig{}
+	for name, c := range configs {
+		var addr net.Addr
+		var err error
+		switch c.Addr.Network {
+		case "unix":
+			addr, err = net.ResolveUnixAddr("unix", c.Addr.String)
+		case "tcp":
+			addr, err = net.ResolveTCPAddr("tcp", c.Addr.String)
+		default:
+			err = fmt.Errorf("unknown address type %q", c.Addr.Network)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid address for provider %v: %w", name, err)
+		}
+
+		result[name] = &plugin.ReattachConfig{
+			Protocol:        plugin.Protocol(c.Protocol),
+			ProtocolVersion: c.ProtocolVersion,
+			Pid:             c.Pid,
+			Test:            c.Test,
+			Addr:            addr,
+		}
+	}
+	return result, nil
+}
+
+// ReattachConfigFromEnv returns the reattach configuration for the named provider from TF_REATTACH_PROVIDERS, if any.
+// The name may be a full provider address (e.g. registry.terraform.io/hashicorp/aws) or just the provider's type name
+// (e.g. aws), which matches the last component of an address.
+func ReattachConfigFromEnv(name string) (*plugin.ReattachConfig, bool, error) {
+	value := os.Getenv(ReattachProvidersEnvVar)
+	if value == "" {
+		return nil, false, nil
+	}
+	configs, err := ParseReattachProviders(value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if config, ok := configs[name]; ok {
+		return config, true, nil
+	}
+	for address, config := range configs {
+		if address[strings.LastIndex(address, "/")+1:] == name {
+			return config, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// providerTypeName returns the type name of the provider in the given executable. Executables are named
+// terraform-provider-<name>, optionally followed by a version suffix (e.g. terraform-provider-aws_v4.0.0).
+func providerTypeName(executablePath string) string {
+	name := strings.TrimSuffix(filepath.Base(executablePath), filepath.Ext(executablePath))
+	name = strings.TrimPrefix(name, "terraform-provider-")
+	if i := strings.Index(name, "_"); i != -1 {
+		name = name[:i]
+	}
+	return name
+}
+
+func pluginLogger() hclog.Logger {
 	switch os.Getenv("TF_LOG") {
 	case "TRACE":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Trace})
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Trace})
 	case "DEBUG":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Debug})
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Debug})
 	case "INFO":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Info})
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Info})
 	case "WARN":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Warn})
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Warn})
 	case "ERROR":
-		logger = hclog.New(&hclog.LoggerOptions{Level: hclog.Error})
+		return hclog.New(&hclog.LoggerOptions{Level: hclog.Error})
 	default:
-		logger = hclog.NewNullLogger()
+		return hclog.NewNullLogger()
 	}
+}
 
-	pluginClient := plugin.NewClient(&plugin.ClientConfig{
-		HandshakeConfig:  Handshake,
-		Plugins:          plugin.PluginSet{"provider": &providerPlugin{terraformVersion: terraformVersion}},
-		Cmd:              exec.Command(executablePath),
-		Managed:          true,
-		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
-		AutoMTLS:         true,
-		Logger:           logger,
-	})
+func dispenseProvider(pluginClient *plugin.Client) (shim.Provider, error) {
+	client, err := pluginClient.Client()
+	if err != nil {
+		return nil, err
+	}
+	provider, err := client.Dispense("provider")
+	if err != nil {
+		return nil, err
+	}
+	return provider.(shim.Provider), nil
+}
+
+// StartProvider launches the provider plugin at the given path and returns a shim.Provider that communicates with it.
+// The plugin is killed when the context is done.
+//
+// If TF_REATTACH_PROVIDERS lists the provider, StartProvider connects to the running provider instead of launching a
+// new process. Providers are matched by the type name derived from the executable's name.
+func StartProvider(ctx context.Context, executablePath, terraformVersion string) (shim.Provider, error) {
+	config, ok, err := ReattachConfigFromEnv(providerTypeName(executablePath))
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return ReattachProvider(ctx, config, terraformVersion)
+	}
+
+	pluginClient := newPluginClient(executablePath, &providerPlugin{terraformVersion: terraformVersion})
 	go func() {
 		<-ctx.Done()
 		pluginClient.Kill()
 	}()
 
-	client, err := pluginClient.Client()
+	return dispenseProvider(pluginClient)
+}
+
+// StartSupervisedProvider launches the provider plugin at the given path like StartProvider, but supervises the plugin
+// process: if the process exits unexpectedly, it is restarted and reconfigured with the last provider configuration,
+// and requests that do not change resources are retried. The options can also run a pool of plugin processes.
+//
+// Providers listed in TF_REATTACH_PROVIDERS are not supervised.
+func StartSupervisedProvider(ctx context.Context, executablePath, terraformVersion string,
+	opts SupervisorOptions) (shim.Provider, error) {
+
+	config, ok, err := ReattachConfigFromEnv(providerTypeName(executablePath))
 	if err != nil {
 		return nil, err
 	}
-	provider, err := client.Dispense("provider")
+	if ok {
+		return ReattachProvider(ctx, config, terraformVersion)
+	}
+
+	client, err := newSupervisor(ctx, func() (pluginProcess, proto.ProviderClient, error) {
+		pluginClient := newPluginClient(executablePath, &providerClientPlugin{})
+		client, err := pluginClient.Client()
+		if err != nil {
+			pluginClient.Kill()
+			return nil, nil, err
+		}
+		provider, err := client.Dispense("provider")
+		if err != nil {
+			pluginClient.Kill()
+			return nil, nil, err
+		}
+		return pluginClient, provider.(proto.ProviderClient), nil
+	}, opts)
 	if err != nil {
 		return nil, err
 	}
-	return provider.(shim.Provider), nil
+	return NewProvider(ctx, client, terraformVersion)
+}
+
+// newPluginClient returns a client that launches the provider plugin at the given path.
+func newPluginClient(executablePath string, p plugin.Plugin) *plugin.Client {
+	return plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          plugin.PluginSet{"provider": p},
+		Cmd:              exec.Command(executablePath),
+		Managed:          true,
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		AutoMTLS:         true,
+		Logger:           pluginLogger(),
+	})
+}
+
+// ReattachProvider connects to an already-running provider plugin, e.g. one that was started under a debugger, and
+// returns a shim.Provider that communicates with it. The connection is closed when the context is done, but the
+// provider process is left running.
+func ReattachProvider(ctx context.Context, config *plugin.ReattachConfig,
+	terraformVersion string) (shim.Provider, error) {
+
+	pluginClient := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          plugin.PluginSet{"provider": &providerPlugin{terraformVersion: terraformVersion}},
+		Reattach:         config,
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		Logger:           pluginLogger(),
+	})
+	go func() {
+		<-ctx.Done()
+		if client, err := pluginClient.Client(); err == nil {
+			contract.IgnoreError(client.Close())
+		}
+	}()
+
+	return dispenseProvider(pluginClient)
 }
@@ -0,0 +1,116 @@
+package tfplugin5
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReattachProviders(t *testing.T) {
+	configs, err := ParseReattachProviders(`{
+		"registry.terraform.io/hashicorp/aws": {
+			"Protocol": "grpc",
+			"ProtocolVersion": 5,
+			"Pid": 1234,
+			"Test": true,
+			"Addr": {"Network": "unix", "String": "/tmp/plugin123"}
+		},
+		"example": {
+			"Protocol": "grpc",
+			"ProtocolVersion": 5,
+			"Pid": 5678,
+			"Addr": {"Network": "tcp", "String": "127.0.0.1:4321"}
+		}
+	}`)
+	require.NoError(t, err)
+
+	aws := configs["registry.terraform.io/hashicorp/aws"]
+	require.NotNil(t, aws)
+	assert.Equal(t, goplugin.ProtocolGRPC, aws.Protocol)
+	assert.Equal(t, 5, aws.ProtocolVersion)
+	assert.Equal(t, 1234, aws.Pid)
+	assert.True(t, aws.Test)
+	assert.Equal(t, "unix", aws.Addr.Network())
+	assert.Equal(t, "/tmp/plugin123", aws.Addr.String())
+
+	example := configs["example"]
+	require.NotNil(t, example)
+	assert.False(t, example.Test)
+	assert.Equal(t, "tcp", example.Addr.Network())
+	assert.Equal(t, "127.0.0.1:4321", example.Addr.String())
+
+	_, err = ParseReattachProviders(`{"aws": {"Addr": {"Network": "pipe", "String": "foo"}}}`)
+	assert.Error(t, err)
+	_, err = ParseReattachProviders(`not json`)
+	assert.Error(t, err)
+}
+
+func TestReattachConfigFromEnv(t *testing.T) {
+	t.Setenv(ReattachProvidersEnvVar, `{
+		"registry.terraform.io/hashicorp/aws": {"Addr": {"Network": "unix", "String": "/tmp/aws"}},
+		"random": {"Addr": {"Network": "unix", "String": "/tmp/random"}}
+	}`)
+
+	cases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "registry.terraform.io/hashicorp/aws", expected: "/tmp/aws"},
+		{name: "aws", expected: "/tmp/aws"},
+		{name: "random", expected: "/tmp/random"},
+		{name: "google"},
+	}
+	for _, c := range cases {
+		config, ok, err := ReattachConfigFromEnv(c.name)
+		require.NoError(t, err)
+		if c.expected == "" {
+			assert.False(t, ok)
+			continue
+		}
+		require.True(t, ok)
+		assert.Equal(t, c.expected, config.Addr.String())
+	}
+
+	t.Setenv(ReattachProvidersEnvVar, "")
+	_, ok, err := ReattachConfigFromEnv("aws")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestProviderTypeName(t *testing.T) {
+	assert.Equal(t, "aws", providerTypeName("/plugins/terraform-provider-aws"))
+	assert.Equal(t, "aws", providerTypeName("/plugins/terraform-provider-aws_v4.0.0_x5"))
+	assert.Equal(t, "aws", providerTypeName(`terraform-provider-aws.exe`))
+}
+
+func TestReattachProvider(t *testing.T) {
+	testProviderPath, err := exec.LookPath("pulumi-terraform-bridge-test-provider")
+	require.NoError(t, err)
+
+	// Launch the provider without mTLS so that another client can attach to it.
+	pluginClient := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  Handshake,
+		Plugins:          goplugin.PluginSet{"provider": &providerPlugin{}},
+		Cmd:              exec.Command(testProviderPath),
+		Managed:          true,
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		Logger:           hclog.NewNullLogger(),
+	})
+	t.Cleanup(pluginClient.Kill)
+	_, err = pluginClient.Start()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := ReattachProvider(ctx, pluginClient.ReattachConfig(), "")
+	require.NoError(t, err)
+
+	_, ok := p.ResourcesMap().GetOk("example_resource")
+	assert.True(t, ok)
+}
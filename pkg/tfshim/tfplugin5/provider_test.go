@@ -891,6 +891,7 @@ func TestDiff(t *testing.T) {
 	cases := []struct {
 		state      map[string]interface{}
 		config     map[string]interface{}
+		planned    map[string]interface{} // the planned values that differ from the config, if any
 		attributes map[string]shim.ResourceAttrDiff
 	}{
 		{
@@ -951,6 +952,24 @@ func TestDiff(t *testing.T) {
 				"array_property_value": []interface{}{"foo"},
 				"set_property_value":   []interface{}{UnknownVariableValue},
 			},
+			// The provider plans a set with unknown elements as wholly unknown.
+			planned: map[string]interface{}{
+				"set_property_value": UnknownVariableValue,
+			},
+			attributes: map[string]shim.ResourceAttrDiff{
+				"set_property_value.#": update("1", UnknownVariableValue, true),
+			},
+		},
+		{
+			// A wholly unknown set may have any number of elements.
+			state: map[string]interface{}{
+				"array_property_value": []interface{}{"foo"},
+				"set_property_value":   []interface{}{"bar"},
+			},
+			config: map[string]interface{}{
+				"array_property_value": []interface{}{"foo"},
+				"set_property_value":   UnknownVariableValue,
+			},
 			attributes: map[string]shim.ResourceAttrDiff{
 				"set_property_value.#": update("1", UnknownVariableValue, true),
 			},
@@ -1008,6 +1027,11 @@ func TestDiff(t *testing.T) {
 				require.NoError(t, err)
 				expected[k] = val
 			}
+			for k, v := range c.planned {
+				val, err := goToCty(v, expected[k].Type())
+				require.NoError(t, err)
+				expected[k] = val
+			}
 
 			requiresNew := false
 			for _, d := range c.attributes {
@@ -1020,11 +1044,7 @@ func TestDiff(t *testing.T) {
 			state, err := res.InstanceState("0", c.state, nil)
 			require.NoError(t, err)
 
-			config := p.NewResourceConfig(c.config)
-			configVal, err := goToCty(config, res.(*resource).ctyType)
-			require.NoError(t, err)
-
-			diff, err := p.Diff("example_resource", state, config)
+			diff, err := p.Diff("example_resource", state, p.NewResourceConfig(c.config))
 			require.NoError(t, err)
 
 			var meta map[string]interface{}
@@ -1037,13 +1057,18 @@ func TestDiff(t *testing.T) {
 				}
 			}
 
-			assert.Equal(t, &instanceDiff{
-				config:      configVal,
-				planned:     cty.ObjectVal(expected),
-				attributes:  c.attributes,
-				requiresNew: requiresNew,
-				meta:        meta,
-			}, diff)
+			assert.Equal(t, c.attributes, diff.Attributes())
+			assert.Equal(t, requiresNew, diff.RequiresNew())
+			assert.False(t, diff.Destroy())
+
+			expectedObject, err := ctyToGo(cty.ObjectVal(expected))
+			require.NoError(t, err)
+			proposed, err := diff.ProposedState(res, state)
+			require.NoError(t, err)
+			object, err := proposed.Object(res.Schema())
+			require.NoError(t, err)
+			assert.Equal(t, expectedObject, object)
+			assert.Equal(t, meta, proposed.Meta())
 		})
 	}
 }
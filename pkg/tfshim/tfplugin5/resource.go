@@ -7,22 +7,25 @@ import (
 	"github.com/hashicorp/go-cty/cty"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
 )
 
 var _ = shim.Resource((*resource)(nil))
 var _ = shim.ResourceMap(resourceMap{})
+var _ = shim.SchemaMapWithUnknownCollections(schemaMap{})
 
 type resource struct {
 	provider *provider
 
 	resourceType  string
 	ctyType       cty.Type
+	block         *proto.Schema_Block
 	schema        schema.SchemaMap
 	schemaVersion int
 }
 
 func (r *resource) Schema() shim.SchemaMap {
-	return r.schema
+	return schemaMap{r.schema}
 }
 
 func (r *resource) SchemaVersion() int {
@@ -141,3 +144,13 @@ func (m resourceMap) Range(each func(key string, value shim.Resource) bool) {
 func (m resourceMap) Set(key string, value shim.Resource) {
 	m[key] = value.(*resource)
 }
+
+// schemaMap is the schema of a provider's config or of one of its resources or data sources. Like the provider, it
+// supports unknown collections.
+type schemaMap struct {
+	schema.SchemaMap
+}
+
+func (m schemaMap) SupportsUnknownCollections() bool {
+	return true
+}
@@ -0,0 +1,374 @@
+package tfplugin5
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
+)
+
+// serverV5 adapts a terraform-plugin-go tfprotov5.ProviderServer to the tfplugin5 gRPC service. This stands in for
+// terraform-plugin-go's own gRPC server, whose generated protocol types conflict with those of this package.
+type serverV5 struct {
+	proto.UnimplementedProviderServer
+
+	server tfprotov5.ProviderServer
+}
+
+func dynamicValueFromProto(v *proto.DynamicValue) *tfprotov5.DynamicValue {
+	if v == nil {
+		return nil
+	}
+	return &tfprotov5.DynamicValue{MsgPack: v.Msgpack, JSON: v.Json}
+}
+
+func dynamicValueToProto(v *tfprotov5.DynamicValue) *proto.DynamicValue {
+	if v == nil {
+		return nil
+	}
+	return &proto.DynamicValue{Msgpack: v.MsgPack, Json: v.JSON}
+}
+
+func rawStateFromProto(s *proto.RawState) *tfprotov5.RawState {
+	if s == nil {
+		return nil
+	}
+	return &tfprotov5.RawState{JSON: s.Json, Flatmap: s.Flatmap}
+}
+
+func attributePathToProto(path *tftypes.AttributePath) *proto.AttributePath {
+	if path == nil {
+		return nil
+	}
+	var steps []*proto.AttributePath_Step
+	for _, step := range path.Steps() {
+		switch step := step.(type) {
+		case tftypes.AttributeName:
+			steps = append(steps, &proto.AttributePath_Step{
+				Selector: &proto.AttributePath_Step_AttributeName{AttributeName: string(step)},
+			})
+		case tftypes.ElementKeyString:
+			steps = append(steps, &proto.AttributePath_Step{
+				Selector: &proto.AttributePath_Step_ElementKeyString{ElementKeyString: string(step)},
+			})
+		case tftypes.ElementKeyInt:
+			steps = append(steps, &proto.AttributePath_Step{
+				Selector: &proto.AttributePath_Step_ElementKeyInt{ElementKeyInt: int64(step)},
+			})
+		default:
+			// The protocol cannot address set elements, so return the prefix of the path that it can represent.
+			return &proto.AttributePath{Steps: steps}
+		}
+	}
+	return &proto.AttributePath{Steps: steps}
+}
+
+func attributePathsToProto(paths []*tftypes.AttributePath) []*proto.AttributePath {
+	result := make([]*proto.AttributePath, len(paths))
+	for i, path := range paths {
+		result[i] = attributePathToProto(path)
+	}
+	return result
+}
+
+func diagnosticsToProto(diags []*tfprotov5.Diagnostic) []*proto.Diagnostic {
+	result := make([]*proto.Diagnostic, 0, len(diags))
+	for _, d := range diags {
+		if d == nil {
+			continue
+		}
+		result = append(result, &proto.Diagnostic{
+			Severity:  proto.Diagnostic_Severity(d.Severity),
+			Summary:   d.Summary,
+			Detail:    d.Detail,
+			Attribute: attributePathToProto(d.Attribute),
+		})
+	}
+	return result
+}
+
+func schemaToProto(s *tfprotov5.Schema) (*proto.Schema, error) {
+	if s == nil {
+		return nil, nil
+	}
+	block, err := schemaBlockToProto(s.Block)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.Schema{Version: s.Version, Block: block}, nil
+}
+
+func schemaBlockToProto(b *tfprotov5.SchemaBlock) (*proto.Schema_Block, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	block := &proto.Schema_Block{
+		Version:         b.Version,
+		Description:     b.Description,
+		DescriptionKind: proto.StringKind(b.DescriptionKind),
+		Deprecated:      b.Deprecated,
+	}
+	for _, a := range b.Attributes {
+		ty, err := a.Type.MarshalJSON() //nolint:staticcheck
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling type of attribute %q: %w", a.Name, err)
+		}
+		block.Attributes = append(block.Attributes, &proto.Schema_Attribute{
+			Name:            a.Name,
+			Type:            ty,
+			Description:     a.Description,
+			Required:        a.Required,
+			Optional:        a.Optional,
+			Computed:        a.Computed,
+			Sensitive:       a.Sensitive,
+			DescriptionKind: proto.StringKind(a.DescriptionKind),
+			Deprecated:      a.Deprecated,
+		})
+	}
+	for _, nb := range b.BlockTypes {
+		nested, err := schemaBlockToProto(nb.Block)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling block %q: %w", nb.TypeName, err)
+		}
+		block.BlockTypes = append(block.BlockTypes, &proto.Schema_NestedBlock{
+			TypeName: nb.TypeName,
+			Block:    nested,
+			Nesting:  proto.Schema_NestedBlock_NestingMode(nb.Nesting),
+			MinItems: nb.MinItems,
+			MaxItems: nb.MaxItems,
+		})
+	}
+	return block, nil
+}
+
+func schemasToProto(schemas map[string]*tfprotov5.Schema) (map[string]*proto.Schema, error) {
+	result := make(map[string]*proto.Schema, len(schemas))
+	for name, s := range schemas {
+		schema, err := schemaToProto(s)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling schema for %q: %w", name, err)
+		}
+		result[name] = schema
+	}
+	return result, nil
+}
+
+func (s *serverV5) GetSchema(ctx context.Context,
+	req *proto.GetProviderSchema_Request) (*proto.GetProviderSchema_Response, error) {
+
+	resp, err := s.server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := schemaToProto(resp.Provider)
+	if err != nil {
+		return nil, err
+	}
+	providerMeta, err := schemaToProto(resp.ProviderMeta)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := schemasToProto(resp.ResourceSchemas)
+	if err != nil {
+		return nil, err
+	}
+	dataSources, err := schemasToProto(resp.DataSourceSchemas)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.GetProviderSchema_Response{
+		Provider:          provider,
+		ProviderMeta:      providerMeta,
+		ResourceSchemas:   resources,
+		DataSourceSchemas: dataSources,
+		Diagnostics:       diagnosticsToProto(resp.Diagnostics),
+	}, nil
+}
+
+func (s *serverV5) PrepareProviderConfig(ctx context.Context,
+	req *proto.PrepareProviderConfig_Request) (*proto.PrepareProviderConfig_Response, error) {
+
+	resp, err := s.server.PrepareProviderConfig(ctx, &tfprotov5.PrepareProviderConfigRequest{
+		Config: dynamicValueFromProto(req.Config),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.PrepareProviderConfig_Response{
+		PreparedConfig: dynamicValueToProto(resp.PreparedConfig),
+		Diagnostics:    diagnosticsToProto(resp.Diagnostics),
+	}, nil
+}
+
+func (s *serverV5) ValidateResourceTypeConfig(ctx context.Context,
+	req *proto.ValidateResourceTypeConfig_Request) (*proto.ValidateResourceTypeConfig_Response, error) {
+
+	resp, err := s.server.ValidateResourceTypeConfig(ctx, &tfprotov5.ValidateResourceTypeConfigRequest{
+		TypeName: req.TypeName,
+		Config:   dynamicValueFromProto(req.Config),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ValidateResourceTypeConfig_Response{Diagnostics: diagnosticsToProto(resp.Diagnostics)}, nil
+}
+
+func (s *serverV5) ValidateDataSourceConfig(ctx context.Context,
+	req *proto.ValidateDataSourceConfig_Request) (*proto.ValidateDataSourceConfig_Response, error) {
+
+	resp, err := s.server.ValidateDataSourceConfig(ctx, &tfprotov5.ValidateDataSourceConfigRequest{
+		TypeName: req.TypeName,
+		Config:   dynamicValueFromProto(req.Config),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ValidateDataSourceConfig_Response{Diagnostics: diagnosticsToProto(resp.Diagnostics)}, nil
+}
+
+func (s *serverV5) UpgradeResourceState(ctx context.Context,
+	req *proto.UpgradeResourceState_Request) (*proto.UpgradeResourceState_Response, error) {
+
+	resp, err := s.server.UpgradeResourceState(ctx, &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: req.TypeName,
+		Version:  req.Version,
+		RawState: rawStateFromProto(req.RawState),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.UpgradeResourceState_Response{
+		UpgradedState: dynamicValueToProto(resp.UpgradedState),
+		Diagnostics:   diagnosticsToProto(resp.Diagnostics),
+	}, nil
+}
+
+func (s *serverV5) Configure(ctx context.Context, req *proto.Configure_Request) (*proto.Configure_Response, error) {
+	resp, err := s.server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion: req.TerraformVersion,
+		Config:           dynamicValueFromProto(req.Config),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.Configure_Response{Diagnostics: diagnosticsToProto(resp.Diagnostics)}, nil
+}
+
+func (s *serverV5) ReadResource(ctx context.Context,
+	req *proto.ReadResource_Request) (*proto.ReadResource_Response, error) {
+
+	resp, err := s.server.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		TypeName:     req.TypeName,
+		CurrentState: dynamicValueFromProto(req.CurrentState),
+		Private:      req.Private,
+		ProviderMeta: dynamicValueFromProto(req.ProviderMeta),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ReadResource_Response{
+		NewState:    dynamicValueToProto(resp.NewState),
+		Diagnostics: diagnosticsToProto(resp.Diagnostics),
+		Private:     resp.Private,
+	}, nil
+}
+
+func (s *serverV5) PlanResourceChange(ctx context.Context,
+	req *proto.PlanResourceChange_Request) (*proto.PlanResourceChange_Response, error) {
+
+	resp, err := s.server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         req.TypeName,
+		PriorState:       dynamicValueFromProto(req.PriorState),
+		ProposedNewState: dynamicValueFromProto(req.ProposedNewState),
+		Config:           dynamicValueFromProto(req.Config),
+		PriorPrivate:     req.PriorPrivate,
+		ProviderMeta:     dynamicValueFromProto(req.ProviderMeta),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.PlanResourceChange_Response{
+		PlannedState:     dynamicValueToProto(resp.PlannedState),
+		RequiresReplace:  attributePathsToProto(resp.RequiresReplace),
+		PlannedPrivate:   resp.PlannedPrivate,
+		Diagnostics:      diagnosticsToProto(resp.Diagnostics),
+		LegacyTypeSystem: resp.UnsafeToUseLegacyTypeSystem, //nolint:staticcheck
+	}, nil
+}
+
+func (s *serverV5) ApplyResourceChange(ctx context.Context,
+	req *proto.ApplyResourceChange_Request) (*proto.ApplyResourceChange_Response, error) {
+
+	resp, err := s.server.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       req.TypeName,
+		PriorState:     dynamicValueFromProto(req.PriorState),
+		PlannedState:   dynamicValueFromProto(req.PlannedState),
+		Config:         dynamicValueFromProto(req.Config),
+		PlannedPrivate: req.PlannedPrivate,
+		ProviderMeta:   dynamicValueFromProto(req.ProviderMeta),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ApplyResourceChange_Response{
+		NewState:         dynamicValueToProto(resp.NewState),
+		Private:          resp.Private,
+		Diagnostics:      diagnosticsToProto(resp.Diagnostics),
+		LegacyTypeSystem: resp.UnsafeToUseLegacyTypeSystem, //nolint:staticcheck
+	}, nil
+}
+
+func (s *serverV5) ImportResourceState(ctx context.Context,
+	req *proto.ImportResourceState_Request) (*proto.ImportResourceState_Response, error) {
+
+	resp, err := s.server.ImportResourceState(ctx, &tfprotov5.ImportResourceStateRequest{
+		TypeName: req.TypeName,
+		ID:       req.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make([]*proto.ImportResourceState_ImportedResource, len(resp.ImportedResources))
+	for i, r := range resp.ImportedResources {
+		imported[i] = &proto.ImportResourceState_ImportedResource{
+			TypeName: r.TypeName,
+			State:    dynamicValueToProto(r.State),
+			Private:  r.Private,
+		}
+	}
+	return &proto.ImportResourceState_Response{
+		ImportedResources: imported,
+		Diagnostics:       diagnosticsToProto(resp.Diagnostics),
+	}, nil
+}
+
+func (s *serverV5) ReadDataSource(ctx context.Context,
+	req *proto.ReadDataSource_Request) (*proto.ReadDataSource_Response, error) {
+
+	resp, err := s.server.ReadDataSource(ctx, &tfprotov5.ReadDataSourceRequest{
+		TypeName:     req.TypeName,
+		Config:       dynamicValueFromProto(req.Config),
+		ProviderMeta: dynamicValueFromProto(req.ProviderMeta),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ReadDataSource_Response{
+		State:       dynamicValueToProto(resp.State),
+		Diagnostics: diagnosticsToProto(resp.Diagnostics),
+	}, nil
+}
+
+func (s *serverV5) Stop(ctx context.Context, req *proto.Stop_Request) (*proto.Stop_Response, error) {
+	resp, err := s.server.StopProvider(ctx, &tfprotov5.StopProviderRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.Stop_Response{Error: resp.Error}, nil
+}
@@ -0,0 +1,357 @@
+package tfplugin5
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
+)
+
+// defaultMaxRestarts is the number of times a supervised plugin process is restarted if SupervisorOptions does not
+// say otherwise.
+const defaultMaxRestarts = 3
+
+// SupervisorOptions configures how StartSupervisedProvider runs a provider plugin.
+type SupervisorOptions struct {
+	// PoolSize is the number of plugin processes to run. Each request is sent to the process with the fewest requests
+	// in flight. Defaults to 1.
+	PoolSize int
+	// MaxRestarts is the number of times each plugin process may be restarted after it exits unexpectedly. Defaults to
+	// 3. A negative value disables restarts.
+	MaxRestarts int
+}
+
+// pluginProcess is a running provider plugin. It is implemented by *plugin.Client.
+type pluginProcess interface {
+	Exited() bool
+	Kill()
+}
+
+// launchFunc launches a new provider plugin process and returns a client connected to it.
+type launchFunc func() (pluginProcess, proto.ProviderClient, error)
+
+// poolMember is a single supervised plugin process.
+type poolMember struct {
+	// inFlight is the number of requests in flight. It is protected by the supervisor's lock.
+	inFlight int
+
+	m          sync.Mutex
+	process    pluginProcess
+	client     proto.ProviderClient
+	generation int
+	restarts   int
+}
+
+// supervisor is a proto.ProviderClient that sends requests to a pool of supervised plugin processes. If a process
+// exits, the supervisor restarts it and replays the last Configure request. Requests that have no side effects are
+// then retried on the new process; other requests fail, but later requests go to the new process.
+type supervisor struct {
+	ctx         context.Context
+	launch      launchFunc
+	maxRestarts int
+
+	m         sync.Mutex
+	members   []*poolMember
+	configure *proto.Configure_Request
+}
+
+var _ = proto.ProviderClient((*supervisor)(nil))
+
+func newSupervisor(ctx context.Context, launch launchFunc, opts SupervisorOptions) (*supervisor, error) {
+	poolSize := opts.PoolSize
+	if poolSize <= 0 {
+		poolSize = 1
+	}
+	maxRestarts := opts.MaxRestarts
+	switch {
+	case maxRestarts == 0:
+		maxRestarts = defaultMaxRestarts
+	case maxRestarts < 0:
+		maxRestarts = 0
+	}
+
+	s := &supervisor{ctx: ctx, launch: launch, maxRestarts: maxRestarts}
+	for i := 0; i < poolSize; i++ {
+		process, client, err := launch()
+		if err != nil {
+			s.kill()
+			return nil, err
+		}
+		s.members = append(s.members, &poolMember{process: process, client: client})
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.kill()
+	}()
+
+	return s, nil
+}
+
+// kill kills each plugin process. It takes each member's lock, so a restart that is in progress finishes before its
+// new process is killed, and restart does nothing once the supervisor's context is done.
+func (s *supervisor) kill() {
+	for _, member := range s.members {
+		member.m.Lock()
+		member.process.Kill()
+		member.m.Unlock()
+	}
+}
+
+// acquire picks the pool member with the fewest requests in flight and returns its current client and generation.
+func (s *supervisor) acquire() (*poolMember, proto.ProviderClient, int) {
+	s.m.Lock()
+	member := s.members[0]
+	for _, m := range s.members[1:] {
+		if m.inFlight < member.inFlight {
+			member = m
+		}
+	}
+	member.inFlight++
+	s.m.Unlock()
+
+	member.m.Lock()
+	defer member.m.Unlock()
+	return member, member.client, member.generation
+}
+
+func (s *supervisor) release(member *poolMember) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	member.inFlight--
+}
+
+// exited returns true if err indicates that the member's plugin process is no longer reachable.
+func (s *supervisor) exited(member *poolMember, err error) bool {
+	if status.Code(err) == codes.Unavailable {
+		return true
+	}
+	member.m.Lock()
+	defer member.m.Unlock()
+	return member.process.Exited()
+}
+
+// restart replaces the member's plugin process with a new one and replays the last Configure request. If the process
+// has already been replaced since the given generation, restart does nothing.
+func (s *supervisor) restart(member *poolMember, generation int) error {
+	member.m.Lock()
+	defer member.m.Unlock()
+
+	// The context is checked under the member's lock so that a process is never launched after kill has run.
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if member.generation != generation {
+		return nil
+	}
+	if member.restarts >= s.maxRestarts {
+		return fmt.Errorf("the provider exited unexpectedly and has already been restarted %d times",
+			member.restarts)
+	}
+
+	member.process.Kill()
+	process, client, err := s.launch()
+	if err != nil {
+		return fmt.Errorf("restarting the provider: %w", err)
+	}
+	member.process, member.client = process, client
+	member.generation, member.restarts = member.generation+1, member.restarts+1
+
+	s.m.Lock()
+	configure := s.configure
+	s.m.Unlock()
+	if configure != nil {
+		resp, err := client.Configure(s.ctx, configure)
+		if err != nil {
+			return fmt.Errorf("reconfiguring the provider: %w", err)
+		}
+		if err = unmarshalErrors(resp.Diagnostics); err != nil {
+			return fmt.Errorf("reconfiguring the provider: %w", err)
+		}
+	}
+	return nil
+}
+
+// call sends a request to a pool member. If the member's process has exited, it is restarted, and the request is
+// retried if it is retryable.
+func (s *supervisor) call(retryable bool, f func(client proto.ProviderClient) error) error {
+	member, client, generation := s.acquire()
+	defer s.release(member)
+
+	err := f(client)
+	if err == nil || !s.exited(member, err) {
+		return err
+	}
+	if restartErr := s.restart(member, generation); restartErr != nil {
+		return fmt.Errorf("%w (%v)", err, restartErr)
+	}
+	if !retryable {
+		return err
+	}
+
+	member.m.Lock()
+	client = member.client
+	member.m.Unlock()
+	return f(client)
+}
+
+func (s *supervisor) GetSchema(ctx context.Context, in *proto.GetProviderSchema_Request,
+	opts ...grpc.CallOption) (resp *proto.GetProviderSchema_Response, err error) {
+
+	err = s.call(true, func(client proto.ProviderClient) (err error) {
+		resp, err = client.GetSchema(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (s *supervisor) PrepareProviderConfig(ctx context.Context, in *proto.PrepareProviderConfig_Request,
+	opts ...grpc.CallOption) (resp *proto.PrepareProviderConfig_Response, err error) {
+
+	err = s.call(true, func(client proto.ProviderClient) (err error) {
+		resp, err = client.PrepareProviderConfig(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (s *supervisor) ValidateResourceTypeConfig(ctx context.Context, in *proto.ValidateResourceTypeConfig_Request,
+	opts ...grpc.CallOption) (resp *proto.ValidateResourceTypeConfig_Response, err error) {
+
+	err = s.call(true, func(client proto.ProviderClient) (err error) {
+		resp, err = client.ValidateResourceTypeConfig(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (s *supervisor) ValidateDataSourceConfig(ctx context.Context, in *proto.ValidateDataSourceConfig_Request,
+	opts ...grpc.CallOption) (resp *proto.ValidateDataSourceConfig_Response, err error) {
+
+	err = s.call(true, func(client proto.ProviderClient) (err error) {
+		resp, err = client.ValidateDataSourceConfig(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (s *supervisor) UpgradeResourceState(ctx context.Context, in *proto.UpgradeResourceState_Request,
+	opts ...grpc.CallOption) (resp *proto.UpgradeResourceState_Response, err error) {
+
+	err = s.call(true, func(client proto.ProviderClient) (err error) {
+		resp, err = client.UpgradeResourceState(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+// Configure records the request so that it can be replayed if a process is restarted and then configures each plugin
+// process in the pool.
+func (s *supervisor) Configure(ctx context.Context, in *proto.Configure_Request,
+	opts ...grpc.CallOption) (*proto.Configure_Response, error) {
+
+	// The request is recorded first so that a process that is restarted concurrently is given this configuration.
+	s.m.Lock()
+	s.configure = in
+	s.m.Unlock()
+
+	var result *proto.Configure_Response
+	for _, member := range s.members {
+		member.m.Lock()
+		client, generation := member.client, member.generation
+		member.m.Unlock()
+
+		resp, err := client.Configure(ctx, in, opts...)
+		if err != nil && s.exited(member, err) {
+			// Restarting the process replays the request, and fails if the replay reports errors.
+			if restartErr := s.restart(member, generation); restartErr != nil {
+				return nil, fmt.Errorf("%w (%v)", err, restartErr)
+			}
+			resp, err = &proto.Configure_Response{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if result == nil || unmarshalErrors(resp.Diagnostics) != nil {
+			result = resp
+		}
+	}
+	return result, nil
+}
+
+func (s *supervisor) ReadResource(ctx context.Context, in *proto.ReadResource_Request,
+	opts ...grpc.CallOption) (resp *proto.ReadResource_Response, err error) {
+
+	err = s.call(true, func(client proto.ProviderClient) (err error) {
+		resp, err = client.ReadResource(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (s *supervisor) PlanResourceChange(ctx context.Context, in *proto.PlanResourceChange_Request,
+	opts ...grpc.CallOption) (resp *proto.PlanResourceChange_Response, err error) {
+
+	err = s.call(true, func(client proto.ProviderClient) (err error) {
+		resp, err = client.PlanResourceChange(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+// ApplyResourceChange is not retried: the process may have exited after the change was made.
+func (s *supervisor) ApplyResourceChange(ctx context.Context, in *proto.ApplyResourceChange_Request,
+	opts ...grpc.CallOption) (resp *proto.ApplyResourceChange_Response, err error) {
+
+	err = s.call(false, func(client proto.ProviderClient) (err error) {
+		resp, err = client.ApplyResourceChange(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (s *supervisor) ImportResourceState(ctx context.Context, in *proto.ImportResourceState_Request,
+	opts ...grpc.CallOption) (resp *proto.ImportResourceState_Response, err error) {
+
+	err = s.call(true, func(client proto.ProviderClient) (err error) {
+		resp, err = client.ImportResourceState(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+func (s *supervisor) ReadDataSource(ctx context.Context, in *proto.ReadDataSource_Request,
+	opts ...grpc.CallOption) (resp *proto.ReadDataSource_Response, err error) {
+
+	err = s.call(true, func(client proto.ProviderClient) (err error) {
+		resp, err = client.ReadDataSource(ctx, in, opts...)
+		return err
+	})
+	return resp, err
+}
+
+// Stop stops each plugin process in the pool.
+func (s *supervisor) Stop(ctx context.Context, in *proto.Stop_Request,
+	opts ...grpc.CallOption) (*proto.Stop_Response, error) {
+
+	var result *proto.Stop_Response
+	for _, member := range s.members {
+		member.m.Lock()
+		client := member.client
+		member.m.Unlock()
+
+		resp, err := client.Stop(ctx, in, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil || resp.Error != "" {
+			result = resp
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,182 @@
+package tfplugin5
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/tfplugin5/proto"
+)
+
+// fakePlugin is a plugin process whose client fails with a transport error once it has been killed.
+type fakePlugin struct {
+	proto.ProviderClient
+
+	dead       bool
+	configured *proto.Configure_Request
+	configures int
+	applies    int
+}
+
+func (p *fakePlugin) Exited() bool { return p.dead }
+
+func (p *fakePlugin) Kill() { p.dead = true }
+
+func (p *fakePlugin) Configure(_ context.Context, in *proto.Configure_Request,
+	_ ...grpc.CallOption) (*proto.Configure_Response, error) {
+
+	if p.dead {
+		return nil, status.Error(codes.Unavailable, "transport is closing")
+	}
+	p.configured, p.configures = in, p.configures+1
+	return &proto.Configure_Response{}, nil
+}
+
+func (p *fakePlugin) ReadResource(_ context.Context, in *proto.ReadResource_Request,
+	_ ...grpc.CallOption) (*proto.ReadResource_Response, error) {
+
+	if p.dead {
+		return nil, status.Error(codes.Unavailable, "transport is closing")
+	}
+	return &proto.ReadResource_Response{NewState: in.CurrentState}, nil
+}
+
+func (p *fakePlugin) ApplyResourceChange(_ context.Context, in *proto.ApplyResourceChange_Request,
+	_ ...grpc.CallOption) (*proto.ApplyResourceChange_Response, error) {
+
+	if p.dead {
+		return nil, status.Error(codes.Unavailable, "transport is closing")
+	}
+	p.applies++
+	return &proto.ApplyResourceChange_Response{NewState: in.PlannedState}, nil
+}
+
+func newTestSupervisor(ctx context.Context, t *testing.T, opts SupervisorOptions) (*supervisor, *[]*fakePlugin) {
+	var plugins []*fakePlugin
+	s, err := newSupervisor(ctx, func() (pluginProcess, proto.ProviderClient, error) {
+		p := &fakePlugin{}
+		plugins = append(plugins, p)
+		return p, p, nil
+	}, opts)
+	require.NoError(t, err)
+	return s, &plugins
+}
+
+func TestSupervisorRestart(t *testing.T) {
+	ctx := context.Background()
+	s, plugins := newTestSupervisor(ctx, t, SupervisorOptions{MaxRestarts: 2})
+	require.Len(t, *plugins, 1)
+
+	configure := &proto.Configure_Request{TerraformVersion: "1.0.0"}
+	_, err := s.Configure(ctx, configure)
+	require.NoError(t, err)
+
+	// Reads are retried on a new, reconfigured process.
+	(*plugins)[0].Kill()
+	state := &proto.DynamicValue{Json: []byte(`{}`)}
+	resp, err := s.ReadResource(ctx, &proto.ReadResource_Request{CurrentState: state})
+	require.NoError(t, err)
+	assert.Equal(t, state, resp.NewState)
+	require.Len(t, *plugins, 2)
+	assert.Same(t, configure, (*plugins)[1].configured)
+
+	// Applies are not retried, but later requests use the new process.
+	(*plugins)[1].Kill()
+	_, err = s.ApplyResourceChange(ctx, &proto.ApplyResourceChange_Request{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	require.Len(t, *plugins, 3)
+	assert.Equal(t, 0, (*plugins)[2].applies)
+	_, err = s.ApplyResourceChange(ctx, &proto.ApplyResourceChange_Request{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, (*plugins)[2].applies)
+
+	// Processes are restarted at most MaxRestarts times.
+	(*plugins)[2].Kill()
+	_, err = s.ReadResource(ctx, &proto.ReadResource_Request{CurrentState: state})
+	assert.ErrorContains(t, err, "already been restarted 2 times")
+	assert.Len(t, *plugins, 3)
+}
+
+func TestSupervisorPool(t *testing.T) {
+	ctx := context.Background()
+	s, plugins := newTestSupervisor(ctx, t, SupervisorOptions{PoolSize: 3, MaxRestarts: -1})
+	require.Len(t, *plugins, 3)
+
+	// Configure is sent to every process.
+	configure := &proto.Configure_Request{TerraformVersion: "1.0.0"}
+	_, err := s.Configure(ctx, configure)
+	require.NoError(t, err)
+	for _, p := range *plugins {
+		assert.Same(t, configure, p.configured)
+	}
+
+	// Requests go to the process with the fewest requests in flight.
+	s.members[0].inFlight, s.members[2].inFlight = 2, 1
+	_, err = s.ApplyResourceChange(ctx, &proto.ApplyResourceChange_Request{})
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 0}, []int{(*plugins)[0].applies, (*plugins)[1].applies, (*plugins)[2].applies})
+
+	// Restarts are disabled.
+	s.members[0].inFlight, s.members[2].inFlight = 0, 0
+	(*plugins)[0].Kill()
+	_, err = s.ReadResource(ctx, &proto.ReadResource_Request{})
+	assert.ErrorContains(t, err, "already been restarted 0 times")
+}
+
+func TestSupervisorConfigureRestart(t *testing.T) {
+	ctx := context.Background()
+	s, plugins := newTestSupervisor(ctx, t, SupervisorOptions{PoolSize: 2})
+
+	// A process that has exited is restarted with the new configuration, and is configured only once.
+	(*plugins)[1].Kill()
+	configure := &proto.Configure_Request{TerraformVersion: "1.0.0"}
+	_, err := s.Configure(ctx, configure)
+	require.NoError(t, err)
+	require.Len(t, *plugins, 3)
+	assert.Same(t, configure, (*plugins)[0].configured)
+	assert.Same(t, configure, (*plugins)[2].configured)
+	assert.Equal(t, 1, (*plugins)[2].configures)
+	assert.Same(t, configure, s.configure)
+}
+
+func TestSupervisorCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s, plugins := newTestSupervisor(ctx, t, SupervisorOptions{})
+
+	// Once the context is done, processes are killed and no longer restarted.
+	cancel()
+	assert.Eventually(t, func() bool {
+		s.members[0].m.Lock()
+		defer s.members[0].m.Unlock()
+		return s.members[0].process.Exited()
+	}, time.Second, time.Millisecond)
+	_, err := s.ReadResource(context.Background(), &proto.ReadResource_Request{})
+	assert.ErrorContains(t, err, context.Canceled.Error())
+	assert.Len(t, *plugins, 1)
+}
+
+func TestStartSupervisedProvider(t *testing.T) {
+	testProviderPath, err := exec.LookPath("pulumi-terraform-bridge-test-provider")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := StartSupervisedProvider(ctx, testProviderPath, "", SupervisorOptions{})
+	require.NoError(t, err)
+
+	// Kill the plugin process out from under the provider and ensure that it is restarted.
+	s := p.(*provider).client.(*supervisor)
+	s.members[0].process.Kill()
+
+	_, errs := p.Validate(p.NewResourceConfig(map[string]interface{}{}))
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, s.members[0].restarts)
+}